@@ -15,14 +15,14 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/comment/add_like/{comment_id}": {
+        "/admin/audit": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Increment the like count for a comment",
+                "description": "List role and verified badge changes, newest first",
                 "consumes": [
                     "application/json"
                 ],
@@ -30,29 +30,34 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Comments"
+                    "Admin"
                 ],
-                "summary": "Add a like to a comment",
+                "summary": "List audit log",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID of the comment to like",
-                        "name": "comment_id",
-                        "in": "path",
-                        "required": true
+                        "description": "Only changes to this user",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Entries per page, at most 100",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Message"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/models.Error"
+                            "$ref": "#/definitions/user.AuditEntries"
                         }
                     },
                     "500": {
@@ -64,14 +69,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/comment/delete/{id}": {
-            "delete": {
+        "/admin/change_profile_image_by_id/{user_id}": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Remove a comment by ID",
+                "description": "Update the profile image of a user",
                 "consumes": [
                     "application/json"
                 ],
@@ -79,23 +84,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Comments"
+                    "Admin"
                 ],
-                "summary": "Delete a comment",
+                "summary": "Change User Profile Image",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID of the comment to delete",
-                        "name": "id",
+                        "description": "User ID",
+                        "name": "user_id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Change profile image",
+                        "name": "ChangeProfileImageById",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.URL"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Message"
+                            "$ref": "#/definitions/models.Void"
                         }
                     },
                     "400": {
@@ -113,14 +127,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/comment/get/{id}": {
-            "get": {
+        "/admin/create": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve a comment by ID",
+                "description": "Create a new user",
                 "consumes": [
                     "application/json"
                 ],
@@ -128,23 +142,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Comments"
+                    "Admin"
                 ],
-                "summary": "Get a specific comment",
+                "summary": "Create User",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "ID of the comment to retrieve",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Create user",
+                        "name": "Create",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.CommentRes"
+                            "$ref": "#/definitions/models.UserResponse"
                         }
                     },
                     "400": {
@@ -162,14 +178,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/comment/get_all/{tweet_id}": {
+        "/admin/dead_letters/{type}": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve all comments for a specific tweet",
+                "description": "List commands that were dead-lettered after failing or running out of retries",
                 "consumes": [
                     "application/json"
                 ],
@@ -177,23 +193,29 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Comments"
+                    "Admin"
                 ],
-                "summary": "Get all comments for a tweet",
+                "summary": "List dead letters",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID of the tweet to retrieve comments for",
-                        "name": "tweet_id",
+                        "description": "Command type, e.g. POST_TWEET",
+                        "name": "type",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of messages",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Comments"
+                            "$ref": "#/definitions/tweet.DeadLetters"
                         }
                     },
                     "400": {
@@ -211,14 +233,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/comment/get_user/{user_id}": {
-            "get": {
+        "/admin/dead_letters/{type}/replay": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve all comments made by a specific user",
+                "description": "Send a dead-lettered command back to its queue. Without an id every dead letter of the type is replayed",
                 "consumes": [
                     "application/json"
                 ],
@@ -226,29 +248,29 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Comments"
+                    "Admin"
                 ],
-                "summary": "Get all comments by a user",
+                "summary": "Replay dead letters",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID of the user to retrieve comments for",
-                        "name": "user_id",
+                        "description": "Command type, e.g. POST_TWEET",
+                        "name": "type",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Message ID",
+                        "name": "id",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Comments"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/models.Error"
+                            "$ref": "#/definitions/tweet.ReplayRes"
                         }
                     },
                     "500": {
@@ -260,14 +282,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/comment/post": {
-            "post": {
+        "/admin/delete/{user_id}": {
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a new comment for a tweet",
+                "description": "Delete a user account",
                 "consumes": [
                     "application/json"
                 ],
@@ -275,25 +297,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Comments"
+                    "Admin"
                 ],
-                "summary": "Post a new comment",
+                "summary": "Delete User",
                 "parameters": [
                     {
-                        "description": "Comment to be created",
-                        "name": "comment",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.Comment"
-                        }
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.CommentRes"
+                            "$ref": "#/definitions/models.Message"
                         }
                     },
                     "400": {
@@ -311,14 +331,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/comment/remove_like/{comment_id}": {
-            "delete": {
+        "/admin/get_profile_by_id/{user_id}": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Decrement the like count for a comment",
+                "description": "Retrieve the profile of a user",
                 "consumes": [
                     "application/json"
                 ],
@@ -326,14 +346,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Comments"
+                    "Admin"
                 ],
-                "summary": "Remove a like from a comment",
+                "summary": "Get User Profile",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID of the comment to unlike",
-                        "name": "comment_id",
+                        "description": "User ID",
+                        "name": "user_id",
                         "in": "path",
                         "required": true
                     }
@@ -342,7 +362,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Message"
+                            "$ref": "#/definitions/models.GetProfileResponse"
                         }
                     },
                     "400": {
@@ -360,14 +380,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/comment/update": {
+        "/admin/update_profile_by_id/{user_id}": {
             "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update the content of a comment",
+                "description": "Update user profile details. The username is changed with /user/change_username",
                 "consumes": [
                     "application/json"
                 ],
@@ -375,17 +395,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Comments"
+                    "Admin"
                 ],
-                "summary": "Update an existing comment",
+                "summary": "Update User Profile",
                 "parameters": [
                     {
-                        "description": "Updated comment details",
-                        "name": "comment",
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update user profile",
+                        "name": "UpdateProfileById",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.UpdateAComment"
+                            "$ref": "#/definitions/models.UpdateProfileRequest"
                         }
                     }
                 ],
@@ -393,7 +420,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.CommentRes"
+                            "$ref": "#/definitions/models.UserResponse"
                         }
                     },
                     "400": {
@@ -411,14 +438,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/like/add": {
-            "post": {
+        "/admin/users/{user_id}/role": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Add a like to a tweet by a user",
+                "description": "Promote or demote a user. Admins manage roles up to their own; the user has to sign in again",
                 "consumes": [
                     "application/json"
                 ],
@@ -426,17 +453,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Like"
+                    "Admin"
                 ],
-                "summary": "Add a like to a tweet",
+                "summary": "Set user role",
                 "parameters": [
                     {
-                        "description": "Add like request",
-                        "name": "AddLike",
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New role",
+                        "name": "SetRole",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.LikeReq"
+                            "$ref": "#/definitions/models.SetRoleRequest"
                         }
                     }
                 ],
@@ -444,7 +478,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.LikeRes"
+                            "$ref": "#/definitions/models.UserResponse"
                         }
                     },
                     "400": {
@@ -453,8 +487,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Error"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/models.Error"
                         }
@@ -468,14 +502,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/like/delete": {
-            "delete": {
+        "/admin/users/{user_id}/verified": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Remove a like from a tweet by a user",
+                "description": "Grant or take a user's verified badge. The change and its reason are kept in the audit log",
                 "consumes": [
                     "application/json"
                 ],
@@ -483,17 +517,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Like"
+                    "Admin"
                 ],
-                "summary": "Delete a like from a tweet",
+                "summary": "Set verified badge",
                 "parameters": [
                     {
-                        "description": "Delete like request",
-                        "name": "DeleteLIke",
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Badge",
+                        "name": "SetVerified",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.LikeReq"
+                            "$ref": "#/definitions/models.SetVerifiedRequest"
                         }
                     }
                 ],
@@ -501,7 +542,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.LikeRes"
+                            "$ref": "#/definitions/models.UserResponse"
                         }
                     },
                     "400": {
@@ -510,8 +551,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Error"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/models.Error"
                         }
@@ -525,14 +566,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/like/get_count/{tweet_id}": {
+        "/commands/{id}": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve the number of likes for a specific tweet",
+                "description": "Report whether an asynchronous write is pending, succeeded or failed, with the resource it created",
                 "consumes": [
                     "application/json"
                 ],
@@ -540,14 +581,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Like"
+                    "Command"
                 ],
-                "summary": "Get the count of likes for a tweet",
+                "summary": "Get command status",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Tweet ID",
-                        "name": "tweet_id",
+                        "description": "Command ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
@@ -556,13 +597,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Count"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/models.Error"
+                            "$ref": "#/definitions/service.Command"
                         }
                     },
                     "404": {
@@ -580,14 +615,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/like/get_most": {
+        "/comment/add_like/{comment_id}": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve tweets with the highest number of likes",
+                "description": "Increment the like count for a comment",
                 "consumes": [
                     "application/json"
                 ],
@@ -595,14 +630,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Like"
+                    "Comments"
+                ],
+                "summary": "Add a like to a comment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the comment to like",
+                        "name": "comment_id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get the most liked tweets",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Tweet"
+                            "$ref": "#/definitions/models.Message"
                         }
                     },
                     "400": {
@@ -611,12 +655,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Error"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/models.Error"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -626,14 +664,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/like/get_user": {
-            "get": {
+        "/comment/delete/{id}": {
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve all tweets liked by a specific user",
+                "description": "Remove a comment by ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -641,22 +679,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Like"
+                    "Comments"
                 ],
-                "summary": "Get all likes by a user",
+                "summary": "Delete a comment",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
-                        "name": "user_id",
-                        "in": "query"
+                        "description": "ID of the comment to delete",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.TweetTitles"
+                            "$ref": "#/definitions/models.Message"
                         }
                     },
                     "400": {
@@ -665,12 +704,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Error"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/models.Error"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -680,14 +713,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/tweet/add": {
-            "post": {
+        "/comment/get/{id}": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a new tweet",
+                "description": "Retrieve a comment by ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -695,25 +728,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Tweet"
+                    "Comments"
                 ],
-                "summary": "PostTweet Tweets",
+                "summary": "Get a specific comment",
                 "parameters": [
                     {
-                        "description": "Post tweet",
-                        "name": "PostTweet",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.Tweet"
-                        }
+                        "type": "string",
+                        "description": "ID of the comment to retrieve",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.TweetResponse"
+                            "$ref": "#/definitions/models.CommentRes"
                         }
                     },
                     "400": {
@@ -731,14 +762,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/tweet/add_image": {
-            "post": {
+        "/comment/get_all/{tweet_id}": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Add an image to a tweet",
+                "description": "Retrieve all comments for a specific tweet",
                 "consumes": [
                     "application/json"
                 ],
@@ -746,25 +777,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Tweet"
+                    "Comments"
                 ],
-                "summary": "AddImageToTweet Tweets",
+                "summary": "Get all comments for a tweet",
                 "parameters": [
                     {
-                        "description": "Add image to tweet",
-                        "name": "AddImageToTweet",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.Url"
-                        }
+                        "type": "string",
+                        "description": "ID of the tweet to retrieve comments for",
+                        "name": "tweet_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Message"
+                            "$ref": "#/definitions/models.Comments"
                         }
                     },
                     "400": {
@@ -782,14 +811,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/tweet/add_up": {
-            "put": {
+        "/comment/get_user": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update an existing tweet",
+                "description": "Retrieve all comments made by a specific user",
                 "consumes": [
                     "application/json"
                 ],
@@ -797,25 +826,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Tweet"
-                ],
-                "summary": "UpdateTweet Tweets",
-                "parameters": [
-                    {
-                        "description": "Update tweet",
-                        "name": "UpdateTweet",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.UpdateATweet"
-                        }
-                    }
+                    "Comments"
                 ],
+                "summary": "Get all comments by a user",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.TweetResponse"
+                            "$ref": "#/definitions/models.Comments"
                         }
                     },
                     "400": {
@@ -833,14 +851,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/tweet/get_all": {
-            "get": {
+        "/comment/post": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get a list of all tweets with optional filters",
+                "description": "Create a new comment for a tweet",
                 "consumes": [
                     "application/json"
                 ],
@@ -848,40 +866,31 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Tweet"
+                    "Comments"
                 ],
-                "summary": "GetAllTweets Tweets",
+                "summary": "Post a new comment",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Limit of tweets",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Offset of tweets",
-                        "name": "offset",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Hashtag filter",
-                        "name": "hashtag",
-                        "in": "query"
+                        "description": "Comment to be created",
+                        "name": "comment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Comment"
+                        }
                     },
                     {
                         "type": "string",
-                        "description": "Title filter",
-                        "name": "title",
-                        "in": "query"
+                        "description": "Key that makes retries of this request apply once",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "202": {
+                        "description": "Accepted",
                         "schema": {
-                            "$ref": "#/definitions/models.Tweets"
+                            "$ref": "#/definitions/models.CommandAccepted"
                         }
                     },
                     "400": {
@@ -899,14 +908,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/tweet/get_new": {
-            "get": {
+        "/comment/remove_like/{comment_id}": {
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get new tweets for a specific user",
+                "description": "Decrement the like count for a comment",
                 "consumes": [
                     "application/json"
                 ],
@@ -914,22 +923,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Tweet"
+                    "Comments"
                 ],
-                "summary": "GetNewTweets Tweets",
+                "summary": "Remove a like from a comment",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
-                        "name": "user_id",
-                        "in": "query"
+                        "description": "ID of the comment to unlike",
+                        "name": "comment_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Tweets"
+                            "$ref": "#/definitions/models.Message"
                         }
                     },
                     "400": {
@@ -947,14 +957,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/tweet/get_tt/{id}": {
-            "get": {
+        "/comment/update": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get details of a specific tweet",
+                "description": "Update the content of a comment",
                 "consumes": [
                     "application/json"
                 ],
@@ -962,23 +972,31 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Tweet"
+                    "Comments"
                 ],
-                "summary": "GetTweet Tweets",
+                "summary": "Update an existing comment",
                 "parameters": [
+                    {
+                        "description": "Updated comment details",
+                        "name": "comment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateAComment"
+                        }
+                    },
                     {
                         "type": "string",
-                        "description": "Tweet ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Key that makes retries of this request apply once",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "202": {
+                        "description": "Accepted",
                         "schema": {
-                            "$ref": "#/definitions/models.TweetResponse"
+                            "$ref": "#/definitions/models.CommandAccepted"
                         }
                     },
                     "400": {
@@ -996,14 +1014,34 @@ const docTemplate = `{
                 }
             }
         },
-        "/tweet/recommend": {
+        "/healthz": {
             "get": {
+                "description": "Tell whether the gateway process is up. It does not look at the services behind it",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Liveness",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Readiness"
+                        }
+                    }
+                }
+            }
+        },
+        "/like/add": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get tweet recommendations for a specific user",
+                "description": "Add a like to a tweet by a user",
                 "consumes": [
                     "application/json"
                 ],
@@ -1011,22 +1049,31 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Tweet"
+                    "Like"
                 ],
-                "summary": "RecommendTweets Tweets",
+                "summary": "Add a like to a tweet",
                 "parameters": [
+                    {
+                        "description": "Add like request",
+                        "name": "AddLike",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.LikeReq"
+                        }
+                    },
                     {
                         "type": "string",
-                        "description": "User ID",
-                        "name": "user_id",
-                        "in": "query"
+                        "description": "Key that makes retries of this request apply once",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "202": {
+                        "description": "Accepted",
                         "schema": {
-                            "$ref": "#/definitions/models.Tweets"
+                            "$ref": "#/definitions/models.CommandAccepted"
                         }
                     },
                     "400": {
@@ -1035,6 +1082,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Error"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1044,14 +1103,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/tweet/user": {
-            "get": {
+        "/like/delete/{tweet_id}": {
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get all tweets for a specific user",
+                "description": "Remove a like from a tweet by a user",
                 "consumes": [
                     "application/json"
                 ],
@@ -1059,22 +1118,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Tweet"
+                    "Like"
                 ],
-                "summary": "UserTweets Tweets",
+                "summary": "Delete a like from a tweet",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
-                        "name": "user_id",
-                        "in": "query"
+                        "description": "Tweet ID",
+                        "name": "tweet_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Tweets"
+                            "$ref": "#/definitions/models.LikeRes"
                         }
                     },
                     "400": {
@@ -1083,6 +1143,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Error"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1092,14 +1164,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/user/change_password": {
-            "put": {
+        "/like/get_count/{tweet_id}": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Change the password of a user",
+                "description": "Retrieve the number of likes for a specific tweet",
                 "consumes": [
                     "application/json"
                 ],
@@ -1107,25 +1179,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Like"
                 ],
-                "summary": "Change User Password",
+                "summary": "Get the count of likes for a tweet",
                 "parameters": [
                     {
-                        "description": "Change password",
-                        "name": "ChangePassword",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.ChangePasswordRequest"
-                        }
-                    }
-                ],
-                "responses": {
+                        "type": "string",
+                        "description": "Tweet ID",
+                        "name": "tweet_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.ChangePasswordResponse"
+                            "$ref": "#/definitions/models.Count"
                         }
                     },
                     "400": {
@@ -1134,6 +1204,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Error"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1143,14 +1225,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/user/change_profile_image": {
-            "put": {
+        "/like/get_most": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update the profile image of a user",
+                "description": "Retrieve tweets with the highest number of likes",
                 "consumes": [
                     "application/json"
                 ],
@@ -1158,25 +1240,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User"
-                ],
-                "summary": "Change User Profile Image",
-                "parameters": [
-                    {
-                        "description": "Change profile image",
-                        "name": "ChangeProfileImage",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.URL"
-                        }
-                    }
+                    "Like"
                 ],
+                "summary": "Get the most liked tweets",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Void"
+                            "$ref": "#/definitions/models.Tweet"
                         }
                     },
                     "400": {
@@ -1185,6 +1256,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Error"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1194,14 +1277,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/user/create": {
-            "post": {
+        "/like/get_user": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a new user",
+                "description": "Retrieve all tweets liked by a specific user",
                 "consumes": [
                     "application/json"
                 ],
@@ -1209,25 +1292,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User"
-                ],
-                "summary": "Create User",
-                "parameters": [
-                    {
-                        "description": "Create user",
-                        "name": "Create",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.CreateRequest"
-                        }
-                    }
+                    "Like"
                 ],
+                "summary": "Get all likes by a user",
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.UserResponse"
+                            "$ref": "#/definitions/models.TweetTitles"
                         }
                     },
                     "400": {
@@ -1236,6 +1308,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Error"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1245,63 +1329,40 @@ const docTemplate = `{
                 }
             }
         },
-        "/user/delete/{user_id}": {
-            "delete": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Delete a user account",
-                "consumes": [
-                    "application/json"
-                ],
+        "/readyz": {
+            "get": {
+                "description": "Tell whether the gateway can serve requests: auth-service and twitt-service report serving, and Redis and RabbitMQ are reachable",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "User"
-                ],
-                "summary": "Delete User",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "User ID",
-                        "name": "UserId",
-                        "in": "path",
-                        "required": true
-                    }
+                    "Health"
                 ],
+                "summary": "Readiness",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Void"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/models.Error"
+                            "$ref": "#/definitions/models.Readiness"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "503": {
+                        "description": "Service Unavailable",
                         "schema": {
-                            "$ref": "#/definitions/models.Error"
+                            "$ref": "#/definitions/models.Readiness"
                         }
                     }
                 }
             }
         },
-        "/user/fetch_users": {
-            "get": {
+        "/tweet/add": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve a list of users with filtering options",
+                "description": "Create a new tweet",
                 "consumes": [
                     "application/json"
                 ],
@@ -1309,40 +1370,31 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Tweet"
                 ],
-                "summary": "Fetch Users",
+                "summary": "PostTweet Tweets",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "User role",
-                        "name": "role",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Page number",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Number of users per page",
-                        "name": "limit",
-                        "in": "query"
+                        "description": "Post tweet",
+                        "name": "PostTweet",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Tweet"
+                        }
                     },
                     {
                         "type": "string",
-                        "description": "User name",
-                        "name": "name",
-                        "in": "query"
+                        "description": "Key that makes retries of this request apply once",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "202": {
+                        "description": "Accepted",
                         "schema": {
-                            "$ref": "#/definitions/models.UserResponses"
+                            "$ref": "#/definitions/models.CommandAccepted"
                         }
                     },
                     "400": {
@@ -1351,6 +1403,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Error"
                         }
                     },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1360,14 +1418,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/user/follow": {
+        "/tweet/add_image": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Follow another user",
+                "description": "Add an image to a tweet",
                 "consumes": [
                     "application/json"
                 ],
@@ -1375,17 +1433,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Tweet"
                 ],
-                "summary": "Follow User",
+                "summary": "AddImageToTweet Tweets",
                 "parameters": [
                     {
-                        "description": "post user",
-                        "name": "Follow",
+                        "description": "Add image to tweet",
+                        "name": "AddImageToTweet",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.FollowReq"
+                            "$ref": "#/definitions/models.Url"
                         }
                     }
                 ],
@@ -1393,7 +1451,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.FollowRes"
+                            "$ref": "#/definitions/models.Message"
                         }
                     },
                     "400": {
@@ -1402,6 +1460,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Error"
                         }
                     },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1411,14 +1475,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/user/get_profile/{user_id}": {
-            "get": {
+        "/tweet/add_up": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve the profile of a user",
+                "description": "Update an existing tweet",
                 "consumes": [
                     "application/json"
                 ],
@@ -1426,23 +1490,31 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Tweet"
                 ],
-                "summary": "Get User Profile",
+                "summary": "UpdateTweet Tweets",
                 "parameters": [
+                    {
+                        "description": "Update tweet",
+                        "name": "UpdateTweet",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateATweet"
+                        }
+                    },
                     {
                         "type": "string",
-                        "description": "User ID",
-                        "name": "UserId",
-                        "in": "path",
-                        "required": true
+                        "description": "Key that makes retries of this request apply once",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "202": {
+                        "description": "Accepted",
                         "schema": {
-                            "$ref": "#/definitions/models.GetProfileResponse"
+                            "$ref": "#/definitions/models.CommandAccepted"
                         }
                     },
                     "400": {
@@ -1451,6 +1523,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Error"
                         }
                     },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1460,14 +1538,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/user/get_user_followers/{user_id}": {
+        "/tweet/get_all": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve a list of followers for a specific user",
+                "description": "Get a list of all tweets with optional filters",
                 "consumes": [
                     "application/json"
                 ],
@@ -1475,23 +1553,40 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Tweet"
                 ],
-                "summary": "Get User Followers",
+                "summary": "GetAllTweets Tweets",
                 "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Limit of tweets",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset of tweets",
+                        "name": "offset",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
-                        "description": "User ID",
-                        "name": "UserId",
-                        "in": "path",
-                        "required": true
+                        "description": "Hashtag filter",
+                        "name": "hashtag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Title filter",
+                        "name": "title",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Count"
+                            "$ref": "#/definitions/models.Tweets"
                         }
                     },
                     "400": {
@@ -1500,6 +1595,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Error"
                         }
                     },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1509,14 +1610,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/user/get_user_follows/{user_id}": {
+        "/tweet/get_new": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve a list of users that a specific user is following",
+                "description": "Get new tweets for a specific user",
                 "consumes": [
                     "application/json"
                 ],
@@ -1524,23 +1625,22 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Tweet"
                 ],
-                "summary": "Get User Follows",
+                "summary": "GetNewTweets Tweets",
                 "parameters": [
                     {
                         "type": "string",
                         "description": "User ID",
-                        "name": "UserId",
-                        "in": "path",
-                        "required": true
+                        "name": "user_id",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Count"
+                            "$ref": "#/definitions/models.Tweets"
                         }
                     },
                     "400": {
@@ -1549,6 +1649,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Error"
                         }
                     },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1558,14 +1664,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/user/list_of_followers/{user_id}": {
+        "/tweet/get_tt/{id}": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve the list of followers for a user",
+                "description": "Get details of a specific tweet",
                 "consumes": [
                     "application/json"
                 ],
@@ -1573,14 +1679,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Tweet"
                 ],
-                "summary": "List of Followers",
+                "summary": "GetTweet Tweets",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
-                        "name": "UserId",
+                        "description": "Tweet ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
@@ -1589,7 +1695,64 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Followers"
+                            "$ref": "#/definitions/models.TweetResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/tweet/re_tweet": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retweet a tweet by a user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tweet"
+                ],
+                "summary": "ReTweet Tweets",
+                "parameters": [
+                    {
+                        "description": "Post retweet",
+                        "name": "ReTweet",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/tweet.ReTweetReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/tweet.TweetResponse"
                         }
                     },
                     "400": {
@@ -1598,6 +1761,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Error"
                         }
                     },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1607,14 +1776,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/user/list_of_following/{user_id}": {
+        "/tweet/recommend": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve the list of users that a specific user is following",
+                "description": "Get tweet recommendations for a specific user",
                 "consumes": [
                     "application/json"
                 ],
@@ -1622,14 +1791,22 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Tweet"
+                ],
+                "summary": "RecommendTweets Tweets",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "query"
+                    }
                 ],
-                "summary": "List of Following Users",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Followings"
+                            "$ref": "#/definitions/models.Tweets"
                         }
                     },
                     "400": {
@@ -1638,6 +1815,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Error"
                         }
                     },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1647,14 +1830,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/user/most_popular": {
+        "/tweet/user": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve the most popular user based on criteria",
+                "description": "Get all tweets for a specific user",
                 "consumes": [
                     "application/json"
                 ],
@@ -1662,14 +1845,22 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Tweet"
+                ],
+                "summary": "UserTweets Tweets",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Most Popular User",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.UserResponse"
+                            "$ref": "#/definitions/models.Tweets"
                         }
                     },
                     "400": {
@@ -1678,6 +1869,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Error"
                         }
                     },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1687,14 +1884,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/user/unfollow": {
+        "/user/block/{user_id}": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Unfollow a user",
+                "description": "Block a user. Follows between you are removed and neither can follow the other",
                 "consumes": [
                     "application/json"
                 ],
@@ -1704,15 +1901,99 @@ const docTemplate = `{
                 "tags": [
                     "User"
                 ],
-                "summary": "Unfollow User",
+                "summary": "Block User",
                 "parameters": [
                     {
-                        "description": "put user",
-                        "name": "Unfollow",
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Message"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Unblock a user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Unblock User",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Message"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/change_password": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Change the password of a user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Change User Password",
+                "parameters": [
+                    {
+                        "description": "Change password",
+                        "name": "ChangePassword",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.FollowReq"
+                            "$ref": "#/definitions/models.ChangePasswordRequest"
                         }
                     }
                 ],
@@ -1720,7 +2001,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.DFollowRes"
+                            "$ref": "#/definitions/models.ChangePasswordResponse"
                         }
                     },
                     "400": {
@@ -1738,14 +2019,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/user/update_profile": {
+        "/user/change_profile_image": {
             "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update user profile details",
+                "description": "Update the profile image of a user",
                 "consumes": [
                     "application/json"
                 ],
@@ -1755,15 +2036,15 @@ const docTemplate = `{
                 "tags": [
                     "User"
                 ],
-                "summary": "Update User Profile",
+                "summary": "Change User Profile Image",
                 "parameters": [
                     {
-                        "description": "Update user profile",
-                        "name": "UpdateProfile",
+                        "description": "Change profile image",
+                        "name": "ChangeProfileImage",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.UpdateProfileRequest"
+                            "$ref": "#/definitions/models.URL"
                         }
                     }
                 ],
@@ -1771,7 +2052,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.UserResponse"
+                            "$ref": "#/definitions/models.Void"
                         }
                     },
                     "400": {
@@ -1788,49 +2069,1063 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "models.ChangePasswordRequest": {
-            "type": "object",
-            "properties": {
-                "current_password": {
-                    "type": "string"
-                },
-                "new_password": {
-                    "type": "string"
-                }
-            }
-        },
-        "models.ChangePasswordResponse": {
-            "type": "object",
-            "properties": {
-                "message": {
-                    "type": "string"
-                }
-            }
-        },
-        "models.Comment": {
-            "type": "object",
-            "properties": {
-                "content": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "like_count": {
-                    "type": "integer"
-                },
-                "tweet_id": {
-                    "type": "string"
-                },
-                "user_id": {
-                    "type": "string"
-                }
-            }
         },
-        "models.CommentRes": {
+        "/user/change_username": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Change your username. It can be changed again only after a cooldown; the old one keeps pointing to you",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Change Username",
+                "parameters": [
+                    {
+                        "description": "New username",
+                        "name": "ChangeUsername",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ChangeUsernameRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.UserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/check_username/{username}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Tell whether a username can be taken, and why not",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Check Username",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Username",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/user.UsernameAvailability"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/deletion": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Schedule the account and all its content to be deleted for good after a grace period, during which the deletion can be cancelled",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Delete account",
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/user.DeletionStatus"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Cancel a scheduled account deletion while its grace period lasts",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Cancel account deletion",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/user.DeletionStatus"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Download everything kept about the user as a zip of JSON files: profile, followers, following, tweets, comments and likes",
+                "produces": [
+                    "application/zip"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Export account data",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/fetch_users": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve a list of users with filtering options. Emails are only shown to admins.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Fetch Users",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of users per page",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Part of the username or first name",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Role: user, admin or c-admin",
+                        "name": "role",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/user.UserResponses"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/follow": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Follow another user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Follow User",
+                "parameters": [
+                    {
+                        "description": "post user",
+                        "name": "Follow",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.FollowReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.FollowRes"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/get_profile": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve the profile of a user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Get User Profile",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.GetProfileResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/get_user_followers": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve a list of followers for a specific user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Get User Followers",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Count"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/get_user_follows": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve a list of users that a specific user is following",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Get User Follows",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Count"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/list_of_followers": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve the list of followers for a user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "List of Followers",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/user.Followers"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/list_of_followers_by_username/{username}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve the list of followers for a user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "List of Followers",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Username",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/user.Followers"
+                        }
+                    },
+                    "307": {
+                        "description": "A former username redirects to the current one"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/list_of_following": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve the list of users that a specific user is following",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "List of Following Users",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/user.Followings"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/list_of_following_by_username/{username}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve the list of users that a specific user is following",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "List of Following Users",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Username",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/user.Followings"
+                        }
+                    },
+                    "307": {
+                        "description": "A former username redirects to the current one"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/most_popular": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve the user who gained the most followers recently",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Most Popular User",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.UserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/mutual_followers": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "For each user, a page of the people you follow who follow them and how many there are in all. Takes up to 100 users",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Followers You Know",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comma separated user IDs",
+                        "name": "ids",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of followers per user",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/user.MutualFollowers"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/relationships": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Tell, for each user, whether you follow them, they follow you and whether either blocked the other. Takes up to 100 users, e.g. the authors of a page of tweets",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Relationships",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comma separated user IDs",
+                        "name": "ids",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/user.Relationships"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/search": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Search users by username, first or last name. With prefix only names starting with the query match, for typeahead; otherwise similar names match too. Users with more followers rank higher among equal matches",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Search Users",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Query",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Match names starting with the query only",
+                        "name": "prefix",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of users per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/user.SearchResults"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/suggestions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Suggest users to follow, ranked by how many of the people you follow follow them, whether they follow you and their follower count",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Who to follow",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/user.Suggestions"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/unfollow": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Unfollow a user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Unfollow User",
+                "parameters": [
+                    {
+                        "description": "put user",
+                        "name": "Unfollow",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.FollowReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.DFollowRes"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/update_profile": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update user profile details. The username is changed with /user/change_username",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Update User Profile",
+                "parameters": [
+                    {
+                        "description": "Update user profile",
+                        "name": "UpdateProfile",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateProfileRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.UserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Error"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "models.ChangePasswordRequest": {
+            "type": "object",
+            "properties": {
+                "current_password": {
+                    "type": "string"
+                },
+                "new_password": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ChangePasswordResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ChangeUsernameRequest": {
+            "type": "object",
+            "required": [
+                "username"
+            ],
+            "properties": {
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CommandAccepted": {
+            "type": "object",
+            "properties": {
+                "command_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Comment": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "like_count": {
+                    "type": "integer"
+                },
+                "tweet_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CommentRes": {
             "type": "object",
             "properties": {
                 "content": {
@@ -1924,14 +3219,38 @@ const docTemplate = `{
         "models.Error": {
             "type": "object",
             "properties": {
+                "error": {
+                    "$ref": "#/definitions/models.ErrorBody"
+                }
+            }
+        },
+        "models.ErrorBody": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "NOT_FOUND"
+                },
                 "message": {
-                    "type": "string"
+                    "type": "string",
+                    "example": "tweet not found"
                 }
             }
         },
         "models.FollowReq": {
             "type": "object",
             "properties": {
+                "following_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.FollowRes": {
+            "type": "object",
+            "properties": {
+                "followed_at": {
+                    "type": "string"
+                },
                 "follower_id": {
                     "type": "string"
                 },
@@ -1940,24 +3259,264 @@ const docTemplate = `{
                 }
             }
         },
-        "models.FollowRes": {
+        "models.GetProfileResponse": {
             "type": "object",
             "properties": {
-                "followed_at": {
+                "bio": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "first_name": {
+                    "type": "string"
+                },
+                "followers_count": {
+                    "type": "integer"
+                },
+                "following_count": {
+                    "type": "integer"
+                },
+                "last_name": {
+                    "type": "string"
+                },
+                "nationality": {
+                    "type": "string"
+                },
+                "phone_number": {
+                    "type": "string"
+                },
+                "posts_count": {
+                    "type": "integer"
+                },
+                "profile_image": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                },
+                "verified": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.LikeReq": {
+            "type": "object",
+            "properties": {
+                "tweet_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.LikeRes": {
+            "type": "object",
+            "properties": {
+                "liked_at": {
+                    "type": "string"
+                },
+                "tweet_id": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Message": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Readiness": {
+            "type": "object",
+            "properties": {
+                "checks": {
+                    "description": "Checks are \"ok\" or \"unavailable\" by dependency.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "models.SetRoleRequest": {
+            "type": "object",
+            "required": [
+                "role"
+            ],
+            "properties": {
+                "role": {
+                    "type": "string",
+                    "enum": [
+                        "user",
+                        "admin",
+                        "c-admin"
+                    ]
+                }
+            }
+        },
+        "models.SetVerifiedRequest": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string"
+                },
+                "verified": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.Tweet": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "hashtag": {
+                    "type": "string"
+                },
+                "image_url": {
+                    "type": "string"
+                },
+                "like_count": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.TweetResponse": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "hashtag": {
+                    "type": "string"
+                },
+                "image_url": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.TweetTitles": {
+            "type": "object",
+            "properties": {
+                "titles": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.Tweets": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "tweets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TweetResponse"
+                    }
+                }
+            }
+        },
+        "models.URL": {
+            "type": "object",
+            "properties": {
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.UpdateAComment": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.UpdateATweet": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string"
+                },
+                "hashtag": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.UpdateProfileRequest": {
+            "type": "object",
+            "properties": {
+                "bio": {
+                    "type": "string"
+                },
+                "first_name": {
+                    "type": "string"
+                },
+                "last_name": {
+                    "type": "string"
+                },
+                "nationality": {
+                    "type": "string"
+                },
+                "phone": {
                     "type": "string"
                 },
-                "follower_id": {
+                "phone_number": {
                     "type": "string"
                 },
-                "following_id": {
-                    "type": "string"
-                }
-            }
-        },
-        "models.Follower": {
-            "type": "object",
-            "properties": {
-                "email": {
+                "profile_image": {
                     "type": "string"
                 },
                 "username": {
@@ -1965,29 +3524,18 @@ const docTemplate = `{
                 }
             }
         },
-        "models.Followers": {
-            "type": "object",
-            "properties": {
-                "followers": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.Follower"
-                    }
-                }
-            }
-        },
-        "models.Followings": {
+        "models.Url": {
             "type": "object",
             "properties": {
-                "ids": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.Ids"
-                    }
+                "tweet_id": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
                 }
             }
         },
-        "models.GetProfileResponse": {
+        "models.UserResponse": {
             "type": "object",
             "properties": {
                 "bio": {
@@ -2002,11 +3550,8 @@ const docTemplate = `{
                 "first_name": {
                     "type": "string"
                 },
-                "followers_count": {
-                    "type": "integer"
-                },
-                "following_count": {
-                    "type": "integer"
+                "id": {
+                    "type": "string"
                 },
                 "last_name": {
                     "type": "string"
@@ -2014,112 +3559,203 @@ const docTemplate = `{
                 "nationality": {
                     "type": "string"
                 },
-                "phone_number": {
-                    "type": "string"
-                },
-                "posts_count": {
-                    "type": "integer"
-                },
-                "profile_image": {
+                "phone": {
                     "type": "string"
                 },
-                "updated_at": {
+                "role": {
                     "type": "string"
                 },
                 "username": {
                     "type": "string"
+                },
+                "verified": {
+                    "type": "boolean"
                 }
             }
         },
-        "models.Ids": {
+        "models.Void": {
+            "type": "object"
+        },
+        "service.Command": {
             "type": "object",
             "properties": {
-                "follower_id": {
+                "completed_at": {
                     "type": "string"
                 },
-                "following_id": {
+                "created_at": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "resource": {
+                    "type": "object"
+                },
+                "resource_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "type": {
                     "type": "string"
                 }
             }
         },
-        "models.LikeReq": {
+        "tweet.DeadLetter": {
             "type": "object",
             "properties": {
-                "tweet_id": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "deadAt": {
                     "type": "string"
                 },
-                "user_id": {
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "occurredAt": {
+                    "type": "string"
+                },
+                "payload": {
+                    "type": "string"
+                },
+                "type": {
                     "type": "string"
+                },
+                "version": {
+                    "type": "integer"
                 }
             }
         },
-        "models.LikeRes": {
+        "tweet.DeadLetters": {
             "type": "object",
             "properties": {
-                "liked_at": {
+                "deadLetters": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/tweet.DeadLetter"
+                    }
+                }
+            }
+        },
+        "tweet.ReTweetReq": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "hashtag": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "imageUrl": {
+                    "type": "string"
+                },
+                "likeCount": {
+                    "type": "integer"
+                },
+                "title": {
                     "type": "string"
                 },
                 "tweet_id": {
                     "type": "string"
                 },
-                "user_id": {
+                "userId": {
                     "type": "string"
                 }
             }
         },
-        "models.Message": {
+        "tweet.ReplayRes": {
             "type": "object",
             "properties": {
-                "message": {
-                    "type": "string"
+                "count": {
+                    "type": "integer"
                 }
             }
         },
-        "models.Tweet": {
+        "tweet.TweetResponse": {
             "type": "object",
             "properties": {
                 "content": {
                     "type": "string"
                 },
-                "created_at": {
+                "createdAt": {
                     "type": "string"
                 },
                 "hashtag": {
                     "type": "string"
                 },
-                "image_url": {
+                "id": {
+                    "type": "string"
+                },
+                "imageUrl": {
                     "type": "string"
                 },
                 "like_count": {
-                    "type": "integer"
+                    "type": "string"
                 },
                 "title": {
                     "type": "string"
                 },
-                "user_id": {
+                "updatedAt": {
+                    "type": "string"
+                },
+                "userId": {
                     "type": "string"
                 }
             }
         },
-        "models.TweetResponse": {
+        "user.AuditEntries": {
             "type": "object",
             "properties": {
-                "content": {
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/user.AuditEntry"
+                    }
+                }
+            }
+        },
+        "user.AuditEntry": {
+            "type": "object",
+            "properties": {
+                "action": {
                     "type": "string"
                 },
-                "created_at": {
+                "actor_id": {
                     "type": "string"
                 },
-                "hashtag": {
+                "created_at": {
                     "type": "string"
                 },
-                "image_url": {
+                "detail": {
                     "type": "string"
                 },
-                "title": {
-                    "type": "string"
+                "id": {
+                    "type": "integer"
                 },
-                "updated_at": {
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "user.DeletionStatus": {
+            "type": "object",
+            "properties": {
+                "delete_after": {
+                    "description": "When the account will be deleted; empty if no deletion is scheduled.",
                     "type": "string"
                 },
                 "user_id": {
@@ -2127,10 +3763,10 @@ const docTemplate = `{
                 }
             }
         },
-        "models.TweetTitles": {
+        "user.Followers": {
             "type": "object",
             "properties": {
-                "titles": {
+                "followers": {
                     "type": "array",
                     "items": {
                         "type": "string"
@@ -2138,60 +3774,102 @@ const docTemplate = `{
                 }
             }
         },
-        "models.Tweets": {
+        "user.Followings": {
             "type": "object",
             "properties": {
-                "limit": {
-                    "type": "integer"
-                },
-                "offset": {
-                    "type": "integer"
-                },
-                "tweets": {
+                "following": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.TweetResponse"
+                        "type": "string"
                     }
                 }
             }
         },
-        "models.URL": {
+        "user.MutualFollowers": {
             "type": "object",
             "properties": {
-                "url": {
-                    "type": "string"
+                "targets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/user.TargetMutualFollowers"
+                    }
                 }
             }
         },
-        "models.UpdateAComment": {
+        "user.Relationship": {
             "type": "object",
             "properties": {
-                "content": {
-                    "type": "string"
+                "blocked_by": {
+                    "description": "The target blocked the viewer.",
+                    "type": "boolean"
                 },
-                "id": {
+                "blocking": {
+                    "description": "The viewer blocked the target.",
+                    "type": "boolean"
+                },
+                "followed_by": {
+                    "description": "The target follows the viewer.",
+                    "type": "boolean"
+                },
+                "following": {
+                    "description": "The viewer follows the target.",
+                    "type": "boolean"
+                },
+                "target_id": {
                     "type": "string"
                 }
             }
         },
-        "models.UpdateATweet": {
+        "user.Relationships": {
             "type": "object",
             "properties": {
-                "content": {
+                "relationships": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/user.Relationship"
+                    }
+                }
+            }
+        },
+        "user.SearchResult": {
+            "type": "object",
+            "properties": {
+                "first_name": {
                     "type": "string"
                 },
-                "hashtag": {
-                    "type": "string"
+                "followers_count": {
+                    "type": "integer"
                 },
                 "id": {
                     "type": "string"
                 },
-                "title": {
+                "last_name": {
+                    "type": "string"
+                },
+                "score": {
+                    "description": "How well the user matches the query, from 0 to 1.",
+                    "type": "number"
+                },
+                "username": {
                     "type": "string"
+                },
+                "verified": {
+                    "type": "boolean"
                 }
             }
         },
-        "models.UpdateProfileRequest": {
+        "user.SearchResults": {
+            "type": "object",
+            "properties": {
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/user.SearchResult"
+                    }
+                }
+            }
+        },
+        "user.SuggestedUser": {
             "type": "object",
             "properties": {
                 "bio": {
@@ -2200,41 +3878,57 @@ const docTemplate = `{
                 "first_name": {
                     "type": "string"
                 },
-                "last_name": {
-                    "type": "string"
-                },
-                "nationality": {
-                    "type": "string"
+                "followers_count": {
+                    "type": "integer"
                 },
-                "phone": {
-                    "type": "string"
+                "follows_you": {
+                    "type": "boolean"
                 },
-                "phone_number": {
+                "id": {
                     "type": "string"
                 },
-                "profile_image": {
+                "last_name": {
                     "type": "string"
                 },
-                "user_id": {
-                    "type": "string"
+                "mutual_count": {
+                    "description": "How many of the people the user follows follow them.",
+                    "type": "integer"
                 },
                 "username": {
                     "type": "string"
                 }
             }
         },
-        "models.Url": {
+        "user.Suggestions": {
             "type": "object",
             "properties": {
-                "tweet_id": {
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/user.SuggestedUser"
+                    }
+                }
+            }
+        },
+        "user.TargetMutualFollowers": {
+            "type": "object",
+            "properties": {
+                "target_id": {
                     "type": "string"
                 },
-                "url": {
-                    "type": "string"
+                "total": {
+                    "description": "All of them, for \"and 3 others\".",
+                    "type": "integer"
+                },
+                "usernames": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
-        "models.UserResponse": {
+        "user.UserResponse": {
             "type": "object",
             "properties": {
                 "bio": {
@@ -2261,24 +3955,39 @@ const docTemplate = `{
                 "phone": {
                     "type": "string"
                 },
+                "role": {
+                    "type": "string"
+                },
                 "username": {
                     "type": "string"
+                },
+                "verified": {
+                    "type": "boolean"
                 }
             }
         },
-        "models.UserResponses": {
+        "user.UserResponses": {
             "type": "object",
             "properties": {
                 "users": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.UserResponse"
+                        "$ref": "#/definitions/user.UserResponse"
                     }
                 }
             }
         },
-        "models.Void": {
-            "type": "object"
+        "user.UsernameAvailability": {
+            "type": "object",
+            "properties": {
+                "available": {
+                    "type": "boolean"
+                },
+                "reason": {
+                    "description": "Why the username cannot be taken.",
+                    "type": "string"
+                }
+            }
         }
     },
     "securityDefinitions": {
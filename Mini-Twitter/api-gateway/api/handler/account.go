@@ -0,0 +1,185 @@
+package handler
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"log/slog"
+	"net/http"
+	"time"
+
+	pbt "apigateway/genproto/tweet"
+	pb "apigateway/genproto/user"
+	"apigateway/service"
+	"github.com/gin-gonic/gin"
+)
+
+type AccountHandler interface {
+	ExportData(c *gin.Context)
+	RequestDeletion(c *gin.Context)
+	CancelDeletion(c *gin.Context)
+}
+
+type accountHandler struct {
+	userService  pb.UserServiceClient
+	tweetService pbt.TweetServiceClient
+	logger       *slog.Logger
+}
+
+func NewAccountHandler(accountService service.Service, logger *slog.Logger) AccountHandler {
+	userClient := accountService.UserService()
+	tweetClient := accountService.TweetService()
+	if userClient == nil || tweetClient == nil {
+		log.Fatalf("Error creating account handler")
+		return nil
+	}
+	return &accountHandler{
+		userService:  userClient,
+		tweetService: tweetClient,
+		logger:       logger,
+	}
+}
+
+// ExportData godoc
+// @Summary Export account data
+// @Description Download everything kept about the user as a zip of JSON files: profile, followers, following, tweets, comments and likes
+// @Security BearerAuth
+// @Tags User
+// @Produce application/zip
+// @Success 200 {file} file
+// @Failure 500 {object} models.Error
+// @Router /user/export [get]
+func (h *accountHandler) ExportData(c *gin.Context) {
+	userID, ok := callerID(c)
+	if !ok {
+		return
+	}
+
+	account, err := h.userService.RequestDataExport(c.Request.Context(), &pb.Id{UserId: userID})
+	if err != nil {
+		h.logger.Error("Error occurred while exporting account", "error", err)
+		c.Error(err)
+		return
+	}
+
+	content, err := h.tweetService.ExportUserData(c.Request.Context(), &pbt.UserId{Id: userID})
+	if err != nil {
+		h.logger.Error("Error occurred while exporting tweets", "error", err)
+		c.Error(err)
+		return
+	}
+
+	archive, err := exportArchive(account, content)
+	if err != nil {
+		h.logger.Error("Error occurred while building export archive", "error", err)
+		c.Error(err)
+		return
+	}
+
+	name := fmt.Sprintf("mini-twitter-%s-%s.zip", userID, time.Now().UTC().Format("20060102"))
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name))
+	c.Data(http.StatusOK, "application/zip", archive)
+}
+
+// exportArchive writes one JSON file per kind of data into a zip.
+func exportArchive(account *pb.UserDataExport, content *pbt.UserContent) ([]byte, error) {
+	files := []struct {
+		name string
+		data interface{}
+	}{
+		{"profile.json", gin.H{"profile": account.Profile, "exported_at": account.ExportedAt}},
+		{"followers.json", nonNil(account.Followers)},
+		{"following.json", nonNil(account.Following)},
+		{"tweets.json", nonNil(content.Tweets)},
+		{"comments.json", nonNil(content.Comments)},
+		{"likes.json", nonNil(content.Likes)},
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, f := range files {
+		w, err := zw.Create(f.name)
+		if err != nil {
+			return nil, err
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(f.data); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// nonNil makes an empty list encode as [] rather than null.
+func nonNil[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
+
+// RequestDeletion godoc
+// @Summary Delete account
+// @Description Schedule the account and all its content to be deleted for good after a grace period, during which the deletion can be cancelled
+// @Security BearerAuth
+// @Tags User
+// @Accept json
+// @Produce json
+// @Success 202 {object} user.DeletionStatus
+// @Failure 500 {object} models.Error
+// @Router /user/deletion [post]
+func (h *accountHandler) RequestDeletion(c *gin.Context) {
+	userID, ok := callerID(c)
+	if !ok {
+		return
+	}
+
+	req := pb.Id{
+		UserId: userID,
+	}
+
+	res, err := h.userService.RequestDeletion(c.Request.Context(), &req)
+	if err != nil {
+		h.logger.Error("Error occurred while requesting account deletion", "error", err)
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusAccepted, res)
+}
+
+// CancelDeletion godoc
+// @Summary Cancel account deletion
+// @Description Cancel a scheduled account deletion while its grace period lasts
+// @Security BearerAuth
+// @Tags User
+// @Accept json
+// @Produce json
+// @Success 200 {object} user.DeletionStatus
+// @Failure 400 {object} models.Error
+// @Failure 500 {object} models.Error
+// @Router /user/deletion [delete]
+func (h *accountHandler) CancelDeletion(c *gin.Context) {
+	userID, ok := callerID(c)
+	if !ok {
+		return
+	}
+
+	req := pb.Id{
+		UserId: userID,
+	}
+
+	res, err := h.userService.CancelDeletion(c.Request.Context(), &req)
+	if err != nil {
+		h.logger.Error("Error occurred while cancelling account deletion", "error", err)
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
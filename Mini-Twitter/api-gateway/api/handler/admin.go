@@ -0,0 +1,156 @@
+package handler
+
+import (
+	pb "apigateway/genproto/user"
+	"apigateway/pkg/models"
+	"apigateway/service"
+	"github.com/gin-gonic/gin"
+	"log"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type AdminHandler interface {
+	SetRole(c *gin.Context)
+	SetVerified(c *gin.Context)
+	ListAuditLog(c *gin.Context)
+}
+
+type adminHandler struct {
+	userService pb.UserServiceClient
+	logger      *slog.Logger
+}
+
+func NewAdminHandler(adminService service.Service, logger *slog.Logger) AdminHandler {
+	userClient := adminService.UserService()
+	if userClient == nil {
+		log.Fatalf("Error creating admin handler")
+		return nil
+	}
+	return &adminHandler{
+		userService: userClient,
+		logger:      logger,
+	}
+}
+
+// SetRole godoc
+// @Summary Set user role
+// @Description Promote or demote a user. Admins manage roles up to their own; the user has to sign in again
+// @Security BearerAuth
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param user_id path string true "User ID"
+// @Param SetRole body models.SetRoleRequest true "New role"
+// @Success 200 {object} models.UserResponse
+// @Failure 400 {object} models.Error
+// @Failure 403 {object} models.Error
+// @Failure 500 {object} models.Error
+// @Router /admin/users/{user_id}/role [put]
+func (h *adminHandler) SetRole(c *gin.Context) {
+	var body models.SetRoleRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	userID, ok := callerID(c)
+	if !ok {
+		return
+	}
+
+	req := pb.SetRoleReq{
+		ActorId: userID,
+		UserId:  c.Param("user_id"),
+		Role:    body.Role,
+	}
+
+	res, err := h.userService.SetRole(c.Request.Context(), &req)
+	if err != nil {
+		h.logger.Error("Error occurred while setting role", "error", err)
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// SetVerified godoc
+// @Summary Set verified badge
+// @Description Grant or take a user's verified badge. The change and its reason are kept in the audit log
+// @Security BearerAuth
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param user_id path string true "User ID"
+// @Param SetVerified body models.SetVerifiedRequest true "Badge"
+// @Success 200 {object} models.UserResponse
+// @Failure 400 {object} models.Error
+// @Failure 403 {object} models.Error
+// @Failure 500 {object} models.Error
+// @Router /admin/users/{user_id}/verified [put]
+func (h *adminHandler) SetVerified(c *gin.Context) {
+	var body models.SetVerifiedRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	userID, ok := callerID(c)
+	if !ok {
+		return
+	}
+
+	req := pb.SetVerifiedReq{
+		ActorId:  userID,
+		UserId:   c.Param("user_id"),
+		Verified: body.Verified,
+		Reason:   body.Reason,
+	}
+
+	res, err := h.userService.SetVerified(c.Request.Context(), &req)
+	if err != nil {
+		h.logger.Error("Error occurred while setting verified", "error", err)
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// ListAuditLog godoc
+// @Summary List audit log
+// @Description List role and verified badge changes, newest first
+// @Security BearerAuth
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param user_id query string false "Only changes to this user"
+// @Param page query int false "Page number"
+// @Param limit query int false "Entries per page, at most 100"
+// @Success 200 {object} user.AuditEntries
+// @Failure 500 {object} models.Error
+// @Router /admin/audit [get]
+func (h *adminHandler) ListAuditLog(c *gin.Context) {
+	page, err := strconv.Atoi(c.Query("page"))
+	if err != nil {
+		page = 1
+	}
+	limit, err := strconv.Atoi(c.Query("limit"))
+	if err != nil {
+		limit = 50
+	}
+
+	req := pb.AuditFilter{
+		UserId: c.Query("user_id"),
+		Page:   int32(page),
+		Limit:  int32(limit),
+	}
+
+	res, err := h.userService.ListAuditLog(c.Request.Context(), &req)
+	if err != nil {
+		h.logger.Error("Error occurred while listing audit log", "error", err)
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"apigateway/pkg/models"
+	"apigateway/service"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"log/slog"
+	"net/http"
+)
+
+type CommandHandler interface {
+	GetCommand(c *gin.Context)
+}
+
+type commandHandler struct {
+	commands *service.CommandStore
+	logger   *slog.Logger
+}
+
+func NewCommandHandler(commands *service.CommandStore, logger *slog.Logger) CommandHandler {
+	return &commandHandler{
+		commands: commands,
+		logger:   logger,
+	}
+}
+
+// GetCommand godoc
+// @Summary Get command status
+// @Description Report whether an asynchronous write is pending, succeeded or failed, with the resource it created
+// @Security BearerAuth
+// @Tags Command
+// @Accept json
+// @Produce json
+// @Param id path string true "Command ID"
+// @Success 200 {object} service.Command
+// @Failure 404 {object} models.Error
+// @Failure 500 {object} models.Error
+// @Router /commands/{id} [get]
+func (h *commandHandler) GetCommand(c *gin.Context) {
+	cmd, err := h.commands.Get(c.Request.Context(), c.Param("id"))
+	if errors.Is(err, service.ErrCommandNotFound) {
+		c.Error(status.Error(codes.NotFound, err.Error()))
+		return
+	}
+	if err != nil {
+		h.logger.Error("Error occurred while getting command", "error", err)
+		c.Error(err)
+		return
+	}
+
+	userID, ok := callerID(c)
+	if !ok {
+		return
+	}
+
+	// Commands of other users, and commands whose owner was never recorded,
+	// are reported as missing rather than forbidden, so ids cannot be probed.
+	if cmd.UserID != userID {
+		c.Error(status.Error(codes.NotFound, service.ErrCommandNotFound.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": cmd})
+}
+
+// acceptCommand records a freshly published command as pending and answers
+// 202 Accepted with its id. The caller polls GET /commands/{id} for the
+// outcome. A command that could not be recorded has no owner to check reads
+// against, so the request fails and the client retries it with the same
+// Idempotency-Key.
+func acceptCommand(c *gin.Context, commands *service.CommandStore, logger *slog.Logger, id, typ string) {
+	userID, ok := callerID(c)
+	if !ok {
+		return
+	}
+
+	if err := commands.Pending(c.Request.Context(), id, typ, userID); err != nil {
+		logger.Error("Error occurred while recording command", "command_id", id, "error", err)
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusAccepted, models.CommandAccepted{CommandID: id, Status: "pending"})
+}
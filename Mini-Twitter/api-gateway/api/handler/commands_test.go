@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"apigateway/api/middleware"
+	"apigateway/service"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestGetCommandOwnership(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	commands := service.NewCommandStore(rdb)
+
+	if err := commands.Pending(context.Background(), "owned", "POST_TWEET", "alice"); err != nil {
+		t.Fatal(err)
+	}
+	// A command whose owner was never recorded.
+	mr.HSet("command:orphan", "id", "orphan", "status", "succeeded")
+
+	router := gin.New()
+	router.Use(middleware.ErrorMiddleware(logger))
+	router.Use(func(c *gin.Context) {
+		if id := c.GetHeader("X-User"); id != "" {
+			c.Set("user_id", id)
+		}
+	})
+	router.GET("/commands/:id", NewCommandHandler(commands, logger).GetCommand)
+
+	tests := []struct {
+		id, user string
+		want     int
+	}{
+		{"owned", "alice", http.StatusOK},
+		{"owned", "bob", http.StatusNotFound},
+		{"orphan", "alice", http.StatusNotFound},
+		{"missing", "alice", http.StatusNotFound},
+		{"owned", "", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/commands/"+tt.id, nil)
+		if tt.user != "" {
+			req.Header.Set("X-User", tt.user)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != tt.want {
+			t.Errorf("GET %s as %q: status = %d, want %d", tt.id, tt.user, w.Code, tt.want)
+		}
+	}
+}
+
+func TestAcceptCommandFailsWithoutRecord(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	mr := miniredis.RunT(t)
+	commands := service.NewCommandStore(redis.NewClient(&redis.Options{Addr: mr.Addr()}))
+	mr.Close()
+
+	router := gin.New()
+	router.Use(middleware.ErrorMiddleware(logger))
+	router.POST("/tweet/add", func(c *gin.Context) {
+		c.Set("user_id", "alice")
+		acceptCommand(c, commands, logger, "id", "POST_TWEET")
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/tweet/add", nil))
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500", w.Code)
+	}
+}
@@ -5,10 +5,11 @@ import (
 	"apigateway/pkg/models"
 	t "apigateway/pkg/token"
 	"apigateway/service"
-	"encoding/json"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	amqp "github.com/rabbitmq/amqp091-go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"log"
 	"log/slog"
 	"net/http"
@@ -26,12 +27,13 @@ type CommentHandler interface {
 }
 
 type commentHandler struct {
+	commands       *service.CommandStore
 	CommentMQ      *service.MsgBroker
 	CommentService pb.TweetServiceClient
 	logger         *slog.Logger
 }
 
-func NewCommentHandler(commentService service.Service, logger *slog.Logger, conn *amqp.Channel) CommentHandler {
+func NewCommentHandler(commentService service.Service, logger *slog.Logger, conn *amqp.Channel, commands *service.CommandStore) CommentHandler {
 	commentClient := commentService.TweetService()
 	if commentClient == nil {
 		log.Fatalf("Failed to create comment handler")
@@ -39,6 +41,7 @@ func NewCommentHandler(commentService service.Service, logger *slog.Logger, conn
 	}
 	return &commentHandler{
 		CommentMQ:      service.NewMsgBroker(conn, logger),
+		commands:       commands,
 		CommentService: commentClient,
 		logger:         logger,
 	}
@@ -52,23 +55,24 @@ func NewCommentHandler(commentService service.Service, logger *slog.Logger, conn
 // @Accept json
 // @Produce json
 // @Param comment body models.Comment true "Comment to be created"
-// @Success 200 {object} models.CommentRes
+// @Param Idempotency-Key header string false "Key that makes retries of this request apply once"
+// @Success 202 {object} models.CommandAccepted
 // @Failure 400 {object} models.Error
 // @Failure 500 {object} models.Error
 // @Router /comment/post [post]
 func (h *commentHandler) PostComment(c *gin.Context) {
 	var tweet models.Comment
 	if err := c.ShouldBindJSON(&tweet); err != nil {
-		h.logger.Error("Error occurred while binding json", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		h.logger.Error("Error occurred while binding json", "error", err)
+		c.Error(err).SetType(gin.ErrorTypeBind)
 		return
 	}
 
 	token := c.GetHeader("Authorization")
-	cl, err := t.ExtractClaims(token)
+	cl, err := t.ExtractClaims(c.Request.Context(), token)
 	if err != nil {
-		h.logger.Error("Error occurred while extracting claims", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		h.logger.Error("Error occurred while extracting claims", "error", err)
+		c.Error(status.Error(codes.Unauthenticated, err.Error()))
 		return
 	}
 
@@ -82,21 +86,13 @@ func (h *commentHandler) PostComment(c *gin.Context) {
 		LikeCount: tweet.LikeCount,
 	}
 
-	log.Printf("%+v", res)
-
-	bady, err := json.Marshal(&res)
-	if err != nil {
-		h.logger.Error("Error occurred while marshaling json", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-	err = h.CommentMQ.PostComment(bady)
+	id, err := h.CommentMQ.PostComment(c.Request.Context(), &res)
 	if err != nil {
-		h.logger.Error("Error occurred while posting json", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		h.logger.Error("Error occurred while posting comment", "error", err)
+		c.Error(err)
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"massage": "success"})
+	acceptCommand(c, h.commands, h.logger, id, "POST_COMMENT")
 }
 
 // UpdateComment godoc
@@ -107,34 +103,29 @@ func (h *commentHandler) PostComment(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param comment body models.UpdateAComment true "Updated comment details"
-// @Success 200 {object} models.CommentRes
+// @Param Idempotency-Key header string false "Key that makes retries of this request apply once"
+// @Success 202 {object} models.CommandAccepted
 // @Failure 400 {object} models.Error
 // @Failure 500 {object} models.Error
 // @Router /comment/update [put]
 func (h *commentHandler) UpdateComment(c *gin.Context) {
 	var tweet models.UpdateAComment
 	if err := c.ShouldBindJSON(&tweet); err != nil {
-		h.logger.Error("Error occurred while binding json", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		h.logger.Error("Error occurred while binding json", "error", err)
+		c.Error(err).SetType(gin.ErrorTypeBind)
 		return
 	}
 	res := pb.UpdateAComment{
 		Id:      tweet.ID,
 		Content: tweet.Content,
 	}
-	bady, err := json.Marshal(&res)
-	if err != nil {
-		h.logger.Error("Error occurred while marshaling json", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-	err = h.CommentMQ.UpdateComment(bady)
+	id, err := h.CommentMQ.UpdateComment(c.Request.Context(), &res)
 	if err != nil {
-		h.logger.Error("Error occurred while updating json", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		h.logger.Error("Error occurred while updating comment", "error", err)
+		c.Error(err)
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"massage": "success"})
+	acceptCommand(c, h.commands, h.logger, id, "UPDATE_COMMENT")
 }
 
 // DeleteComment godoc
@@ -158,8 +149,8 @@ func (h *commentHandler) DeleteComment(c *gin.Context) {
 
 	req, err := h.CommentService.DeleteComment(c.Request.Context(), &res)
 	if err != nil {
-		h.logger.Error("Error occurred while deleting comment", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		h.logger.Error("Error occurred while deleting comment", "error", err)
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"data": req})
@@ -186,8 +177,8 @@ func (h *commentHandler) GetComment(c *gin.Context) {
 
 	req, err := h.CommentService.GetComment(c.Request.Context(), &res)
 	if err != nil {
-		h.logger.Error("Error occurred while deleting comment", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		h.logger.Error("Error occurred while deleting comment", "error", err)
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"data": req})
@@ -215,8 +206,8 @@ func (h *commentHandler) GetAllComments(c *gin.Context) {
 	}
 	req, err := h.CommentService.GetAllComments(c.Request.Context(), &res)
 	if err != nil {
-		h.logger.Error("Error occurred while getting comments", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		h.logger.Error("Error occurred while getting comments", "error", err)
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"data": req})
@@ -236,10 +227,10 @@ func (h *commentHandler) GetAllComments(c *gin.Context) {
 func (h *commentHandler) GetUserComments(c *gin.Context) {
 
 	token := c.GetHeader("Authorization")
-	cl, err := t.ExtractClaims(token)
+	cl, err := t.ExtractClaims(c.Request.Context(), token)
 	if err != nil {
-		h.logger.Error("Error occurred while extracting claims", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		h.logger.Error("Error occurred while extracting claims", "error", err)
+		c.Error(status.Error(codes.Unauthenticated, err.Error()))
 		return
 	}
 
@@ -248,8 +239,8 @@ func (h *commentHandler) GetUserComments(c *gin.Context) {
 	}
 	req, err := h.CommentService.GetUserComments(c.Request.Context(), &res)
 	if err != nil {
-		h.logger.Error("Error occurred while getting comments", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		h.logger.Error("Error occurred while getting comments", "error", err)
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"data": req})
@@ -274,8 +265,8 @@ func (h *commentHandler) AddLikeToComment(c *gin.Context) {
 	}
 	req, err := h.CommentService.AddLikeToComment(c.Request.Context(), &res)
 	if err != nil {
-		h.logger.Error("Error occurred while adding like to comment", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		h.logger.Error("Error occurred while adding like to comment", "error", err)
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"data": req})
@@ -300,8 +291,8 @@ func (h *commentHandler) DeleteLikeComment(c *gin.Context) {
 	}
 	req, err := h.CommentService.DeleteLikeComment(c.Request.Context(), &res)
 	if err != nil {
-		h.logger.Error("Error occurred while deleting like comment", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		h.logger.Error("Error occurred while deleting like comment", "error", err)
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"data": req})
@@ -0,0 +1,98 @@
+package handler
+
+import (
+	pb "apigateway/genproto/tweet"
+	"apigateway/service"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"log"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type DeadLetterHandler interface {
+	ListDeadLetters(c *gin.Context)
+	ReplayDeadLetter(c *gin.Context)
+}
+
+type deadLetterHandler struct {
+	deadLetters pb.DeadLetterServiceClient
+	logger      *slog.Logger
+}
+
+func NewDeadLetterHandler(deadLetterService service.Service, logger *slog.Logger) DeadLetterHandler {
+	deadLetterClient := deadLetterService.DeadLetterService()
+	if deadLetterClient == nil {
+		log.Fatalf("Error creating dead letter handler")
+		return nil
+	}
+	return &deadLetterHandler{
+		deadLetters: deadLetterClient,
+		logger:      logger,
+	}
+}
+
+// ListDeadLetters godoc
+// @Summary List dead letters
+// @Description List commands that were dead-lettered after failing or running out of retries
+// @Security BearerAuth
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param type path string true "Command type, e.g. POST_TWEET"
+// @Param limit query int false "Maximum number of messages"
+// @Success 200 {object} tweet.DeadLetters
+// @Failure 400 {object} models.Error
+// @Failure 500 {object} models.Error
+// @Router /admin/dead_letters/{type} [get]
+func (h *deadLetterHandler) ListDeadLetters(c *gin.Context) {
+	req := pb.DeadLetterFilter{
+		Type: c.Param("type"),
+	}
+
+	if limit := c.Query("limit"); limit != "" {
+		l, err := strconv.ParseInt(limit, 10, 64)
+		if err != nil {
+			c.Error(status.Error(codes.InvalidArgument, "invalid limit"))
+			return
+		}
+		req.Limit = l
+	}
+
+	res, err := h.deadLetters.ListDeadLetters(c.Request.Context(), &req)
+	if err != nil {
+		h.logger.Error("Error occurred while listing dead letters", "error", err)
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": res})
+}
+
+// ReplayDeadLetter godoc
+// @Summary Replay dead letters
+// @Description Send a dead-lettered command back to its queue. Without an id every dead letter of the type is replayed
+// @Security BearerAuth
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param type path string true "Command type, e.g. POST_TWEET"
+// @Param id query string false "Message ID"
+// @Success 200 {object} tweet.ReplayRes
+// @Failure 500 {object} models.Error
+// @Router /admin/dead_letters/{type}/replay [post]
+func (h *deadLetterHandler) ReplayDeadLetter(c *gin.Context) {
+	req := pb.ReplayReq{
+		Type: c.Param("type"),
+		Id:   c.Query("id"),
+	}
+
+	res, err := h.deadLetters.ReplayDeadLetter(c.Request.Context(), &req)
+	if err != nil {
+		h.logger.Error("Error occurred while replaying dead letters", "error", err)
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": res})
+}
@@ -2,6 +2,9 @@ package handler
 
 import (
 	"apigateway/service"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"log/slog"
 )
 
@@ -22,3 +25,16 @@ func NewMainHandler(service service.Service, logger *slog.Logger) handler {
 		User: NewUserHandler(service, logger),
 	}
 }
+
+// callerID returns the id of the authenticated caller, as recorded by the
+// permission middleware. The request fails as unauthenticated when there is
+// none.
+func callerID(c *gin.Context) (string, bool) {
+	value, _ := c.Get("user_id")
+	id, ok := value.(string)
+	if !ok || id == "" {
+		c.Error(status.Error(codes.Unauthenticated, "caller is not authenticated"))
+		return "", false
+	}
+	return id, true
+}
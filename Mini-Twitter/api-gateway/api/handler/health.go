@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"apigateway/pkg/models"
+	"apigateway/service"
+	"context"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// readyTimeout bounds all the checks of one readiness request.
+const readyTimeout = 2 * time.Second
+
+type HealthHandler interface {
+	Healthz(c *gin.Context)
+	Readyz(c *gin.Context)
+}
+
+type healthHandler struct {
+	probes map[string]service.Probe
+	logger *slog.Logger
+}
+
+func NewHealthHandler(probes map[string]service.Probe, logger *slog.Logger) HealthHandler {
+	return &healthHandler{
+		probes: probes,
+		logger: logger,
+	}
+}
+
+// Healthz godoc
+// @Summary Liveness
+// @Description Tell whether the gateway process is up. It does not look at the services behind it
+// @Tags Health
+// @Produce json
+// @Success 200 {object} models.Readiness
+// @Router /healthz [get]
+func (h *healthHandler) Healthz(c *gin.Context) {
+	c.JSON(http.StatusOK, models.Readiness{Status: "ok"})
+}
+
+// Readyz godoc
+// @Summary Readiness
+// @Description Tell whether the gateway can serve requests: auth-service and twitt-service report serving, and Redis and RabbitMQ are reachable
+// @Tags Health
+// @Produce json
+// @Success 200 {object} models.Readiness
+// @Failure 503 {object} models.Readiness
+// @Router /readyz [get]
+func (h *healthHandler) Readyz(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), readyTimeout)
+	defer cancel()
+
+	res := models.Readiness{Status: "ok", Checks: make(map[string]string, len(h.probes))}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, probe := range h.probes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Why a check failed is only logged, so the public endpoint does
+			// not give away addresses or errors of the dependencies.
+			result := "ok"
+			if err := probe(ctx); err != nil {
+				h.logger.Warn("Readiness check failed", "dependency", name, "error", err)
+				result = "unavailable"
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			res.Checks[name] = result
+			if result != "ok" {
+				res.Status = "unavailable"
+			}
+		}()
+	}
+	wg.Wait()
+
+	code := http.StatusOK
+	if res.Status != "ok" {
+		code = http.StatusServiceUnavailable
+	}
+	c.JSON(code, res)
+}
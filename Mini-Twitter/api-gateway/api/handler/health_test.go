@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"apigateway/pkg/models"
+	"apigateway/service"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func readyz(t *testing.T, probes map[string]service.Probe) (int, models.Readiness) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.GET("/readyz", NewHealthHandler(probes, slog.New(slog.NewTextHandler(io.Discard, nil))).Readyz)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	var res models.Readiness
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatalf("decoding %s: %v", w.Body, err)
+	}
+	return w.Code, res
+}
+
+func ok(ctx context.Context) error { return nil }
+
+func TestReadyz(t *testing.T) {
+	code, res := readyz(t, map[string]service.Probe{"redis": ok, "rabbitmq": ok})
+	if code != http.StatusOK || res.Status != "ok" {
+		t.Fatalf("got %d %q, want 200 ok", code, res.Status)
+	}
+	if res.Checks["redis"] != "ok" || res.Checks["rabbitmq"] != "ok" {
+		t.Errorf("checks = %v, want all ok", res.Checks)
+	}
+}
+
+func TestReadyzHidesErrors(t *testing.T) {
+	failing := func(ctx context.Context) error {
+		return errors.New("dial tcp 10.0.0.7:6379: connection refused")
+	}
+	code, res := readyz(t, map[string]service.Probe{"redis": failing, "rabbitmq": ok})
+	if code != http.StatusServiceUnavailable || res.Status != "unavailable" {
+		t.Fatalf("got %d %q, want 503 unavailable", code, res.Status)
+	}
+	if res.Checks["redis"] != "unavailable" || res.Checks["rabbitmq"] != "ok" {
+		t.Errorf("checks = %v, want redis unavailable and rabbitmq ok", res.Checks)
+	}
+}
+
+func TestHealthz(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	// Liveness does not depend on the probes.
+	router.GET("/healthz", NewHealthHandler(map[string]service.Probe{
+		"redis": func(ctx context.Context) error { return errors.New("down") },
+	}, slog.New(slog.NewTextHandler(io.Discard, nil))).Healthz)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+}
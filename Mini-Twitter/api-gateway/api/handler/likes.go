@@ -5,10 +5,10 @@ import (
 	"apigateway/pkg/models"
 	t "apigateway/pkg/token"
 	"apigateway/service"
-	"context"
-	"encoding/json"
 	"github.com/gin-gonic/gin"
 	amqp "github.com/rabbitmq/amqp091-go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"log"
 	"log/slog"
 	"net/http"
@@ -23,12 +23,13 @@ type LikeHandler interface {
 }
 
 type HandlerL struct {
+	commands     *service.CommandStore
 	LikeMQ       *service.MsgBroker
 	TweetService pb.TweetServiceClient
 	logger       *slog.Logger
 }
 
-func NewLikeHandler(tweerService service.Service, logger *slog.Logger, conn *amqp.Channel) LikeHandler {
+func NewLikeHandler(tweerService service.Service, logger *slog.Logger, conn *amqp.Channel, commands *service.CommandStore) LikeHandler {
 	tweerClient := tweerService.TweetService()
 	if tweerClient == nil {
 		log.Fatalf("Error creating like handler")
@@ -36,6 +37,7 @@ func NewLikeHandler(tweerService service.Service, logger *slog.Logger, conn *amq
 	}
 	return &HandlerL{
 		LikeMQ:       service.NewMsgBroker(conn, logger),
+		commands:     commands,
 		TweetService: tweerClient,
 		logger:       logger,
 	}
@@ -49,23 +51,25 @@ func NewLikeHandler(tweerService service.Service, logger *slog.Logger, conn *amq
 // @Accept json
 // @Produce json
 // @Param AddLike body models.LikeReq true "Add like request"
-// @Success 200 {object} models.LikeRes
+// @Param Idempotency-Key header string false "Key that makes retries of this request apply once"
+// @Success 202 {object} models.CommandAccepted
 // @Failure 400 {object} models.Error
 // @Failure 404 {object} models.Error
+// @Failure 429 {object} models.Error
 // @Failure 500 {object} models.Error
 // @Router /like/add [post]
 func (h *HandlerL) AddLike(c *gin.Context) {
 	var like models.LikeReq
 	if err := c.ShouldBindJSON(&like); err != nil {
-		h.logger.Error("Error occurred while posting tweet", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		h.logger.Error("Error occurred while posting tweet", "error", err)
+		c.Error(err).SetType(gin.ErrorTypeBind)
 		return
 	}
 	token := c.GetHeader("Authorization")
-	cl, err := t.ExtractClaims(token)
+	cl, err := t.ExtractClaims(c.Request.Context(), token)
 	if err != nil {
-		h.logger.Error("Error occurred while extracting claims", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		h.logger.Error("Error occurred while extracting claims", "error", err)
+		c.Error(status.Error(codes.Unauthenticated, err.Error()))
 		return
 	}
 
@@ -76,20 +80,13 @@ func (h *HandlerL) AddLike(c *gin.Context) {
 		TweetId: like.TweetID,
 	}
 
-	body, err := json.Marshal(&res)
-	if err != nil {
-		h.logger.Error("Error occurred while posting tweet", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-
-	err = h.LikeMQ.AddLike(body)
+	id, err := h.LikeMQ.AddLike(c.Request.Context(), &res)
 	if err != nil {
-		h.logger.Error("Error occurred while posting tweet", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		h.logger.Error("Error occurred while adding like", "error", err)
+		c.Error(err)
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"massage": "success"})
+	acceptCommand(c, h.commands, h.logger, id, "ADD_LIKE")
 }
 
 // DeleteLIke godoc
@@ -103,6 +100,7 @@ func (h *HandlerL) AddLike(c *gin.Context) {
 // @Success 200 {object} models.LikeRes
 // @Failure 400 {object} models.Error
 // @Failure 404 {object} models.Error
+// @Failure 429 {object} models.Error
 // @Failure 500 {object} models.Error
 // @Router /like/delete/{tweet_id} [delete]
 func (h *HandlerL) DeleteLIke(c *gin.Context) {
@@ -114,11 +112,11 @@ func (h *HandlerL) DeleteLIke(c *gin.Context) {
 	}
 
 	// Call the service to delete the like
-	req, err := h.TweetService.DeleteLike(context.Background(), &res)
+	req, err := h.TweetService.DeleteLike(c.Request.Context(), &res)
 	if err != nil {
-		h.logger.Error("Error occurred while deleting like", err)
+		h.logger.Error("Error occurred while deleting like", "error", err)
 		// Determine the appropriate status code based on the error
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 
@@ -135,16 +133,17 @@ func (h *HandlerL) DeleteLIke(c *gin.Context) {
 // @Success 200 {object} models.TweetTitles
 // @Failure 400 {object} models.Error
 // @Failure 404 {object} models.Error
+// @Failure 429 {object} models.Error
 // @Failure 500 {object} models.Error
 // @Router /like/get_user [get]
 func (h *HandlerL) GetUserLikes(c *gin.Context) {
 	res := pb.UserId{
 		Id: c.MustGet("user_id").(string),
 	}
-	req, err := h.TweetService.GetUserLikes(context.Background(), &res)
+	req, err := h.TweetService.GetUserLikes(c.Request.Context(), &res)
 	if err != nil {
-		h.logger.Error("Error occurred while posting tweet", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		h.logger.Error("Error occurred while posting tweet", "error", err)
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"data": req})
@@ -161,6 +160,7 @@ func (h *HandlerL) GetUserLikes(c *gin.Context) {
 // @Success 200 {object} models.Count
 // @Failure 400 {object} models.Error
 // @Failure 404 {object} models.Error
+// @Failure 429 {object} models.Error
 // @Failure 500 {object} models.Error
 // @Router /like/get_count/{tweet_id} [get]
 func (h *HandlerL) GetCountTweetLikes(c *gin.Context) {
@@ -170,10 +170,10 @@ func (h *HandlerL) GetCountTweetLikes(c *gin.Context) {
 		Id: TweetId,
 	}
 
-	req, err := h.TweetService.GetCountTweetLikes(context.Background(), &res)
+	req, err := h.TweetService.GetCountTweetLikes(c.Request.Context(), &res)
 	if err != nil {
-		h.logger.Error("Error occurred while posting tweet", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		h.logger.Error("Error occurred while posting tweet", "error", err)
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"data": req})
@@ -189,15 +189,16 @@ func (h *HandlerL) GetCountTweetLikes(c *gin.Context) {
 // @Success 200 {object} models.Tweet
 // @Failure 400 {object} models.Error
 // @Failure 404 {object} models.Error
+// @Failure 429 {object} models.Error
 // @Failure 500 {object} models.Error
 // @Router /like/get_most [get]
 func (h *HandlerL) MostLikedTweets(c *gin.Context) {
 	res := pb.Void{}
 
-	req, err := h.TweetService.MostLikedTweets(context.Background(), &res)
+	req, err := h.TweetService.MostLikedTweets(c.Request.Context(), &res)
 	if err != nil {
-		h.logger.Error("Error occurred while posting tweet", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		h.logger.Error("Error occurred while posting tweet", "error", err)
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"data": req})
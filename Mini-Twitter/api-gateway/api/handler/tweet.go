@@ -5,10 +5,10 @@ import (
 	"apigateway/pkg/models"
 	t "apigateway/pkg/token"
 	"apigateway/service"
-	"encoding/json"
-	"fmt"
 	"github.com/gin-gonic/gin"
 	amqp "github.com/rabbitmq/amqp091-go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"log"
 	"log/slog"
 	"net/http"
@@ -28,12 +28,13 @@ type TweetHandler interface {
 }
 
 type tweetHandler struct {
+	commands     *service.CommandStore
 	TweetMQ      *service.MsgBroker
 	tweetService pb.TweetServiceClient
 	logger       *slog.Logger
 }
 
-func NewTweetHandler(tweetService service.Service, logger *slog.Logger, conn *amqp.Channel) TweetHandler {
+func NewTweetHandler(tweetService service.Service, logger *slog.Logger, conn *amqp.Channel, commands *service.CommandStore) TweetHandler {
 	tweetClient := tweetService.TweetService()
 	if tweetClient == nil {
 		log.Fatalf("tweet client is nil")
@@ -41,6 +42,7 @@ func NewTweetHandler(tweetService service.Service, logger *slog.Logger, conn *am
 	}
 	return &tweetHandler{
 		TweetMQ:      service.NewMsgBroker(conn, logger),
+		commands:     commands,
 		tweetService: tweetClient,
 		logger:       logger,
 	}
@@ -54,29 +56,29 @@ func NewTweetHandler(tweetService service.Service, logger *slog.Logger, conn *am
 // @Accept json
 // @Produce json
 // @Param PostTweet body models.Tweet true "Post tweet"
-// @Success 200 {object} models.TweetResponse
+// @Param Idempotency-Key header string false "Key that makes retries of this request apply once"
+// @Success 202 {object} models.CommandAccepted
 // @Failure 400 {object} models.Error
+// @Failure 429 {object} models.Error
 // @Failure 500 {object} models.Error
 // @Router /tweet/add [post]
 func (h *tweetHandler) PostTweet(c *gin.Context) {
 	var tweet models.Tweet
 	if err := c.ShouldBindJSON(&tweet); err != nil {
-		h.logger.Error("Error occurred while binding json", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		h.logger.Error("Error occurred while binding json", "error", err)
+		c.Error(err).SetType(gin.ErrorTypeBind)
 		return
 	}
 	token := c.GetHeader("Authorization")
-	cl, err := t.ExtractClaims(token)
+	cl, err := t.ExtractClaims(c.Request.Context(), token)
 	if err != nil {
-		h.logger.Error("Error occurred while extracting claims", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		h.logger.Error("Error occurred while extracting claims", "error", err)
+		c.Error(status.Error(codes.Unauthenticated, err.Error()))
 		return
 	}
 
 	tweet.UserID = cl["user_id"].(string)
 
-	fmt.Println(tweet.UserID)
-
 	res := pb.Tweet{
 		UserId:   tweet.UserID,
 		Hashtag:  tweet.Hashtag,
@@ -85,20 +87,13 @@ func (h *tweetHandler) PostTweet(c *gin.Context) {
 		ImageUrl: tweet.ImageURL,
 	}
 
-	bady, err := json.Marshal(&res)
+	id, err := h.TweetMQ.PostTweet(c.Request.Context(), &res)
 	if err != nil {
-		h.logger.Error("Error occurred while marshaling json", err)
+		h.logger.Error("Error occurred while posting tweet", "error", err)
+		c.Error(err)
 		return
 	}
-
-	log.Println(string(bady))
-
-	err = h.TweetMQ.PostTweet(bady)
-	if err != nil {
-		h.logger.Error("Error occurred while posting tweet", err)
-		return
-	}
-	c.JSON(http.StatusOK, gin.H{"massage": "success"})
+	acceptCommand(c, h.commands, h.logger, id, "POST_TWEET")
 }
 
 // UpdateTweet godoc
@@ -109,15 +104,17 @@ func (h *tweetHandler) PostTweet(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param UpdateTweet body models.UpdateATweet true "Update tweet"
-// @Success 200 {object} models.TweetResponse
+// @Param Idempotency-Key header string false "Key that makes retries of this request apply once"
+// @Success 202 {object} models.CommandAccepted
 // @Failure 400 {object} models.Error
+// @Failure 429 {object} models.Error
 // @Failure 500 {object} models.Error
 // @Router /tweet/add_up [put]
 func (h *tweetHandler) UpdateTweet(c *gin.Context) {
 	var tweet models.UpdateATweet
 	if err := c.ShouldBindJSON(&tweet); err != nil {
-		h.logger.Error("Error occurred while binding json", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		h.logger.Error("Error occurred while binding json", "error", err)
+		c.Error(err).SetType(gin.ErrorTypeBind)
 		return
 	}
 	res := pb.UpdateATweet{
@@ -126,17 +123,13 @@ func (h *tweetHandler) UpdateTweet(c *gin.Context) {
 		Title:   tweet.Title,
 		Content: tweet.Content,
 	}
-	bady, err := json.Marshal(&res)
-	if err != nil {
-		h.logger.Error("Error occurred while marshaling json", err)
-		return
-	}
-	err = h.TweetMQ.UpdateTweet(bady)
+	id, err := h.TweetMQ.UpdateTweet(c.Request.Context(), &res)
 	if err != nil {
-		h.logger.Error("Error occurred while updating tweet", err)
+		h.logger.Error("Error occurred while updating tweet", "error", err)
+		c.Error(err)
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"massage": "success"})
+	acceptCommand(c, h.commands, h.logger, id, "UPDATE_TWEET")
 }
 
 // AddImageToTweet godoc
@@ -149,13 +142,14 @@ func (h *tweetHandler) UpdateTweet(c *gin.Context) {
 // @Param AddImageToTweet body models.Url true "Add image to tweet"
 // @Success 200 {object} models.Message
 // @Failure 400 {object} models.Error
+// @Failure 429 {object} models.Error
 // @Failure 500 {object} models.Error
 // @Router /tweet/add_image [post]
 func (h *tweetHandler) AddImageToTweet(c *gin.Context) {
 	var tweet models.Url
 	if err := c.ShouldBindJSON(&tweet); err != nil {
-		h.logger.Error("Error occurred while binding json", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		h.logger.Error("Error occurred while binding json", "error", err)
+		c.Error(err).SetType(gin.ErrorTypeBind)
 		return
 	}
 	res := pb.Url{
@@ -165,8 +159,8 @@ func (h *tweetHandler) AddImageToTweet(c *gin.Context) {
 
 	req, err := h.tweetService.AddImageToTweet(c.Request.Context(), &res)
 	if err != nil {
-		h.logger.Error("Error occurred while adding image to tweet", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		h.logger.Error("Error occurred while adding image to tweet", "error", err)
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"data": req})
@@ -182,6 +176,7 @@ func (h *tweetHandler) AddImageToTweet(c *gin.Context) {
 // @Param user_id query string false "User ID"
 // @Success 200 {object} models.Tweets
 // @Failure 400 {object} models.Error
+// @Failure 429 {object} models.Error
 // @Failure 500 {object} models.Error
 // @Router /tweet/user [get]
 func (h *tweetHandler) UserTweets(c *gin.Context) {
@@ -191,8 +186,8 @@ func (h *tweetHandler) UserTweets(c *gin.Context) {
 
 	req, err := h.tweetService.UserTweets(c.Request.Context(), &res)
 	if err != nil {
-		h.logger.Error("Error occurred while getting user tweets", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		h.logger.Error("Error occurred while getting user tweets", "error", err)
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"data": req})
@@ -208,6 +203,7 @@ func (h *tweetHandler) UserTweets(c *gin.Context) {
 // @Param id path string true "Tweet ID"
 // @Success 200 {object} models.TweetResponse
 // @Failure 400 {object} models.Error
+// @Failure 429 {object} models.Error
 // @Failure 500 {object} models.Error
 // @Router /tweet/get_tt/{id} [get]
 func (h *tweetHandler) GetTweet(c *gin.Context) {
@@ -219,8 +215,8 @@ func (h *tweetHandler) GetTweet(c *gin.Context) {
 
 	req, err := h.tweetService.GetTweet(c.Request.Context(), &res)
 	if err != nil {
-		h.logger.Error("Error occurred while getting tweet", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		h.logger.Error("Error occurred while getting tweet", "error", err)
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"data": req})
@@ -239,6 +235,7 @@ func (h *tweetHandler) GetTweet(c *gin.Context) {
 // @Param title query string false "Title filter"
 // @Success 200 {object} models.Tweets
 // @Failure 400 {object} models.Error
+// @Failure 429 {object} models.Error
 // @Failure 500 {object} models.Error
 // @Router /tweet/get_all [get]
 func (h *tweetHandler) GetAllTweets(c *gin.Context) {
@@ -267,8 +264,8 @@ func (h *tweetHandler) GetAllTweets(c *gin.Context) {
 
 	req, err := h.tweetService.GetAllTweets(c.Request.Context(), &res)
 	if err != nil {
-		h.logger.Error("Error occurred while getting tweets", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		h.logger.Error("Error occurred while getting tweets", "error", err)
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"data": req})
@@ -284,6 +281,7 @@ func (h *tweetHandler) GetAllTweets(c *gin.Context) {
 // @Param user_id query string false "User ID"
 // @Success 200 {object} models.Tweets
 // @Failure 400 {object} models.Error
+// @Failure 429 {object} models.Error
 // @Failure 500 {object} models.Error
 // @Router /tweet/recommend [get]
 func (h *tweetHandler) RecommendTweets(c *gin.Context) {
@@ -292,8 +290,8 @@ func (h *tweetHandler) RecommendTweets(c *gin.Context) {
 	}
 	req, err := h.tweetService.RecommendTweets(c.Request.Context(), &res)
 	if err != nil {
-		h.logger.Error("Error occurred while getting tweets", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		h.logger.Error("Error occurred while getting tweets", "error", err)
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"data": req})
@@ -309,6 +307,7 @@ func (h *tweetHandler) RecommendTweets(c *gin.Context) {
 // @Param user_id query string false "User ID"
 // @Success 200 {object} models.Tweets
 // @Failure 400 {object} models.Error
+// @Failure 429 {object} models.Error
 // @Failure 500 {object} models.Error
 // @Router /tweet/get_new [get]
 func (h *tweetHandler) GetNewTweets(c *gin.Context) {
@@ -317,8 +316,8 @@ func (h *tweetHandler) GetNewTweets(c *gin.Context) {
 	}
 	req, err := h.tweetService.RecommendTweets(c.Request.Context(), &res)
 	if err != nil {
-		h.logger.Error("Error occurred while getting tweets", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		h.logger.Error("Error occurred while getting tweets", "error", err)
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"data": req})
@@ -334,13 +333,14 @@ func (h *tweetHandler) GetNewTweets(c *gin.Context) {
 // @Param ReTweet body tweet.ReTweetReq true "Post retweet"
 // @Success 200 {object} tweet.TweetResponse
 // @Failure 400 {object} models.Error
+// @Failure 429 {object} models.Error
 // @Failure 500 {object} models.Error
 // @Router /tweet/re_tweet [post]
 func (h *tweetHandler) ReTweet(c *gin.Context) {
 	req := pb.ReTweetReq{}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(err).SetType(gin.ErrorTypeBind)
 		return
 	}
 
@@ -349,8 +349,8 @@ func (h *tweetHandler) ReTweet(c *gin.Context) {
 	res, err := h.tweetService.ReTweet(c.Request.Context(), &req)
 	if err != nil {
 		log.Println(err)
-		h.logger.Error("Error occurred while retweeting tweet", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		h.logger.Error("Error occurred while retweeting tweet", "error", err)
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"data": res})
@@ -4,14 +4,16 @@ import (
 	pb "apigateway/genproto/user"
 	"apigateway/pkg/models"
 	"apigateway/service"
-	"context"
-	"database/sql"
-	"errors"
 	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"log"
 	"log/slog"
 	"net/http"
+	"net/url"
+	"path"
 	"strconv"
+	"strings"
 )
 
 type UserHandler interface {
@@ -32,6 +34,14 @@ type UserHandler interface {
 	GetUserFollowers(c *gin.Context)
 	GetUserFollows(c *gin.Context)
 	MostPopularUser(c *gin.Context)
+	ChangeUsername(c *gin.Context)
+	CheckUsername(c *gin.Context)
+	SuggestUsers(c *gin.Context)
+	SearchUsers(c *gin.Context)
+	Block(c *gin.Context)
+	Unblock(c *gin.Context)
+	GetRelationships(c *gin.Context)
+	GetMutualFollowers(c *gin.Context)
 }
 
 type userHandler struct {
@@ -67,8 +77,8 @@ func (h *userHandler) Create(c *gin.Context) {
 	var user models.CreateRequest
 
 	if err := c.ShouldBindJSON(&user); err != nil {
-		h.logger.Error("Error occurred while binding json", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		h.logger.Error("Error occurred while binding json", "error", err)
+		c.Error(err).SetType(gin.ErrorTypeBind)
 		return
 	}
 
@@ -83,10 +93,10 @@ func (h *userHandler) Create(c *gin.Context) {
 		Bio:         user.Bio,
 	}
 
-	res, err := h.userService.Create(context.Background(), &req)
+	res, err := h.userService.Create(c.Request.Context(), &req)
 	if err != nil {
-		h.logger.Error("Error occurred while creating user", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		h.logger.Error("Error occurred while creating user", "error", err)
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusCreated, res)
@@ -108,10 +118,10 @@ func (h *userHandler) GetProfile(c *gin.Context) {
 		UserId: c.MustGet("user_id").(string),
 	}
 
-	res, err := h.userService.GetProfile(context.Background(), &req)
+	res, err := h.userService.GetProfile(c.Request.Context(), &req)
 	if err != nil {
-		h.logger.Error("Error occurred while getting user", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		h.logger.Error("Error occurred while getting user", "error", err)
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusOK, res)
@@ -134,10 +144,10 @@ func (h *userHandler) GetProfileById(c *gin.Context) {
 		UserId: c.Param("user_id"),
 	}
 
-	res, err := h.userService.GetProfile(context.Background(), &req)
+	res, err := h.userService.GetProfile(c.Request.Context(), &req)
 	if err != nil {
-		h.logger.Error("Error occurred while getting user", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		h.logger.Error("Error occurred while getting user", "error", err)
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusOK, res)
@@ -145,7 +155,7 @@ func (h *userHandler) GetProfileById(c *gin.Context) {
 
 // UpdateProfile godoc
 // @Summary Update User Profile
-// @Description Update user profile details
+// @Description Update user profile details. The username is changed with /user/change_username
 // @Security BearerAuth
 // @Tags User
 // @Accept json
@@ -158,8 +168,8 @@ func (h *userHandler) GetProfileById(c *gin.Context) {
 func (h *userHandler) UpdateProfile(c *gin.Context) {
 	var user models.UpdateProfileRequest
 	if err := c.ShouldBindJSON(&user); err != nil {
-		h.logger.Error("Error occurred while binding json", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		h.logger.Error("Error occurred while binding json", "error", err)
+		c.Error(err).SetType(gin.ErrorTypeBind)
 		return
 	}
 	res := pb.UpdateProfileRequest{
@@ -173,10 +183,10 @@ func (h *userHandler) UpdateProfile(c *gin.Context) {
 		ProfileImage: user.ProfileImage,
 	}
 
-	req, err := h.userService.UpdateProfile(context.Background(), &res)
+	req, err := h.userService.UpdateProfile(c.Request.Context(), &res)
 	if err != nil {
-		h.logger.Error("Error occurred while updating user", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		h.logger.Error("Error occurred while updating user", "error", err)
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusOK, req)
@@ -184,7 +194,7 @@ func (h *userHandler) UpdateProfile(c *gin.Context) {
 
 // UpdateProfileById godoc
 // @Summary Update User Profile
-// @Description Update user profile details
+// @Description Update user profile details. The username is changed with /user/change_username
 // @Security BearerAuth
 // @Tags Admin
 // @Accept json
@@ -198,8 +208,8 @@ func (h *userHandler) UpdateProfile(c *gin.Context) {
 func (h *userHandler) UpdateProfileById(c *gin.Context) {
 	var user models.UpdateProfileRequest
 	if err := c.ShouldBindJSON(&user); err != nil {
-		h.logger.Error("Error occurred while binding json", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		h.logger.Error("Error occurred while binding json", "error", err)
+		c.Error(err).SetType(gin.ErrorTypeBind)
 		return
 	}
 	res := pb.UpdateProfileRequest{
@@ -213,10 +223,10 @@ func (h *userHandler) UpdateProfileById(c *gin.Context) {
 		ProfileImage: user.ProfileImage,
 	}
 
-	req, err := h.userService.UpdateProfile(context.Background(), &res)
+	req, err := h.userService.UpdateProfile(c.Request.Context(), &res)
 	if err != nil {
-		h.logger.Error("Error occurred while updating user", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		h.logger.Error("Error occurred while updating user", "error", err)
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusOK, req)
@@ -237,15 +247,15 @@ func (h *userHandler) UpdateProfileById(c *gin.Context) {
 func (h *userHandler) ChangePassword(c *gin.Context) {
 	var user models.ChangePasswordRequest
 	if err := c.ShouldBindJSON(&user); err != nil {
-		h.logger.Error("Error occurred while binding json", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		h.logger.Error("Error occurred while binding json", "error", err)
+		c.Error(err).SetType(gin.ErrorTypeBind)
 		return
 	}
 
 	// Validate request body
 	if user.CurrentPassword == "" || user.NewPassword == "" {
 		h.logger.Error("Invalid request body")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		c.Error(status.Error(codes.InvalidArgument, "current_password and new_password are required"))
 		return
 	}
 
@@ -255,20 +265,10 @@ func (h *userHandler) ChangePassword(c *gin.Context) {
 		NewPassword:     user.NewPassword,
 	}
 
-	req, err := h.userService.ChangePassword(context.Background(), &res)
+	req, err := h.userService.ChangePassword(c.Request.Context(), &res)
 	if err != nil {
-		h.logger.Error("Error occurred while changing user", err)
-		if errors.Is(err, sql.ErrNoRows) {
-			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
-		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		}
-		return
-	}
-
-	if req == nil {
-		h.logger.Error("Nil response from userService")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		h.logger.Error("Error occurred while changing user", "error", err)
+		c.Error(err)
 		return
 	}
 
@@ -290,18 +290,18 @@ func (h *userHandler) ChangePassword(c *gin.Context) {
 func (h *userHandler) ChangeProfileImage(c *gin.Context) {
 	var user models.URL
 	if err := c.ShouldBindJSON(&user); err != nil {
-		h.logger.Error("Error occurred while binding json", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		h.logger.Error("Error occurred while binding json", "error", err)
+		c.Error(err).SetType(gin.ErrorTypeBind)
 		return
 	}
 	res := pb.URL{
 		UserId: c.MustGet("user_id").(string),
 		Url:    user.URL,
 	}
-	req, err := h.userService.ChangeProfileImage(context.Background(), &res)
+	req, err := h.userService.ChangeProfileImage(c.Request.Context(), &res)
 	if err != nil {
-		h.logger.Error("Error occurred while changing user", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		h.logger.Error("Error occurred while changing user", "error", err)
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusOK, req)
@@ -323,18 +323,18 @@ func (h *userHandler) ChangeProfileImage(c *gin.Context) {
 func (h *userHandler) ChangeProfileImageById(c *gin.Context) {
 	var user models.URL
 	if err := c.ShouldBindJSON(&user); err != nil {
-		h.logger.Error("Error occurred while binding json", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		h.logger.Error("Error occurred while binding json", "error", err)
+		c.Error(err).SetType(gin.ErrorTypeBind)
 		return
 	}
 	res := pb.URL{
 		UserId: c.Param("user_id"),
 		Url:    user.URL,
 	}
-	req, err := h.userService.ChangeProfileImage(context.Background(), &res)
+	req, err := h.userService.ChangeProfileImage(c.Request.Context(), &res)
 	if err != nil {
-		h.logger.Error("Error occurred while changing user", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		h.logger.Error("Error occurred while changing user", "error", err)
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusOK, req)
@@ -342,14 +342,15 @@ func (h *userHandler) ChangeProfileImageById(c *gin.Context) {
 
 // FetchUsers godoc
 // @Summary Fetch Users
-// @Description Retrieve a list of users with filtering options
+// @Description Retrieve a list of users with filtering options. Emails are only shown to admins.
 // @Security BearerAuth
 // @Tags User
 // @Accept json
 // @Produce json
 // @Param page query int false "Page number"
 // @Param limit query int false "Number of users per page"
-// @Param name query string false "Username"
+// @Param name query string false "Part of the username or first name"
+// @Param role query string false "Role: user, admin or c-admin"
 // @Success 200 {object} user.UserResponses
 // @Failure 400 {object} models.Error
 // @Failure 500 {object} models.Error
@@ -373,12 +374,13 @@ func (h *userHandler) FetchUsers(c *gin.Context) {
 		Page:      int32(page),
 		Limit:     int32(limit),
 		FirstName: name,
+		Role:      c.Query("role"),
 	}
 
-	req, err := h.userService.FetchUsers(context.Background(), &res)
+	req, err := h.userService.FetchUsers(c.Request.Context(), &res)
 	if err != nil {
-		h.logger.Error("Error occurred while fetching users", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		h.logger.Error("Error occurred while fetching users", "error", err)
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusOK, req)
@@ -400,10 +402,10 @@ func (h *userHandler) ListOfFollowing(c *gin.Context) {
 	res := pb.Id{
 		UserId: c.MustGet("user_id").(string),
 	}
-	req, err := h.userService.ListOfFollowing(context.Background(), &res)
+	req, err := h.userService.ListOfFollowing(c.Request.Context(), &res)
 	if err != nil {
-		h.logger.Error("Error occurred while listing following", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		h.logger.Error("Error occurred while listing following", "error", err)
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusOK, req)
@@ -418,17 +420,23 @@ func (h *userHandler) ListOfFollowing(c *gin.Context) {
 // @Produce json
 // @Param username path string true "Username"
 // @Success 200 {object} user.Followings
+// @Success 307 "A former username redirects to the current one"
 // @Failure 400 {object} models.Error
+// @Failure 404 {object} models.Error
 // @Failure 500 {object} models.Error
 // @Router /user/list_of_following_by_username/{username} [get]
 func (h *userHandler) ListOfFollowingByUsername(c *gin.Context) {
+	if !h.currentUsername(c) {
+		return
+	}
+
 	res := pb.Id{
 		UserId: c.Param("username"),
 	}
-	req, err := h.userService.ListOfFollowingByUsername(context.Background(), &res)
+	req, err := h.userService.ListOfFollowingByUsername(c.Request.Context(), &res)
 	if err != nil {
-		h.logger.Error("Error occurred while listing following", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		h.logger.Error("Error occurred while listing following", "error", err)
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusOK, req)
@@ -443,17 +451,23 @@ func (h *userHandler) ListOfFollowingByUsername(c *gin.Context) {
 // @Produce json
 // @Param username path string true "Username"
 // @Success 200 {object} user.Followers
+// @Success 307 "A former username redirects to the current one"
 // @Failure 400 {object} models.Error
+// @Failure 404 {object} models.Error
 // @Failure 500 {object} models.Error
 // @Router /user/list_of_followers_by_username/{username} [get]
 func (h *userHandler) ListOfFollowersByUsername(c *gin.Context) {
+	if !h.currentUsername(c) {
+		return
+	}
+
 	res := pb.Id{
 		UserId: c.Param("username"),
 	}
-	req, err := h.userService.ListOfFollowersByUsername(context.Background(), &res)
+	req, err := h.userService.ListOfFollowersByUsername(c.Request.Context(), &res)
 	if err != nil {
-		h.logger.Error("Error occurred while listing followers", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		h.logger.Error("Error occurred while listing followers", "error", err)
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusOK, req)
@@ -474,10 +488,10 @@ func (h *userHandler) ListOfFollowers(c *gin.Context) {
 	res := pb.Id{
 		UserId: c.MustGet("user_id").(string),
 	}
-	req, err := h.userService.ListOfFollowers(context.Background(), &res)
+	req, err := h.userService.ListOfFollowers(c.Request.Context(), &res)
 	if err != nil {
-		h.logger.Error("Error occurred while listing followers", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		h.logger.Error("Error occurred while listing followers", "error", err)
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusOK, req)
@@ -499,10 +513,10 @@ func (h *userHandler) DeleteUser(c *gin.Context) {
 	res := pb.Id{
 		UserId: c.Param("user_id"),
 	}
-	req, err := h.userService.DeleteUser(context.Background(), &res)
+	req, err := h.userService.DeleteUser(c.Request.Context(), &res)
 	if err != nil {
-		h.logger.Error("Error occurred while deleting user", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		h.logger.Error("Error occurred while deleting user", "error", err)
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusOK, models.Message{Message: "Successfully deleted user" + req.String()})
@@ -518,13 +532,14 @@ func (h *userHandler) DeleteUser(c *gin.Context) {
 // @Param Follow body models.FollowReq true "post user"
 // @Success 200 {object} models.FollowRes
 // @Failure 400 {object} models.Error
+// @Failure 429 {object} models.Error
 // @Failure 500 {object} models.Error
 // @Router /user/follow [post]
 func (h *userHandler) Follow(c *gin.Context) {
 	var user models.FollowReq
 	if err := c.ShouldBindJSON(&user); err != nil {
-		h.logger.Error("Error occurred while binding json", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		h.logger.Error("Error occurred while binding json", "error", err)
+		c.Error(err).SetType(gin.ErrorTypeBind)
 		return
 	}
 
@@ -533,10 +548,10 @@ func (h *userHandler) Follow(c *gin.Context) {
 		FollowerId:  c.MustGet("user_id").(string),
 	}
 
-	req, err := h.userService.Follow(context.Background(), &res)
+	req, err := h.userService.Follow(c.Request.Context(), &res)
 	if err != nil {
-		h.logger.Error("Error occurred while following", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		h.logger.Error("Error occurred while following", "error", err)
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusOK, req)
@@ -557,8 +572,8 @@ func (h *userHandler) Follow(c *gin.Context) {
 func (h *userHandler) Unfollow(c *gin.Context) {
 	var user models.FollowReq
 	if err := c.ShouldBindJSON(&user); err != nil {
-		h.logger.Error("Error occurred while binding json", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		h.logger.Error("Error occurred while binding json", "error", err)
+		c.Error(err).SetType(gin.ErrorTypeBind)
 		return
 	}
 	res := pb.FollowReq{
@@ -566,10 +581,10 @@ func (h *userHandler) Unfollow(c *gin.Context) {
 		FollowerId:  c.MustGet("user_id").(string),
 	}
 
-	req, err := h.userService.Unfollow(context.Background(), &res)
+	req, err := h.userService.Unfollow(c.Request.Context(), &res)
 	if err != nil {
-		h.logger.Error("Error occurred while following", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		h.logger.Error("Error occurred while following", "error", err)
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusOK, req)
@@ -590,10 +605,10 @@ func (h *userHandler) GetUserFollowers(c *gin.Context) {
 	res := pb.Id{
 		UserId: c.MustGet("user_id").(string),
 	}
-	req, err := h.userService.GetUserFollowers(context.Background(), &res)
+	req, err := h.userService.GetUserFollowers(c.Request.Context(), &res)
 	if err != nil {
-		h.logger.Error("Error occurred while following", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		h.logger.Error("Error occurred while following", "error", err)
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusOK, req)
@@ -614,10 +629,10 @@ func (h *userHandler) GetUserFollows(c *gin.Context) {
 	res := pb.Id{
 		UserId: c.MustGet("user_id").(string),
 	}
-	req, err := h.userService.GetUserFollows(context.Background(), &res)
+	req, err := h.userService.GetUserFollows(c.Request.Context(), &res)
 	if err != nil {
-		h.logger.Error("Error occurred while following", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		h.logger.Error("Error occurred while following", "error", err)
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusOK, req)
@@ -625,7 +640,7 @@ func (h *userHandler) GetUserFollows(c *gin.Context) {
 
 // MostPopularUser godoc
 // @Summary Most Popular User
-// @Description Retrieve the most popular user based on criteria
+// @Description Retrieve the user who gained the most followers recently
 // @Security BearerAuth
 // @Tags User
 // @Accept json
@@ -636,11 +651,320 @@ func (h *userHandler) GetUserFollows(c *gin.Context) {
 // @Router /user/most_popular [get]
 func (h *userHandler) MostPopularUser(c *gin.Context) {
 	res := pb.Void{}
-	req, err := h.userService.MostPopularUser(context.Background(), &res)
+	req, err := h.userService.MostPopularUser(c.Request.Context(), &res)
 	if err != nil {
-		h.logger.Error("Error occurred while most popular user", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		h.logger.Error("Error occurred while most popular user", "error", err)
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusOK, req)
 }
+
+// SuggestUsers godoc
+// @Summary Who to follow
+// @Description Suggest users to follow, ranked by how many of the people you follow follow them, whether they follow you and their follower count
+// @Security BearerAuth
+// @Tags User
+// @Accept json
+// @Produce json
+// @Success 200 {object} user.Suggestions
+// @Failure 500 {object} models.Error
+// @Router /user/suggestions [get]
+func (h *userHandler) SuggestUsers(c *gin.Context) {
+	userID, ok := callerID(c)
+	if !ok {
+		return
+	}
+
+	req := pb.Id{
+		UserId: userID,
+	}
+
+	res, err := h.userService.SuggestUsers(c.Request.Context(), &req)
+	if err != nil {
+		h.logger.Error("Error occurred while suggesting users", "error", err)
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// SearchUsers godoc
+// @Summary Search Users
+// @Description Search users by username, first or last name. With prefix only names starting with the query match, for typeahead; otherwise similar names match too. Users with more followers rank higher among equal matches
+// @Security BearerAuth
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param q query string true "Query"
+// @Param prefix query bool false "Match names starting with the query only"
+// @Param page query int false "Page number"
+// @Param limit query int false "Number of users per page"
+// @Success 200 {object} user.SearchResults
+// @Failure 400 {object} models.Error
+// @Failure 500 {object} models.Error
+// @Router /user/search [get]
+func (h *userHandler) SearchUsers(c *gin.Context) {
+	page, err := strconv.Atoi(c.Query("page"))
+	if err != nil {
+		page = 1
+	}
+	limit, err := strconv.Atoi(c.Query("limit"))
+	if err != nil {
+		limit = 20
+	}
+	prefix, _ := strconv.ParseBool(c.Query("prefix"))
+
+	userID, ok := callerID(c)
+	if !ok {
+		return
+	}
+
+	req := pb.SearchReq{
+		Query:  c.Query("q"),
+		Prefix: prefix,
+		Page:   int32(page),
+		Limit:  int32(limit),
+		UserId: userID,
+	}
+
+	res, err := h.userService.SearchUsers(c.Request.Context(), &req)
+	if err != nil {
+		h.logger.Error("Error occurred while searching users", "error", err)
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// Block godoc
+// @Summary Block User
+// @Description Block a user. Follows between you are removed and neither can follow the other
+// @Security BearerAuth
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param user_id path string true "User ID"
+// @Success 200 {object} models.Message
+// @Failure 400 {object} models.Error
+// @Router /user/block/{user_id} [post]
+func (h *userHandler) Block(c *gin.Context) {
+	userID, ok := callerID(c)
+	if !ok {
+		return
+	}
+
+	req := pb.BlockReq{
+		BlockerId: userID,
+		BlockedId: c.Param("user_id"),
+	}
+
+	_, err := h.userService.Block(c.Request.Context(), &req)
+	if err != nil {
+		h.logger.Error("Error occurred while blocking user", "error", err)
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "user blocked"})
+}
+
+// Unblock godoc
+// @Summary Unblock User
+// @Description Unblock a user
+// @Security BearerAuth
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param user_id path string true "User ID"
+// @Success 200 {object} models.Message
+// @Failure 400 {object} models.Error
+// @Router /user/block/{user_id} [delete]
+func (h *userHandler) Unblock(c *gin.Context) {
+	userID, ok := callerID(c)
+	if !ok {
+		return
+	}
+
+	req := pb.BlockReq{
+		BlockerId: userID,
+		BlockedId: c.Param("user_id"),
+	}
+
+	_, err := h.userService.Unblock(c.Request.Context(), &req)
+	if err != nil {
+		h.logger.Error("Error occurred while unblocking user", "error", err)
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "user unblocked"})
+}
+
+// currentUsername redirects a request for a former username to the same
+// route with the current one. It reports whether the request can go on.
+func (h *userHandler) currentUsername(c *gin.Context) bool {
+	res, err := h.userService.ResolveUsername(c.Request.Context(), &pb.Username{Username: c.Param("username")})
+	if err != nil {
+		if status.Code(err) != codes.NotFound {
+			h.logger.Error("Error occurred while resolving username", "error", err)
+		}
+		c.Error(err)
+		return false
+	}
+	if !res.Renamed {
+		return true
+	}
+
+	// Temporary, as the account may be renamed again and the redirect must
+	// not be cached.
+	target := url.URL{Path: path.Join(path.Dir(c.Request.URL.Path), url.PathEscape(res.Username)), RawQuery: c.Request.URL.RawQuery}
+	c.Redirect(http.StatusTemporaryRedirect, target.String())
+	return false
+}
+
+// ChangeUsername godoc
+// @Summary Change Username
+// @Description Change your username. It can be changed again only after a cooldown; the old one keeps pointing to you
+// @Security BearerAuth
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param ChangeUsername body models.ChangeUsernameRequest true "New username"
+// @Success 200 {object} models.UserResponse
+// @Failure 400 {object} models.Error
+// @Failure 409 {object} models.Error
+// @Failure 500 {object} models.Error
+// @Router /user/change_username [put]
+func (h *userHandler) ChangeUsername(c *gin.Context) {
+	var body models.ChangeUsernameRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	userID, ok := callerID(c)
+	if !ok {
+		return
+	}
+
+	req := pb.ChangeUsernameReq{
+		UserId:   userID,
+		Username: body.Username,
+	}
+
+	res, err := h.userService.ChangeUsername(c.Request.Context(), &req)
+	if err != nil {
+		h.logger.Error("Error occurred while changing username", "error", err)
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// CheckUsername godoc
+// @Summary Check Username
+// @Description Tell whether a username can be taken, and why not
+// @Security BearerAuth
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param username path string true "Username"
+// @Success 200 {object} user.UsernameAvailability
+// @Failure 500 {object} models.Error
+// @Router /user/check_username/{username} [get]
+func (h *userHandler) CheckUsername(c *gin.Context) {
+	res, err := h.userService.CheckUsername(c.Request.Context(), &pb.Username{Username: c.Param("username")})
+	if err != nil {
+		h.logger.Error("Error occurred while checking username", "error", err)
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// targetIDs reads the comma separated ids query parameter.
+func targetIDs(c *gin.Context) []string {
+	var ids []string
+	for _, id := range strings.Split(c.Query("ids"), ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// GetRelationships godoc
+// @Summary Relationships
+// @Description Tell, for each user, whether you follow them, they follow you and whether either blocked the other. Takes up to 100 users, e.g. the authors of a page of tweets
+// @Security BearerAuth
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param ids query string true "Comma separated user IDs"
+// @Success 200 {object} user.Relationships
+// @Failure 400 {object} models.Error
+// @Failure 500 {object} models.Error
+// @Router /user/relationships [get]
+func (h *userHandler) GetRelationships(c *gin.Context) {
+	userID, ok := callerID(c)
+	if !ok {
+		return
+	}
+
+	req := pb.RelationshipsReq{
+		ViewerId:  userID,
+		TargetIds: targetIDs(c),
+	}
+
+	res, err := h.userService.GetRelationships(c.Request.Context(), &req)
+	if err != nil {
+		h.logger.Error("Error occurred while getting relationships", "error", err)
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// GetMutualFollowers godoc
+// @Summary Followers You Know
+// @Description For each user, a page of the people you follow who follow them and how many there are in all. Takes up to 100 users
+// @Security BearerAuth
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param ids query string true "Comma separated user IDs"
+// @Param page query int false "Page number"
+// @Param limit query int false "Number of followers per user"
+// @Success 200 {object} user.MutualFollowers
+// @Failure 400 {object} models.Error
+// @Failure 500 {object} models.Error
+// @Router /user/mutual_followers [get]
+func (h *userHandler) GetMutualFollowers(c *gin.Context) {
+	page, err := strconv.Atoi(c.Query("page"))
+	if err != nil {
+		page = 1
+	}
+	limit, err := strconv.Atoi(c.Query("limit"))
+	if err != nil {
+		limit = 3
+	}
+
+	userID, ok := callerID(c)
+	if !ok {
+		return
+	}
+
+	req := pb.MutualFollowersReq{
+		ViewerId:  userID,
+		TargetIds: targetIDs(c),
+		Page:      int32(page),
+		Limit:     int32(limit),
+	}
+
+	res, err := h.userService.GetMutualFollowers(c.Request.Context(), &req)
+	if err != nil {
+		h.logger.Error("Error occurred while getting mutual followers", "error", err)
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"apigateway/pkg/models"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errorKind is what a gRPC code becomes at the gateway.
+type errorKind struct {
+	status int
+	code   string
+}
+
+var errorKinds = map[codes.Code]errorKind{
+	codes.InvalidArgument:    {http.StatusBadRequest, "INVALID_ARGUMENT"},
+	codes.OutOfRange:         {http.StatusBadRequest, "INVALID_ARGUMENT"},
+	codes.FailedPrecondition: {http.StatusBadRequest, "FAILED_PRECONDITION"},
+	codes.Unauthenticated:    {http.StatusUnauthorized, "UNAUTHENTICATED"},
+	codes.PermissionDenied:   {http.StatusForbidden, "PERMISSION_DENIED"},
+	codes.NotFound:           {http.StatusNotFound, "NOT_FOUND"},
+	codes.AlreadyExists:      {http.StatusConflict, "ALREADY_EXISTS"},
+	codes.Aborted:            {http.StatusConflict, "ABORTED"},
+	codes.ResourceExhausted:  {http.StatusTooManyRequests, "RESOURCE_EXHAUSTED"},
+	codes.Canceled:           {499, "CANCELED"},
+	codes.DeadlineExceeded:   {http.StatusGatewayTimeout, "DEADLINE_EXCEEDED"},
+	codes.Unavailable:        {http.StatusServiceUnavailable, "UNAVAILABLE"},
+	codes.Unimplemented:      {http.StatusNotImplemented, "UNIMPLEMENTED"},
+}
+
+var internalError = errorKind{http.StatusInternalServerError, "INTERNAL"}
+
+// ErrorMiddleware writes the response of requests that failed. Handlers and
+// middleware record the failure with ctx.Error and stop: binding errors
+// (gin.ErrorTypeBind) become 400, gRPC status errors are mapped by their
+// code, and anything else is a 500 whose details are logged but not shown.
+// The body is always a models.Error.
+func ErrorMiddleware(logger *slog.Logger) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Next()
+
+		last := ctx.Errors.Last()
+		if last == nil || ctx.Writer.Written() {
+			return
+		}
+
+		kind, message := classify(last)
+		if kind.status >= http.StatusInternalServerError {
+			logger.Error("request failed",
+				"method", ctx.Request.Method,
+				"path", ctx.FullPath(),
+				"request_id", ctx.Writer.Header().Get("X-Request-Id"),
+				"error", last.Err)
+		}
+		ctx.AbortWithStatusJSON(kind.status, models.Error{
+			Error: models.ErrorBody{Code: kind.code, Message: message},
+		})
+	}
+}
+
+// abort records err for ErrorMiddleware and stops the request.
+func abort(ctx *gin.Context, err error) {
+	_ = ctx.Error(err)
+	ctx.Abort()
+}
+
+func classify(err *gin.Error) (errorKind, string) {
+	if err.IsType(gin.ErrorTypeBind) {
+		return errorKind{http.StatusBadRequest, "INVALID_ARGUMENT"}, err.Error()
+	}
+	st, ok := status.FromError(err.Err)
+	if !ok {
+		return internalError, "internal error"
+	}
+	kind, ok := errorKinds[st.Code()]
+	if !ok {
+		return internalError, "internal error"
+	}
+	return kind, st.Message()
+}
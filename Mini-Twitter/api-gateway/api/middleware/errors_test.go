@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"apigateway/pkg/models"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestErrorMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	tests := []struct {
+		name    string
+		handler gin.HandlerFunc
+		status  int
+		code    string
+		message string
+	}{
+		{"not found", func(c *gin.Context) {
+			c.Error(status.Error(codes.NotFound, "tweet not found"))
+		}, http.StatusNotFound, "NOT_FOUND", "tweet not found"},
+		{"already exists", func(c *gin.Context) {
+			c.Error(status.Error(codes.AlreadyExists, "username is taken"))
+		}, http.StatusConflict, "ALREADY_EXISTS", "username is taken"},
+		{"permission denied", func(c *gin.Context) {
+			c.Error(status.Error(codes.PermissionDenied, "admins only"))
+		}, http.StatusForbidden, "PERMISSION_DENIED", "admins only"},
+		{"bind", func(c *gin.Context) {
+			c.Error(errors.New("bad json")).SetType(gin.ErrorTypeBind)
+		}, http.StatusBadRequest, "INVALID_ARGUMENT", "bad json"},
+		{"internal status", func(c *gin.Context) {
+			c.Error(status.Error(codes.Internal, "internal error"))
+		}, http.StatusInternalServerError, "INTERNAL", "internal error"},
+		{"plain error is hidden", func(c *gin.Context) {
+			c.Error(errors.New("pq: connection refused"))
+		}, http.StatusInternalServerError, "INTERNAL", "internal error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(ErrorMiddleware(logger))
+			router.GET("/", tt.handler)
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.status)
+			}
+			var body models.Error
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			if body.Error.Code != tt.code || body.Error.Message != tt.message {
+				t.Fatalf("body = %+v, want %s %q", body.Error, tt.code, tt.message)
+			}
+		})
+	}
+}
+
+func TestErrorMiddlewareKeepsWrittenResponses(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(ErrorMiddleware(slog.New(slog.NewTextHandler(io.Discard, nil))))
+	router.GET("/", func(c *gin.Context) {
+		c.Error(errors.New("logged only"))
+		c.JSON(http.StatusOK, gin.H{"data": "ok"})
+	})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+}
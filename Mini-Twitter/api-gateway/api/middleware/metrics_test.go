@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMetricsMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(MetricsMiddleware())
+	router.GET("/metrics_test/:id", func(c *gin.Context) {
+		if got := testutil.ToFloat64(httpInFlight); got != 1 {
+			t.Errorf("in flight while handling = %v, want 1", got)
+		}
+		c.Status(http.StatusTeapot)
+	})
+
+	found := httpRequests.WithLabelValues(http.MethodGet, "/metrics_test/:id", "418")
+	unmatched := httpRequests.WithLabelValues(http.MethodGet, "unmatched", "404")
+	beforeFound, beforeUnmatched := testutil.ToFloat64(found), testutil.ToFloat64(unmatched)
+
+	for _, path := range []string{"/metrics_test/1", "/metrics_test/2", "/no/such/route"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	// Both ids are counted under the route pattern.
+	if got := testutil.ToFloat64(found); got != beforeFound+2 {
+		t.Errorf("route requests = %v, want %v", got, beforeFound+2)
+	}
+	if got := testutil.ToFloat64(unmatched); got != beforeUnmatched+1 {
+		t.Errorf("unmatched requests = %v, want %v", got, beforeUnmatched+1)
+	}
+	if got := testutil.ToFloat64(httpInFlight); got != 0 {
+		t.Errorf("in flight = %v, want 0", got)
+	}
+}
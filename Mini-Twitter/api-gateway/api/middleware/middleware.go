@@ -2,32 +2,46 @@ package middleware
 
 import (
 	"apigateway/pkg/token"
+	"apigateway/service"
 	"fmt"
 	"github.com/casbin/casbin/v2"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"net/http"
 )
 
 type casbinPermission struct {
-	enforcer *casbin.Enforcer
+	enforcer    *casbin.Enforcer
+	revocations *service.RevocationList
 }
 
 func (c *casbinPermission) GetRole(ctx *gin.Context) (string, int) {
 	Token := ctx.GetHeader("Authorization")
 	if Token == "" {
-		ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "token is empty"})
+		abort(ctx, status.Error(codes.Unauthenticated, "token is empty"))
 		return "Unauthorized", http.StatusUnauthorized
 	}
-	claims, err := token.ExtractClaims(Token)
+	claims, err := token.ExtractClaims(ctx.Request.Context(), Token)
 	if err != nil {
-		ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		abort(ctx, status.Error(codes.Unauthenticated, err.Error()))
+		return "Unauthorized", http.StatusUnauthorized
+	}
+	revoked, err := c.revocations.IsRevoked(ctx, claims)
+	if err != nil {
+		abort(ctx, fmt.Errorf("failed to check token revocation: %w", err))
+		return "Unauthorized", http.StatusInternalServerError
+	}
+	if revoked {
+		abort(ctx, status.Error(codes.Unauthenticated, "token has been revoked"))
 		return "Unauthorized", http.StatusUnauthorized
 	}
 	role, ok := claims["role"].(string)
 	if !ok {
-		ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "role is empty"})
+		abort(ctx, status.Error(codes.Unauthenticated, "role is empty"))
 		return "Unauthorized", http.StatusUnauthorized
 	}
 	ctx.Set("user_id", claims["user_id"])
@@ -50,37 +64,75 @@ func (c *casbinPermission) CheckPermission(ctx *gin.Context) (bool, error) {
 	return allow, nil
 }
 
-func PermissionMiddleware(enf *casbin.Enforcer) gin.HandlerFunc {
-	casbHandler := &casbinPermission{enforcer: enf}
+func PermissionMiddleware(enf *casbin.Enforcer, revocations *service.RevocationList) gin.HandlerFunc {
+	casbHandler := &casbinPermission{enforcer: enf, revocations: revocations}
 	return func(ctx *gin.Context) {
 		res, err := casbHandler.CheckPermission(ctx)
 
 		if err != nil {
-			ctx.AbortWithError(500, err)
+			// GetRole has already recorded why the caller is turned away.
+			if !ctx.IsAborted() {
+				abort(ctx, err)
+			}
 			return
 		}
 		if !res {
-			ctx.AbortWithStatusJSON(401, gin.H{"error": "You dont have permission"})
+			abort(ctx, status.Error(codes.PermissionDenied, "You dont have permission"))
 			return
 		}
 		auth := ctx.GetHeader("Authorization")
 		if auth == "" {
-			ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "You dont have permission"})
+			abort(ctx, status.Error(codes.Unauthenticated, "token is empty"))
 			return
 		}
 
-		valid, err := token.ValidateToken(auth)
+		valid, err := token.ValidateToken(ctx.Request.Context(), auth)
 		if err != nil || !valid {
-			ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Token invalid: %s", err)})
+			abort(ctx, status.Errorf(codes.Unauthenticated, "Token invalid: %s", err))
 			return
 		}
 
-		claims, err := token.ExtractClaims(auth)
+		claims, err := token.ExtractClaims(ctx.Request.Context(), auth)
 		if err != nil {
-			ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Token invalid claims: %s", err)})
+			abort(ctx, status.Errorf(codes.Unauthenticated, "Token invalid claims: %s", err))
 			return
 		}
 		ctx.Set("claims", claims)
+		userID, _ := claims["user_id"].(string)
+		role, _ := claims["role"].(string)
+		reqCtx := service.WithActor(service.WithAccessToken(ctx.Request.Context(), auth), userID, role)
+		ctx.Request = ctx.Request.WithContext(reqCtx)
+		ctx.Next()
+	}
+}
+
+// IdempotencyMiddleware passes the client's Idempotency-Key header down to the
+// message broker, so a retried request publishes the same command key. Keys
+// are scoped to the caller, so two users cannot collide.
+func IdempotencyMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		key := ctx.GetHeader("Idempotency-Key")
+		if key != "" {
+			if userID, ok := ctx.Get("user_id"); ok {
+				key = fmt.Sprintf("%v:%s", userID, key)
+			}
+			ctx.Request = ctx.Request.WithContext(service.WithIdempotencyKey(ctx.Request.Context(), key))
+		}
+		ctx.Next()
+	}
+}
+
+// RequestIDMiddleware gives every request an id, taken from the client's
+// X-Request-Id header when it sent one. It is returned to the client and
+// passed on to the services called.
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id := ctx.GetHeader("X-Request-Id")
+		if id == "" || len(id) > 128 {
+			id = uuid.NewString()
+		}
+		ctx.Header("X-Request-Id", id)
+		ctx.Request = ctx.Request.WithContext(service.WithRequestID(ctx.Request.Context(), id))
 		ctx.Next()
 	}
 }
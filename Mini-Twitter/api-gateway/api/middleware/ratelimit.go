@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"apigateway/pkg/config"
+	"apigateway/service"
+	"fmt"
+	"log/slog"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RateLimitMiddleware throttles the requests to a route group, named by
+// group, with a token bucket per user and one per client address. It goes
+// after PermissionMiddleware, which tells who the user is. Every response
+// carries the X-RateLimit-* headers of the bucket closest to running out;
+// a request over the limit gets 429 with Retry-After. When Redis cannot be
+// reached, requests are let through rather than failing the gateway. The
+// client address is ctx.ClientIP, so X-Forwarded-For only counts when the
+// engine trusts the proxy the request came through.
+func RateLimitMiddleware(limiter *service.RateLimiter, group string, perUser, perIP config.RateLimit, logger *slog.Logger) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		buckets := []service.Bucket{{Key: group + ":ip:" + ctx.ClientIP(), Limit: perIP}}
+		if userID, ok := ctx.Get("user_id"); ok {
+			buckets = append(buckets, service.Bucket{Key: fmt.Sprintf("%s:user:%v", group, userID), Limit: perUser})
+		}
+
+		d, err := limiter.Take(ctx, buckets...)
+		if err != nil {
+			logger.Warn("Rate limiter unavailable, letting request through", "group", group, "error", err)
+			ctx.Next()
+			return
+		}
+
+		header := ctx.Writer.Header()
+		header.Set("X-RateLimit-Limit", strconv.Itoa(d.Limit))
+		header.Set("X-RateLimit-Remaining", strconv.Itoa(d.Remaining))
+		header.Set("X-RateLimit-Reset", strconv.Itoa(seconds(d.Reset)))
+		if !d.Allowed {
+			header.Set("Retry-After", strconv.Itoa(seconds(d.RetryAfter)))
+			abort(ctx, status.Error(codes.ResourceExhausted, "rate limit exceeded, try again later"))
+			return
+		}
+		ctx.Next()
+	}
+}
+
+// seconds rounds d up to whole seconds, as the headers count in seconds.
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
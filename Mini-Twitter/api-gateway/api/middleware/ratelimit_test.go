@@ -0,0 +1,122 @@
+package middleware
+
+import (
+	"apigateway/pkg/config"
+	"apigateway/service"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+)
+
+func newRateLimitedRouter(t *testing.T, perUser, perIP config.RateLimit) (*gin.Engine, *miniredis.Miniredis) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	mr := miniredis.RunT(t)
+	limiter := service.NewRateLimiter(redis.NewClient(&redis.Options{Addr: mr.Addr()}))
+
+	router := gin.New()
+	router.Use(ErrorMiddleware(logger))
+	router.Use(func(c *gin.Context) {
+		if id := c.GetHeader("X-User"); id != "" {
+			c.Set("user_id", id)
+		}
+	})
+	router.POST("/tweet/add", RateLimitMiddleware(limiter, "tweet", perUser, perIP, logger), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return router, mr
+}
+
+func post(router *gin.Engine, user, ip string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/tweet/add", nil)
+	req.Header.Set("X-User", user)
+	req.RemoteAddr = ip + ":1234"
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestRateLimitPerUser(t *testing.T) {
+	router, _ := newRateLimitedRouter(t,
+		config.RateLimit{Requests: 2, Per: time.Minute},
+		config.RateLimit{Requests: 100, Per: time.Minute})
+
+	for i, remaining := range []string{"1", "0"} {
+		w := post(router, "alice", "10.0.0.1")
+		if w.Code != http.StatusOK {
+			t.Fatalf("request %d: status = %d, want 200", i, w.Code)
+		}
+		if got := w.Header().Get("X-RateLimit-Limit"); got != "2" {
+			t.Errorf("request %d: X-RateLimit-Limit = %q, want 2", i, got)
+		}
+		if got := w.Header().Get("X-RateLimit-Remaining"); got != remaining {
+			t.Errorf("request %d: X-RateLimit-Remaining = %q, want %s", i, got, remaining)
+		}
+	}
+
+	w := post(router, "alice", "10.0.0.1")
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("status = %d, want 429", w.Code)
+	}
+	// One token comes back every 30 seconds.
+	if got := w.Header().Get("Retry-After"); got != "30" {
+		t.Errorf("Retry-After = %q, want 30", got)
+	}
+	if got := w.Header().Get("X-RateLimit-Reset"); got != "60" {
+		t.Errorf("X-RateLimit-Reset = %q, want 60", got)
+	}
+
+	if w := post(router, "bob", "10.0.0.1"); w.Code != http.StatusOK {
+		t.Errorf("another user: status = %d, want 200", w.Code)
+	}
+}
+
+func TestRateLimitPerIP(t *testing.T) {
+	router, _ := newRateLimitedRouter(t,
+		config.RateLimit{Requests: 100, Per: time.Minute},
+		config.RateLimit{Requests: 2, Per: time.Minute})
+
+	post(router, "alice", "10.0.0.1")
+	post(router, "bob", "10.0.0.1")
+	if w := post(router, "carol", "10.0.0.1"); w.Code != http.StatusTooManyRequests {
+		t.Errorf("third user on one address: status = %d, want 429", w.Code)
+	}
+	if w := post(router, "carol", "10.0.0.2"); w.Code != http.StatusOK {
+		t.Errorf("another address: status = %d, want 200", w.Code)
+	}
+}
+
+func TestRateLimitDeniedRequestKeepsTokens(t *testing.T) {
+	router, _ := newRateLimitedRouter(t,
+		config.RateLimit{Requests: 1, Per: time.Minute},
+		config.RateLimit{Requests: 2, Per: time.Minute})
+
+	post(router, "alice", "10.0.0.1")
+	// Turned away by alice's bucket, so the address keeps its token.
+	post(router, "alice", "10.0.0.1")
+	if w := post(router, "bob", "10.0.0.1"); w.Code != http.StatusOK {
+		t.Errorf("status = %d, want 200", w.Code)
+	}
+}
+
+func TestRateLimitRedisDown(t *testing.T) {
+	router, mr := newRateLimitedRouter(t,
+		config.RateLimit{Requests: 1, Per: time.Minute},
+		config.RateLimit{Requests: 1, Per: time.Minute})
+	mr.Close()
+
+	for i := 0; i < 3; i++ {
+		if w := post(router, "alice", "10.0.0.1"); w.Code != http.StatusOK {
+			t.Fatalf("request %d: status = %d, want 200", i, w.Code)
+		}
+	}
+}
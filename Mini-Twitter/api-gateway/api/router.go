@@ -13,8 +13,10 @@ import (
 	_ "apigateway/api/docs"
 
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 )
 
 // @title Api-Geteway service for mini-twitter
@@ -25,23 +27,50 @@ import (
 // @name Authorization
 // @schemes http
 // @BasePath
-func NewRouter(cfg *config.Config, conn *amqp.Channel, log *slog.Logger, casbin *casbin.Enforcer) *gin.Engine {
-	router := gin.Default()
-
-	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
-
-	router.Use(middleware.PermissionMiddleware(casbin))
+func NewRouter(cfg *config.Config, conn *amqp.Channel, log *slog.Logger, casbin *casbin.Enforcer, commands *service.CommandStore, revocations *service.RevocationList, limiter *service.RateLimiter, probes map[string]service.Probe) *gin.Engine {
+	router, err := newEngine(cfg)
+	if err != nil {
+		log.Error("Invalid trusted proxies", "error", err)
+		return nil
+	}
 
 	a, err := service.NewService(cfg)
 	if err != nil {
-		log.Info("Error while creating service", err)
+		log.Info("Error while creating service", "error", err)
 		return nil
 	}
 
-	commentHandler := handler.NewCommentHandler(a, log, conn)
-	tweetHandler := handler.NewTweetHandler(a, log, conn)
+	checks := make(map[string]service.Probe, len(probes))
+	for name, probe := range probes {
+		checks[name] = probe
+	}
+	for name, probe := range a.Probes() {
+		checks[name] = probe
+	}
+	healthHandler := handler.NewHealthHandler(checks, log)
+
+	// These routes are registered before the middleware below, so they
+	// need no token.
+	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
+	router.GET("/healthz", healthHandler.Healthz)
+	router.GET("/readyz", healthHandler.Readyz)
+
+	router.Use(otelgin.Middleware("api-gateway"))
+	router.Use(middleware.RequestIDMiddleware())
+	router.Use(middleware.MetricsMiddleware())
+	router.Use(middleware.ErrorMiddleware(log))
+	router.Use(middleware.PermissionMiddleware(casbin, revocations))
+	router.Use(middleware.IdempotencyMiddleware())
+
+	commentHandler := handler.NewCommentHandler(a, log, conn, commands)
+	tweetHandler := handler.NewTweetHandler(a, log, conn, commands)
 	userHandler := handler.NewUserHandler(a, log)
-	likeHandler := handler.NewLikeHandler(a, log, conn)
+	likeHandler := handler.NewLikeHandler(a, log, conn, commands)
+	commandHandler := handler.NewCommandHandler(commands, log)
+	deadLetterHandler := handler.NewDeadLetterHandler(a, log)
+	accountHandler := handler.NewAccountHandler(a, log)
+	adminHandler := handler.NewAdminHandler(a, log)
 
 	userGroup := router.Group("/user")
 	{
@@ -49,16 +78,27 @@ func NewRouter(cfg *config.Config, conn *amqp.Channel, log *slog.Logger, casbin
 		userGroup.PUT("/update_profile", userHandler.UpdateProfile)
 		userGroup.PUT("/change_password", userHandler.ChangePassword)
 		userGroup.PUT("/change_profile_image", userHandler.ChangeProfileImage)
+		userGroup.PUT("/change_username", userHandler.ChangeUsername)
+		userGroup.GET("/check_username/:username", userHandler.CheckUsername)
 		userGroup.GET("/fetch_users", userHandler.FetchUsers)
 		userGroup.GET("/list_of_following", userHandler.ListOfFollowing)
 		userGroup.GET("/list_of_followers", userHandler.ListOfFollowers)
 		userGroup.GET("/list_of_following_by_username/:username", userHandler.ListOfFollowingByUsername)
 		userGroup.GET("/list_of_followers_by_username/:username", userHandler.ListOfFollowersByUsername)
-		userGroup.POST("/follow", userHandler.Follow)
+		userGroup.POST("/follow", middleware.RateLimitMiddleware(limiter, "follow", cfg.RATE_LIMIT_FOLLOW_USER, cfg.RATE_LIMIT_FOLLOW_IP, log), userHandler.Follow)
 		userGroup.DELETE("/unfollow", userHandler.Unfollow)
 		userGroup.GET("/get_user_followers", userHandler.GetUserFollowers)
 		userGroup.GET("/get_user_follows", userHandler.GetUserFollows)
 		userGroup.GET("/most_popular", userHandler.MostPopularUser)
+		userGroup.GET("/suggestions", userHandler.SuggestUsers)
+		userGroup.GET("/search", userHandler.SearchUsers)
+		userGroup.POST("/block/:user_id", userHandler.Block)
+		userGroup.DELETE("/block/:user_id", userHandler.Unblock)
+		userGroup.GET("/relationships", userHandler.GetRelationships)
+		userGroup.GET("/mutual_followers", userHandler.GetMutualFollowers)
+		userGroup.GET("/export", accountHandler.ExportData)
+		userGroup.POST("/deletion", accountHandler.RequestDeletion)
+		userGroup.DELETE("/deletion", accountHandler.CancelDeletion)
 	}
 	adminGroup := router.Group("/admin")
 	{
@@ -66,20 +106,28 @@ func NewRouter(cfg *config.Config, conn *amqp.Channel, log *slog.Logger, casbin
 		adminGroup.POST("/create", userHandler.Create)
 		adminGroup.GET("/get_profile_by_id/:user_id", userHandler.GetProfile)
 		adminGroup.PUT("/update_profile_by_id/:user_id", userHandler.UpdateProfile)
+		adminGroup.GET("/dead_letters/:type", deadLetterHandler.ListDeadLetters)
+		adminGroup.POST("/dead_letters/:type/replay", deadLetterHandler.ReplayDeadLetter)
+		adminGroup.PUT("/users/:user_id/role", adminHandler.SetRole)
+		adminGroup.PUT("/users/:user_id/verified", adminHandler.SetVerified)
+		adminGroup.GET("/audit", adminHandler.ListAuditLog)
 
 	}
 
+	// Only the routes that write are rate limited, so reading does not use up
+	// the budget for writing.
+	tweetLimit := middleware.RateLimitMiddleware(limiter, "tweet", cfg.RATE_LIMIT_TWEET_USER, cfg.RATE_LIMIT_TWEET_IP, log)
 	tweetGroup := router.Group("/tweet")
 	{
-		tweetGroup.POST("/add", tweetHandler.PostTweet)
-		tweetGroup.PUT("/add_up", tweetHandler.UpdateTweet)
-		tweetGroup.PUT("/add_image", tweetHandler.AddImageToTweet)
+		tweetGroup.POST("/add", tweetLimit, tweetHandler.PostTweet)
+		tweetGroup.PUT("/add_up", tweetLimit, tweetHandler.UpdateTweet)
+		tweetGroup.PUT("/add_image", tweetLimit, tweetHandler.AddImageToTweet)
 		tweetGroup.GET("/get_tt/:id", tweetHandler.GetTweet)
 		tweetGroup.GET("/user", tweetHandler.UserTweets)
 		tweetGroup.GET("/get_all", tweetHandler.GetAllTweets)
 		tweetGroup.GET("/recommend", tweetHandler.RecommendTweets)
 		tweetGroup.GET("/get_new", tweetHandler.GetNewTweets)
-		tweetGroup.POST("/re_tweet", tweetHandler.ReTweet)
+		tweetGroup.POST("/re_tweet", tweetLimit, tweetHandler.ReTweet)
 	}
 
 	commentGroup := router.Group("/comment")
@@ -94,14 +142,28 @@ func NewRouter(cfg *config.Config, conn *amqp.Channel, log *slog.Logger, casbin
 		commentGroup.DELETE("/remove_like/:comment_id", commentHandler.DeleteLikeComment)
 	}
 
+	likeLimit := middleware.RateLimitMiddleware(limiter, "like", cfg.RATE_LIMIT_LIKE_USER, cfg.RATE_LIMIT_LIKE_IP, log)
 	likeGroup := router.Group("/like")
 	{
-		likeGroup.POST("/add", likeHandler.AddLike)
-		likeGroup.DELETE("/delete/:tweet_id", likeHandler.DeleteLIke)
+		likeGroup.POST("/add", likeLimit, likeHandler.AddLike)
+		likeGroup.DELETE("/delete/:tweet_id", likeLimit, likeHandler.DeleteLIke)
 		likeGroup.GET("/get_user", likeHandler.GetUserLikes)
 		likeGroup.GET("/get_count/:tweet_id", likeHandler.GetCountTweetLikes)
 		likeGroup.GET("/get_most", likeHandler.MostLikedTweets)
 	}
 
+	router.GET("/commands/:id", commandHandler.GetCommand)
+
 	return router
 }
+
+// newEngine returns the gin engine of the gateway. The client address, which
+// rate limits are kept by, is only taken from X-Forwarded-For when the
+// request comes through one of cfg.TRUSTED_PROXIES.
+func newEngine(cfg *config.Config) (*gin.Engine, error) {
+	router := gin.Default()
+	if err := router.SetTrustedProxies(cfg.TRUSTED_PROXIES); err != nil {
+		return nil, err
+	}
+	return router, nil
+}
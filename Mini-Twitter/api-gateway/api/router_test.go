@@ -0,0 +1,48 @@
+package api
+
+import (
+	"apigateway/pkg/config"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestNewEngineClientIP(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name    string
+		proxies []string
+		remote  string
+		want    string
+	}{
+		{"no trusted proxies", nil, "203.0.113.9", "203.0.113.9"},
+		{"untrusted proxy", []string{"10.0.0.0/8"}, "203.0.113.9", "203.0.113.9"},
+		{"trusted proxy", []string{"10.0.0.0/8"}, "10.0.0.2", "198.51.100.7"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, err := newEngine(&config.Config{TRUSTED_PROXIES: tt.proxies})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got string
+			router.GET("/ip", func(c *gin.Context) { got = c.ClientIP() })
+
+			req := httptest.NewRequest(http.MethodGet, "/ip", nil)
+			req.RemoteAddr = tt.remote + ":1234"
+			req.Header.Set("X-Forwarded-For", "198.51.100.7")
+			router.ServeHTTP(httptest.NewRecorder(), req)
+
+			if got != tt.want {
+				t.Errorf("client IP = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := newEngine(&config.Config{TRUSTED_PROXIES: []string{"not an address"}}); err == nil {
+		t.Error("newEngine accepted an invalid proxy")
+	}
+}
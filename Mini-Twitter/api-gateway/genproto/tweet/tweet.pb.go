@@ -1899,6 +1899,442 @@ func (x *CommentLikeReq) GetCommentId() string {
 	return ""
 }
 
+// Dead letter Messages
+type DeadLetterFilter struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Type  string `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Limit int64  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+}
+
+func (x *DeadLetterFilter) Reset() {
+	*x = DeadLetterFilter{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tweet_tweet_proto_msgTypes[28]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeadLetterFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeadLetterFilter) ProtoMessage() {}
+
+func (x *DeadLetterFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_tweet_tweet_proto_msgTypes[28]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeadLetterFilter.ProtoReflect.Descriptor instead.
+func (*DeadLetterFilter) Descriptor() ([]byte, []int) {
+	return file_tweet_tweet_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *DeadLetterFilter) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *DeadLetterFilter) GetLimit() int64 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type DeadLetter struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id         string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Type       string `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Version    int32  `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	Attempts   int32  `protobuf:"varint,4,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	Error      string `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	OccurredAt string `protobuf:"bytes,6,opt,name=occurredAt,proto3" json:"occurredAt,omitempty"`
+	DeadAt     string `protobuf:"bytes,7,opt,name=deadAt,proto3" json:"deadAt,omitempty"`
+	Payload    string `protobuf:"bytes,8,opt,name=payload,proto3" json:"payload,omitempty"`
+}
+
+func (x *DeadLetter) Reset() {
+	*x = DeadLetter{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tweet_tweet_proto_msgTypes[29]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeadLetter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeadLetter) ProtoMessage() {}
+
+func (x *DeadLetter) ProtoReflect() protoreflect.Message {
+	mi := &file_tweet_tweet_proto_msgTypes[29]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeadLetter.ProtoReflect.Descriptor instead.
+func (*DeadLetter) Descriptor() ([]byte, []int) {
+	return file_tweet_tweet_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *DeadLetter) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DeadLetter) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *DeadLetter) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *DeadLetter) GetAttempts() int32 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
+}
+
+func (x *DeadLetter) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *DeadLetter) GetOccurredAt() string {
+	if x != nil {
+		return x.OccurredAt
+	}
+	return ""
+}
+
+func (x *DeadLetter) GetDeadAt() string {
+	if x != nil {
+		return x.DeadAt
+	}
+	return ""
+}
+
+func (x *DeadLetter) GetPayload() string {
+	if x != nil {
+		return x.Payload
+	}
+	return ""
+}
+
+type DeadLetters struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DeadLetters []*DeadLetter `protobuf:"bytes,1,rep,name=deadLetters,proto3" json:"deadLetters,omitempty"`
+}
+
+func (x *DeadLetters) Reset() {
+	*x = DeadLetters{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tweet_tweet_proto_msgTypes[30]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeadLetters) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeadLetters) ProtoMessage() {}
+
+func (x *DeadLetters) ProtoReflect() protoreflect.Message {
+	mi := &file_tweet_tweet_proto_msgTypes[30]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeadLetters.ProtoReflect.Descriptor instead.
+func (*DeadLetters) Descriptor() ([]byte, []int) {
+	return file_tweet_tweet_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *DeadLetters) GetDeadLetters() []*DeadLetter {
+	if x != nil {
+		return x.DeadLetters
+	}
+	return nil
+}
+
+type ReplayReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Type string `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Id   string `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *ReplayReq) Reset() {
+	*x = ReplayReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tweet_tweet_proto_msgTypes[31]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReplayReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplayReq) ProtoMessage() {}
+
+func (x *ReplayReq) ProtoReflect() protoreflect.Message {
+	mi := &file_tweet_tweet_proto_msgTypes[31]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplayReq.ProtoReflect.Descriptor instead.
+func (*ReplayReq) Descriptor() ([]byte, []int) {
+	return file_tweet_tweet_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *ReplayReq) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *ReplayReq) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ReplayRes struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Count int64 `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+}
+
+func (x *ReplayRes) Reset() {
+	*x = ReplayRes{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tweet_tweet_proto_msgTypes[32]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReplayRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplayRes) ProtoMessage() {}
+
+func (x *ReplayRes) ProtoReflect() protoreflect.Message {
+	mi := &file_tweet_tweet_proto_msgTypes[32]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplayRes.ProtoReflect.Descriptor instead.
+func (*ReplayRes) Descriptor() ([]byte, []int) {
+	return file_tweet_tweet_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *ReplayRes) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+// Account data Messages
+type LikedTweet struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TweetId string `protobuf:"bytes,1,opt,name=tweetId,proto3" json:"tweetId,omitempty"`
+	Title   string `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	LikedAt string `protobuf:"bytes,3,opt,name=likedAt,proto3" json:"likedAt,omitempty"`
+}
+
+func (x *LikedTweet) Reset() {
+	*x = LikedTweet{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tweet_tweet_proto_msgTypes[33]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LikedTweet) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LikedTweet) ProtoMessage() {}
+
+func (x *LikedTweet) ProtoReflect() protoreflect.Message {
+	mi := &file_tweet_tweet_proto_msgTypes[33]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LikedTweet.ProtoReflect.Descriptor instead.
+func (*LikedTweet) Descriptor() ([]byte, []int) {
+	return file_tweet_tweet_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *LikedTweet) GetTweetId() string {
+	if x != nil {
+		return x.TweetId
+	}
+	return ""
+}
+
+func (x *LikedTweet) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *LikedTweet) GetLikedAt() string {
+	if x != nil {
+		return x.LikedAt
+	}
+	return ""
+}
+
+// UserContent is everything twitt-service keeps about a user.
+type UserContent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Tweets   []*TweetResponse `protobuf:"bytes,1,rep,name=tweets,proto3" json:"tweets,omitempty"`
+	Comments []*CommentRes    `protobuf:"bytes,2,rep,name=comments,proto3" json:"comments,omitempty"`
+	Likes    []*LikedTweet    `protobuf:"bytes,3,rep,name=likes,proto3" json:"likes,omitempty"`
+}
+
+func (x *UserContent) Reset() {
+	*x = UserContent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tweet_tweet_proto_msgTypes[34]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UserContent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserContent) ProtoMessage() {}
+
+func (x *UserContent) ProtoReflect() protoreflect.Message {
+	mi := &file_tweet_tweet_proto_msgTypes[34]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserContent.ProtoReflect.Descriptor instead.
+func (*UserContent) Descriptor() ([]byte, []int) {
+	return file_tweet_tweet_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *UserContent) GetTweets() []*TweetResponse {
+	if x != nil {
+		return x.Tweets
+	}
+	return nil
+}
+
+func (x *UserContent) GetComments() []*CommentRes {
+	if x != nil {
+		return x.Comments
+	}
+	return nil
+}
+
+func (x *UserContent) GetLikes() []*LikedTweet {
+	if x != nil {
+		return x.Likes
+	}
+	return nil
+}
+
 var File_tweet_tweet_proto protoreflect.FileDescriptor
 
 var file_tweet_tweet_proto_rawDesc = []byte{
@@ -2087,95 +2523,149 @@ var file_tweet_tweet_proto_rawDesc = []byte{
 	0x73, 0x52, 0x08, 0x63, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x22, 0x2e, 0x0a, 0x0e, 0x43,
 	0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x4c, 0x69, 0x6b, 0x65, 0x52, 0x65, 0x71, 0x12, 0x1c, 0x0a,
 	0x09, 0x63, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x09, 0x63, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x32, 0xe2, 0x0a, 0x0a, 0x0c,
-	0x54, 0x77, 0x65, 0x65, 0x74, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x2f, 0x0a, 0x09,
-	0x50, 0x6f, 0x73, 0x74, 0x54, 0x77, 0x65, 0x65, 0x74, 0x12, 0x0c, 0x2e, 0x74, 0x77, 0x65, 0x65,
-	0x74, 0x2e, 0x54, 0x77, 0x65, 0x65, 0x74, 0x1a, 0x14, 0x2e, 0x74, 0x77, 0x65, 0x65, 0x74, 0x2e,
-	0x54, 0x77, 0x65, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x38, 0x0a,
-	0x0b, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x54, 0x77, 0x65, 0x65, 0x74, 0x12, 0x13, 0x2e, 0x74,
-	0x77, 0x65, 0x65, 0x74, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x41, 0x54, 0x77, 0x65, 0x65,
-	0x74, 0x1a, 0x14, 0x2e, 0x74, 0x77, 0x65, 0x65, 0x74, 0x2e, 0x54, 0x77, 0x65, 0x65, 0x74, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2d, 0x0a, 0x0f, 0x41, 0x64, 0x64, 0x49, 0x6d,
-	0x61, 0x67, 0x65, 0x54, 0x6f, 0x54, 0x77, 0x65, 0x65, 0x74, 0x12, 0x0a, 0x2e, 0x74, 0x77, 0x65,
-	0x65, 0x74, 0x2e, 0x55, 0x72, 0x6c, 0x1a, 0x0e, 0x2e, 0x74, 0x77, 0x65, 0x65, 0x74, 0x2e, 0x4d,
-	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x2a, 0x0a, 0x0a, 0x55, 0x73, 0x65, 0x72, 0x54, 0x77,
-	0x65, 0x65, 0x74, 0x73, 0x12, 0x0d, 0x2e, 0x74, 0x77, 0x65, 0x65, 0x74, 0x2e, 0x55, 0x73, 0x65,
-	0x72, 0x49, 0x64, 0x1a, 0x0d, 0x2e, 0x74, 0x77, 0x65, 0x65, 0x74, 0x2e, 0x54, 0x77, 0x65, 0x65,
-	0x74, 0x73, 0x12, 0x30, 0x0a, 0x08, 0x47, 0x65, 0x74, 0x54, 0x77, 0x65, 0x65, 0x74, 0x12, 0x0e,
-	0x2e, 0x74, 0x77, 0x65, 0x65, 0x74, 0x2e, 0x54, 0x77, 0x65, 0x65, 0x74, 0x49, 0x64, 0x1a, 0x14,
-	0x2e, 0x74, 0x77, 0x65, 0x65, 0x74, 0x2e, 0x54, 0x77, 0x65, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x31, 0x0a, 0x0c, 0x47, 0x65, 0x74, 0x41, 0x6c, 0x6c, 0x54, 0x77,
-	0x65, 0x65, 0x74, 0x73, 0x12, 0x12, 0x2e, 0x74, 0x77, 0x65, 0x65, 0x74, 0x2e, 0x54, 0x77, 0x65,
-	0x65, 0x74, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x1a, 0x0d, 0x2e, 0x74, 0x77, 0x65, 0x65, 0x74,
-	0x2e, 0x54, 0x77, 0x65, 0x65, 0x74, 0x73, 0x12, 0x2f, 0x0a, 0x0f, 0x52, 0x65, 0x63, 0x6f, 0x6d,
-	0x6d, 0x65, 0x6e, 0x64, 0x54, 0x77, 0x65, 0x65, 0x74, 0x73, 0x12, 0x0d, 0x2e, 0x74, 0x77, 0x65,
-	0x65, 0x74, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x49, 0x64, 0x1a, 0x0d, 0x2e, 0x74, 0x77, 0x65, 0x65,
-	0x74, 0x2e, 0x54, 0x77, 0x65, 0x65, 0x74, 0x73, 0x12, 0x2c, 0x0a, 0x0c, 0x47, 0x65, 0x74, 0x4e,
-	0x65, 0x77, 0x54, 0x77, 0x65, 0x65, 0x74, 0x73, 0x12, 0x0d, 0x2e, 0x74, 0x77, 0x65, 0x65, 0x74,
-	0x2e, 0x55, 0x73, 0x65, 0x72, 0x49, 0x64, 0x1a, 0x0d, 0x2e, 0x74, 0x77, 0x65, 0x65, 0x74, 0x2e,
-	0x54, 0x77, 0x65, 0x65, 0x74, 0x73, 0x12, 0x32, 0x0a, 0x07, 0x52, 0x65, 0x54, 0x77, 0x65, 0x65,
-	0x74, 0x12, 0x11, 0x2e, 0x74, 0x77, 0x65, 0x65, 0x74, 0x2e, 0x52, 0x65, 0x54, 0x77, 0x65, 0x65,
-	0x74, 0x52, 0x65, 0x71, 0x1a, 0x14, 0x2e, 0x74, 0x77, 0x65, 0x65, 0x74, 0x2e, 0x54, 0x77, 0x65,
-	0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2c, 0x0a, 0x06, 0x46, 0x6f,
-	0x6c, 0x6c, 0x6f, 0x77, 0x12, 0x10, 0x2e, 0x74, 0x77, 0x65, 0x65, 0x74, 0x2e, 0x46, 0x6f, 0x6c,
-	0x6c, 0x6f, 0x77, 0x52, 0x65, 0x71, 0x1a, 0x10, 0x2e, 0x74, 0x77, 0x65, 0x65, 0x74, 0x2e, 0x46,
-	0x6f, 0x6c, 0x6c, 0x6f, 0x77, 0x52, 0x65, 0x73, 0x12, 0x2f, 0x0a, 0x08, 0x55, 0x6e, 0x66, 0x6f,
-	0x6c, 0x6c, 0x6f, 0x77, 0x12, 0x10, 0x2e, 0x74, 0x77, 0x65, 0x65, 0x74, 0x2e, 0x46, 0x6f, 0x6c,
-	0x6c, 0x6f, 0x77, 0x52, 0x65, 0x71, 0x1a, 0x11, 0x2e, 0x74, 0x77, 0x65, 0x65, 0x74, 0x2e, 0x44,
-	0x46, 0x6f, 0x6c, 0x6c, 0x6f, 0x77, 0x52, 0x65, 0x73, 0x12, 0x2f, 0x0a, 0x10, 0x47, 0x65, 0x74,
-	0x55, 0x73, 0x65, 0x72, 0x46, 0x6f, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x72, 0x73, 0x12, 0x0d, 0x2e,
-	0x74, 0x77, 0x65, 0x65, 0x74, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x49, 0x64, 0x1a, 0x0c, 0x2e, 0x74,
-	0x77, 0x65, 0x65, 0x74, 0x2e, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x2d, 0x0a, 0x0e, 0x47, 0x65,
-	0x74, 0x55, 0x73, 0x65, 0x72, 0x46, 0x6f, 0x6c, 0x6c, 0x6f, 0x77, 0x73, 0x12, 0x0d, 0x2e, 0x74,
-	0x77, 0x65, 0x65, 0x74, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x49, 0x64, 0x1a, 0x0c, 0x2e, 0x74, 0x77,
-	0x65, 0x65, 0x74, 0x2e, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x2b, 0x0a, 0x0f, 0x4d, 0x6f, 0x73,
-	0x74, 0x50, 0x6f, 0x70, 0x75, 0x6c, 0x61, 0x72, 0x55, 0x73, 0x65, 0x72, 0x12, 0x0b, 0x2e, 0x74,
-	0x77, 0x65, 0x65, 0x74, 0x2e, 0x56, 0x6f, 0x69, 0x64, 0x1a, 0x0b, 0x2e, 0x74, 0x77, 0x65, 0x65,
-	0x74, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x12, 0x29, 0x0a, 0x07, 0x41, 0x64, 0x64, 0x4c, 0x69, 0x6b,
-	0x65, 0x12, 0x0e, 0x2e, 0x74, 0x77, 0x65, 0x65, 0x74, 0x2e, 0x4c, 0x69, 0x6b, 0x65, 0x52, 0x65,
-	0x71, 0x1a, 0x0e, 0x2e, 0x74, 0x77, 0x65, 0x65, 0x74, 0x2e, 0x4c, 0x69, 0x6b, 0x65, 0x52, 0x65,
-	0x73, 0x12, 0x2d, 0x0a, 0x0a, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x4c, 0x69, 0x6b, 0x65, 0x12,
-	0x0e, 0x2e, 0x74, 0x77, 0x65, 0x65, 0x74, 0x2e, 0x4c, 0x69, 0x6b, 0x65, 0x52, 0x65, 0x71, 0x1a,
-	0x0f, 0x2e, 0x74, 0x77, 0x65, 0x65, 0x74, 0x2e, 0x44, 0x4c, 0x69, 0x6b, 0x65, 0x52, 0x65, 0x73,
-	0x12, 0x31, 0x0a, 0x0c, 0x47, 0x65, 0x74, 0x55, 0x73, 0x65, 0x72, 0x4c, 0x69, 0x6b, 0x65, 0x73,
-	0x12, 0x0d, 0x2e, 0x74, 0x77, 0x65, 0x65, 0x74, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x49, 0x64, 0x1a,
-	0x12, 0x2e, 0x74, 0x77, 0x65, 0x65, 0x74, 0x2e, 0x54, 0x77, 0x65, 0x65, 0x74, 0x54, 0x69, 0x74,
-	0x6c, 0x65, 0x73, 0x12, 0x32, 0x0a, 0x12, 0x47, 0x65, 0x74, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x54,
-	0x77, 0x65, 0x65, 0x74, 0x4c, 0x69, 0x6b, 0x65, 0x73, 0x12, 0x0e, 0x2e, 0x74, 0x77, 0x65, 0x65,
-	0x74, 0x2e, 0x54, 0x77, 0x65, 0x65, 0x74, 0x49, 0x64, 0x1a, 0x0c, 0x2e, 0x74, 0x77, 0x65, 0x65,
-	0x74, 0x2e, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x34, 0x0a, 0x0f, 0x4d, 0x6f, 0x73, 0x74, 0x4c,
-	0x69, 0x6b, 0x65, 0x64, 0x54, 0x77, 0x65, 0x65, 0x74, 0x73, 0x12, 0x0b, 0x2e, 0x74, 0x77, 0x65,
-	0x65, 0x74, 0x2e, 0x56, 0x6f, 0x69, 0x64, 0x1a, 0x14, 0x2e, 0x74, 0x77, 0x65, 0x65, 0x74, 0x2e,
-	0x54, 0x77, 0x65, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x30, 0x0a,
-	0x0b, 0x50, 0x6f, 0x73, 0x74, 0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x0e, 0x2e, 0x74,
-	0x77, 0x65, 0x65, 0x74, 0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x1a, 0x11, 0x2e, 0x74,
+	0x52, 0x09, 0x63, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x22, 0x3c, 0x0a, 0x10, 0x44,
+	0x65, 0x61, 0x64, 0x4c, 0x65, 0x74, 0x74, 0x65, 0x72, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x12,
+	0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74,
+	0x79, 0x70, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x22, 0xce, 0x01, 0x0a, 0x0a, 0x44, 0x65,
+	0x61, 0x64, 0x4c, 0x65, 0x74, 0x74, 0x65, 0x72, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x18, 0x0a, 0x07,
+	0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x76,
+	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x61, 0x74, 0x74, 0x65, 0x6d, 0x70,
+	0x74, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x61, 0x74, 0x74, 0x65, 0x6d, 0x70,
+	0x74, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x1e, 0x0a, 0x0a, 0x6f, 0x63, 0x63, 0x75,
+	0x72, 0x72, 0x65, 0x64, 0x41, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6f, 0x63,
+	0x63, 0x75, 0x72, 0x72, 0x65, 0x64, 0x41, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x65, 0x61, 0x64,
+	0x41, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x64, 0x65, 0x61, 0x64, 0x41, 0x74,
+	0x12, 0x18, 0x0a, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x18, 0x08, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x22, 0x42, 0x0a, 0x0b, 0x44, 0x65,
+	0x61, 0x64, 0x4c, 0x65, 0x74, 0x74, 0x65, 0x72, 0x73, 0x12, 0x33, 0x0a, 0x0b, 0x64, 0x65, 0x61,
+	0x64, 0x4c, 0x65, 0x74, 0x74, 0x65, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11,
+	0x2e, 0x74, 0x77, 0x65, 0x65, 0x74, 0x2e, 0x44, 0x65, 0x61, 0x64, 0x4c, 0x65, 0x74, 0x74, 0x65,
+	0x72, 0x52, 0x0b, 0x64, 0x65, 0x61, 0x64, 0x4c, 0x65, 0x74, 0x74, 0x65, 0x72, 0x73, 0x22, 0x2f,
+	0x0a, 0x09, 0x52, 0x65, 0x70, 0x6c, 0x61, 0x79, 0x52, 0x65, 0x71, 0x12, 0x12, 0x0a, 0x04, 0x74,
+	0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12,
+	0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22,
+	0x21, 0x0a, 0x09, 0x52, 0x65, 0x70, 0x6c, 0x61, 0x79, 0x52, 0x65, 0x73, 0x12, 0x14, 0x0a, 0x05,
+	0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x63, 0x6f, 0x75,
+	0x6e, 0x74, 0x22, 0x56, 0x0a, 0x0a, 0x4c, 0x69, 0x6b, 0x65, 0x64, 0x54, 0x77, 0x65, 0x65, 0x74,
+	0x12, 0x18, 0x0a, 0x07, 0x74, 0x77, 0x65, 0x65, 0x74, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x07, 0x74, 0x77, 0x65, 0x65, 0x74, 0x49, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x69,
+	0x74, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65,
+	0x12, 0x18, 0x0a, 0x07, 0x6c, 0x69, 0x6b, 0x65, 0x64, 0x41, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x07, 0x6c, 0x69, 0x6b, 0x65, 0x64, 0x41, 0x74, 0x22, 0x93, 0x01, 0x0a, 0x0b, 0x55,
+	0x73, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x12, 0x2c, 0x0a, 0x06, 0x74, 0x77,
+	0x65, 0x65, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x74, 0x77, 0x65,
+	0x65, 0x74, 0x2e, 0x54, 0x77, 0x65, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x52, 0x06, 0x74, 0x77, 0x65, 0x65, 0x74, 0x73, 0x12, 0x2d, 0x0a, 0x08, 0x63, 0x6f, 0x6d, 0x6d,
+	0x65, 0x6e, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x74, 0x77, 0x65,
+	0x65, 0x74, 0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x52, 0x08, 0x63,
+	0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x27, 0x0a, 0x05, 0x6c, 0x69, 0x6b, 0x65, 0x73,
+	0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x74, 0x77, 0x65, 0x65, 0x74, 0x2e, 0x4c,
+	0x69, 0x6b, 0x65, 0x64, 0x54, 0x77, 0x65, 0x65, 0x74, 0x52, 0x05, 0x6c, 0x69, 0x6b, 0x65, 0x73,
+	0x32, 0x97, 0x0b, 0x0a, 0x0c, 0x54, 0x77, 0x65, 0x65, 0x74, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x12, 0x2f, 0x0a, 0x09, 0x50, 0x6f, 0x73, 0x74, 0x54, 0x77, 0x65, 0x65, 0x74, 0x12, 0x0c,
+	0x2e, 0x74, 0x77, 0x65, 0x65, 0x74, 0x2e, 0x54, 0x77, 0x65, 0x65, 0x74, 0x1a, 0x14, 0x2e, 0x74,
+	0x77, 0x65, 0x65, 0x74, 0x2e, 0x54, 0x77, 0x65, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x38, 0x0a, 0x0b, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x54, 0x77, 0x65, 0x65,
+	0x74, 0x12, 0x13, 0x2e, 0x74, 0x77, 0x65, 0x65, 0x74, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65,
+	0x41, 0x54, 0x77, 0x65, 0x65, 0x74, 0x1a, 0x14, 0x2e, 0x74, 0x77, 0x65, 0x65, 0x74, 0x2e, 0x54,
+	0x77, 0x65, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2d, 0x0a, 0x0f,
+	0x41, 0x64, 0x64, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x54, 0x6f, 0x54, 0x77, 0x65, 0x65, 0x74, 0x12,
+	0x0a, 0x2e, 0x74, 0x77, 0x65, 0x65, 0x74, 0x2e, 0x55, 0x72, 0x6c, 0x1a, 0x0e, 0x2e, 0x74, 0x77,
+	0x65, 0x65, 0x74, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x2a, 0x0a, 0x0a, 0x55,
+	0x73, 0x65, 0x72, 0x54, 0x77, 0x65, 0x65, 0x74, 0x73, 0x12, 0x0d, 0x2e, 0x74, 0x77, 0x65, 0x65,
+	0x74, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x49, 0x64, 0x1a, 0x0d, 0x2e, 0x74, 0x77, 0x65, 0x65, 0x74,
+	0x2e, 0x54, 0x77, 0x65, 0x65, 0x74, 0x73, 0x12, 0x30, 0x0a, 0x08, 0x47, 0x65, 0x74, 0x54, 0x77,
+	0x65, 0x65, 0x74, 0x12, 0x0e, 0x2e, 0x74, 0x77, 0x65, 0x65, 0x74, 0x2e, 0x54, 0x77, 0x65, 0x65,
+	0x74, 0x49, 0x64, 0x1a, 0x14, 0x2e, 0x74, 0x77, 0x65, 0x65, 0x74, 0x2e, 0x54, 0x77, 0x65, 0x65,
+	0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x31, 0x0a, 0x0c, 0x47, 0x65, 0x74,
+	0x41, 0x6c, 0x6c, 0x54, 0x77, 0x65, 0x65, 0x74, 0x73, 0x12, 0x12, 0x2e, 0x74, 0x77, 0x65, 0x65,
+	0x74, 0x2e, 0x54, 0x77, 0x65, 0x65, 0x74, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x1a, 0x0d, 0x2e,
+	0x74, 0x77, 0x65, 0x65, 0x74, 0x2e, 0x54, 0x77, 0x65, 0x65, 0x74, 0x73, 0x12, 0x2f, 0x0a, 0x0f,
+	0x52, 0x65, 0x63, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x64, 0x54, 0x77, 0x65, 0x65, 0x74, 0x73, 0x12,
+	0x0d, 0x2e, 0x74, 0x77, 0x65, 0x65, 0x74, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x49, 0x64, 0x1a, 0x0d,
+	0x2e, 0x74, 0x77, 0x65, 0x65, 0x74, 0x2e, 0x54, 0x77, 0x65, 0x65, 0x74, 0x73, 0x12, 0x2c, 0x0a,
+	0x0c, 0x47, 0x65, 0x74, 0x4e, 0x65, 0x77, 0x54, 0x77, 0x65, 0x65, 0x74, 0x73, 0x12, 0x0d, 0x2e,
+	0x74, 0x77, 0x65, 0x65, 0x74, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x49, 0x64, 0x1a, 0x0d, 0x2e, 0x74,
+	0x77, 0x65, 0x65, 0x74, 0x2e, 0x54, 0x77, 0x65, 0x65, 0x74, 0x73, 0x12, 0x32, 0x0a, 0x07, 0x52,
+	0x65, 0x54, 0x77, 0x65, 0x65, 0x74, 0x12, 0x11, 0x2e, 0x74, 0x77, 0x65, 0x65, 0x74, 0x2e, 0x52,
+	0x65, 0x54, 0x77, 0x65, 0x65, 0x74, 0x52, 0x65, 0x71, 0x1a, 0x14, 0x2e, 0x74, 0x77, 0x65, 0x65,
+	0x74, 0x2e, 0x54, 0x77, 0x65, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x2c, 0x0a, 0x06, 0x46, 0x6f, 0x6c, 0x6c, 0x6f, 0x77, 0x12, 0x10, 0x2e, 0x74, 0x77, 0x65, 0x65,
+	0x74, 0x2e, 0x46, 0x6f, 0x6c, 0x6c, 0x6f, 0x77, 0x52, 0x65, 0x71, 0x1a, 0x10, 0x2e, 0x74, 0x77,
+	0x65, 0x65, 0x74, 0x2e, 0x46, 0x6f, 0x6c, 0x6c, 0x6f, 0x77, 0x52, 0x65, 0x73, 0x12, 0x2f, 0x0a,
+	0x08, 0x55, 0x6e, 0x66, 0x6f, 0x6c, 0x6c, 0x6f, 0x77, 0x12, 0x10, 0x2e, 0x74, 0x77, 0x65, 0x65,
+	0x74, 0x2e, 0x46, 0x6f, 0x6c, 0x6c, 0x6f, 0x77, 0x52, 0x65, 0x71, 0x1a, 0x11, 0x2e, 0x74, 0x77,
+	0x65, 0x65, 0x74, 0x2e, 0x44, 0x46, 0x6f, 0x6c, 0x6c, 0x6f, 0x77, 0x52, 0x65, 0x73, 0x12, 0x2f,
+	0x0a, 0x10, 0x47, 0x65, 0x74, 0x55, 0x73, 0x65, 0x72, 0x46, 0x6f, 0x6c, 0x6c, 0x6f, 0x77, 0x65,
+	0x72, 0x73, 0x12, 0x0d, 0x2e, 0x74, 0x77, 0x65, 0x65, 0x74, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x49,
+	0x64, 0x1a, 0x0c, 0x2e, 0x74, 0x77, 0x65, 0x65, 0x74, 0x2e, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12,
+	0x2d, 0x0a, 0x0e, 0x47, 0x65, 0x74, 0x55, 0x73, 0x65, 0x72, 0x46, 0x6f, 0x6c, 0x6c, 0x6f, 0x77,
+	0x73, 0x12, 0x0d, 0x2e, 0x74, 0x77, 0x65, 0x65, 0x74, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x49, 0x64,
+	0x1a, 0x0c, 0x2e, 0x74, 0x77, 0x65, 0x65, 0x74, 0x2e, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x2b,
+	0x0a, 0x0f, 0x4d, 0x6f, 0x73, 0x74, 0x50, 0x6f, 0x70, 0x75, 0x6c, 0x61, 0x72, 0x55, 0x73, 0x65,
+	0x72, 0x12, 0x0b, 0x2e, 0x74, 0x77, 0x65, 0x65, 0x74, 0x2e, 0x56, 0x6f, 0x69, 0x64, 0x1a, 0x0b,
+	0x2e, 0x74, 0x77, 0x65, 0x65, 0x74, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x12, 0x29, 0x0a, 0x07, 0x41,
+	0x64, 0x64, 0x4c, 0x69, 0x6b, 0x65, 0x12, 0x0e, 0x2e, 0x74, 0x77, 0x65, 0x65, 0x74, 0x2e, 0x4c,
+	0x69, 0x6b, 0x65, 0x52, 0x65, 0x71, 0x1a, 0x0e, 0x2e, 0x74, 0x77, 0x65, 0x65, 0x74, 0x2e, 0x4c,
+	0x69, 0x6b, 0x65, 0x52, 0x65, 0x73, 0x12, 0x2d, 0x0a, 0x0a, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65,
+	0x4c, 0x69, 0x6b, 0x65, 0x12, 0x0e, 0x2e, 0x74, 0x77, 0x65, 0x65, 0x74, 0x2e, 0x4c, 0x69, 0x6b,
+	0x65, 0x52, 0x65, 0x71, 0x1a, 0x0f, 0x2e, 0x74, 0x77, 0x65, 0x65, 0x74, 0x2e, 0x44, 0x4c, 0x69,
+	0x6b, 0x65, 0x52, 0x65, 0x73, 0x12, 0x31, 0x0a, 0x0c, 0x47, 0x65, 0x74, 0x55, 0x73, 0x65, 0x72,
+	0x4c, 0x69, 0x6b, 0x65, 0x73, 0x12, 0x0d, 0x2e, 0x74, 0x77, 0x65, 0x65, 0x74, 0x2e, 0x55, 0x73,
+	0x65, 0x72, 0x49, 0x64, 0x1a, 0x12, 0x2e, 0x74, 0x77, 0x65, 0x65, 0x74, 0x2e, 0x54, 0x77, 0x65,
+	0x65, 0x74, 0x54, 0x69, 0x74, 0x6c, 0x65, 0x73, 0x12, 0x32, 0x0a, 0x12, 0x47, 0x65, 0x74, 0x43,
+	0x6f, 0x75, 0x6e, 0x74, 0x54, 0x77, 0x65, 0x65, 0x74, 0x4c, 0x69, 0x6b, 0x65, 0x73, 0x12, 0x0e,
+	0x2e, 0x74, 0x77, 0x65, 0x65, 0x74, 0x2e, 0x54, 0x77, 0x65, 0x65, 0x74, 0x49, 0x64, 0x1a, 0x0c,
+	0x2e, 0x74, 0x77, 0x65, 0x65, 0x74, 0x2e, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x34, 0x0a, 0x0f,
+	0x4d, 0x6f, 0x73, 0x74, 0x4c, 0x69, 0x6b, 0x65, 0x64, 0x54, 0x77, 0x65, 0x65, 0x74, 0x73, 0x12,
+	0x0b, 0x2e, 0x74, 0x77, 0x65, 0x65, 0x74, 0x2e, 0x56, 0x6f, 0x69, 0x64, 0x1a, 0x14, 0x2e, 0x74,
+	0x77, 0x65, 0x65, 0x74, 0x2e, 0x54, 0x77, 0x65, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x30, 0x0a, 0x0b, 0x50, 0x6f, 0x73, 0x74, 0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e,
+	0x74, 0x12, 0x0e, 0x2e, 0x74, 0x77, 0x65, 0x65, 0x74, 0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e,
+	0x74, 0x1a, 0x11, 0x2e, 0x74, 0x77, 0x65, 0x65, 0x74, 0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e,
+	0x74, 0x52, 0x65, 0x73, 0x12, 0x39, 0x0a, 0x0d, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x43, 0x6f,
+	0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x15, 0x2e, 0x74, 0x77, 0x65, 0x65, 0x74, 0x2e, 0x55, 0x70,
+	0x64, 0x61, 0x74, 0x65, 0x41, 0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x1a, 0x11, 0x2e, 0x74,
 	0x77, 0x65, 0x65, 0x74, 0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x12,
-	0x39, 0x0a, 0x0d, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74,
-	0x12, 0x15, 0x2e, 0x74, 0x77, 0x65, 0x65, 0x74, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x41,
-	0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x1a, 0x11, 0x2e, 0x74, 0x77, 0x65, 0x65, 0x74, 0x2e,
-	0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x12, 0x31, 0x0a, 0x0d, 0x44, 0x65,
-	0x6c, 0x65, 0x74, 0x65, 0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x10, 0x2e, 0x74, 0x77,
-	0x65, 0x65, 0x74, 0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x1a, 0x0e, 0x2e,
-	0x74, 0x77, 0x65, 0x65, 0x74, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x2e, 0x0a,
-	0x0a, 0x47, 0x65, 0x74, 0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x10, 0x2e, 0x74, 0x77,
-	0x65, 0x65, 0x74, 0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x1a, 0x0e, 0x2e,
-	0x74, 0x77, 0x65, 0x65, 0x74, 0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x37, 0x0a,
-	0x0e, 0x47, 0x65, 0x74, 0x41, 0x6c, 0x6c, 0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x12,
-	0x14, 0x2e, 0x74, 0x77, 0x65, 0x65, 0x74, 0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x46,
-	0x69, 0x6c, 0x74, 0x65, 0x72, 0x1a, 0x0f, 0x2e, 0x74, 0x77, 0x65, 0x65, 0x74, 0x2e, 0x43, 0x6f,
-	0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x31, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x55, 0x73, 0x65,
-	0x72, 0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x0d, 0x2e, 0x74, 0x77, 0x65, 0x65,
-	0x74, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x49, 0x64, 0x1a, 0x0f, 0x2e, 0x74, 0x77, 0x65, 0x65, 0x74,
-	0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x39, 0x0a, 0x10, 0x41, 0x64, 0x64,
-	0x4c, 0x69, 0x6b, 0x65, 0x54, 0x6f, 0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x15, 0x2e,
-	0x74, 0x77, 0x65, 0x65, 0x74, 0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x4c, 0x69, 0x6b,
-	0x65, 0x52, 0x65, 0x71, 0x1a, 0x0e, 0x2e, 0x74, 0x77, 0x65, 0x65, 0x74, 0x2e, 0x4d, 0x65, 0x73,
-	0x73, 0x61, 0x67, 0x65, 0x12, 0x3a, 0x0a, 0x11, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x4c, 0x69,
-	0x6b, 0x65, 0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x15, 0x2e, 0x74, 0x77, 0x65, 0x65,
-	0x74, 0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x4c, 0x69, 0x6b, 0x65, 0x52, 0x65, 0x71,
-	0x1a, 0x0e, 0x2e, 0x74, 0x77, 0x65, 0x65, 0x74, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
-	0x42, 0x10, 0x5a, 0x0e, 0x67, 0x65, 0x6e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x74, 0x77, 0x65,
-	0x65, 0x74, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x31, 0x0a, 0x0d, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74,
+	0x12, 0x10, 0x2e, 0x74, 0x77, 0x65, 0x65, 0x74, 0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74,
+	0x49, 0x64, 0x1a, 0x0e, 0x2e, 0x74, 0x77, 0x65, 0x65, 0x74, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x12, 0x2e, 0x0a, 0x0a, 0x47, 0x65, 0x74, 0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74,
+	0x12, 0x10, 0x2e, 0x74, 0x77, 0x65, 0x65, 0x74, 0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74,
+	0x49, 0x64, 0x1a, 0x0e, 0x2e, 0x74, 0x77, 0x65, 0x65, 0x74, 0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x65,
+	0x6e, 0x74, 0x12, 0x37, 0x0a, 0x0e, 0x47, 0x65, 0x74, 0x41, 0x6c, 0x6c, 0x43, 0x6f, 0x6d, 0x6d,
+	0x65, 0x6e, 0x74, 0x73, 0x12, 0x14, 0x2e, 0x74, 0x77, 0x65, 0x65, 0x74, 0x2e, 0x43, 0x6f, 0x6d,
+	0x6d, 0x65, 0x6e, 0x74, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x1a, 0x0f, 0x2e, 0x74, 0x77, 0x65,
+	0x65, 0x74, 0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x31, 0x0a, 0x0f, 0x47,
+	0x65, 0x74, 0x55, 0x73, 0x65, 0x72, 0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x0d,
+	0x2e, 0x74, 0x77, 0x65, 0x65, 0x74, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x49, 0x64, 0x1a, 0x0f, 0x2e,
+	0x74, 0x77, 0x65, 0x65, 0x74, 0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x39,
+	0x0a, 0x10, 0x41, 0x64, 0x64, 0x4c, 0x69, 0x6b, 0x65, 0x54, 0x6f, 0x43, 0x6f, 0x6d, 0x6d, 0x65,
+	0x6e, 0x74, 0x12, 0x15, 0x2e, 0x74, 0x77, 0x65, 0x65, 0x74, 0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x65,
+	0x6e, 0x74, 0x4c, 0x69, 0x6b, 0x65, 0x52, 0x65, 0x71, 0x1a, 0x0e, 0x2e, 0x74, 0x77, 0x65, 0x65,
+	0x74, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x3a, 0x0a, 0x11, 0x44, 0x65, 0x6c,
+	0x65, 0x74, 0x65, 0x4c, 0x69, 0x6b, 0x65, 0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x15,
+	0x2e, 0x74, 0x77, 0x65, 0x65, 0x74, 0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x4c, 0x69,
+	0x6b, 0x65, 0x52, 0x65, 0x71, 0x1a, 0x0e, 0x2e, 0x74, 0x77, 0x65, 0x65, 0x74, 0x2e, 0x4d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x33, 0x0a, 0x0e, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x55,
+	0x73, 0x65, 0x72, 0x44, 0x61, 0x74, 0x61, 0x12, 0x0d, 0x2e, 0x74, 0x77, 0x65, 0x65, 0x74, 0x2e,
+	0x55, 0x73, 0x65, 0x72, 0x49, 0x64, 0x1a, 0x12, 0x2e, 0x74, 0x77, 0x65, 0x65, 0x74, 0x2e, 0x55,
+	0x73, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x32, 0x8b, 0x01, 0x0a, 0x11, 0x44,
+	0x65, 0x61, 0x64, 0x4c, 0x65, 0x74, 0x74, 0x65, 0x72, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x12, 0x3e, 0x0a, 0x0f, 0x4c, 0x69, 0x73, 0x74, 0x44, 0x65, 0x61, 0x64, 0x4c, 0x65, 0x74, 0x74,
+	0x65, 0x72, 0x73, 0x12, 0x17, 0x2e, 0x74, 0x77, 0x65, 0x65, 0x74, 0x2e, 0x44, 0x65, 0x61, 0x64,
+	0x4c, 0x65, 0x74, 0x74, 0x65, 0x72, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x1a, 0x12, 0x2e, 0x74,
+	0x77, 0x65, 0x65, 0x74, 0x2e, 0x44, 0x65, 0x61, 0x64, 0x4c, 0x65, 0x74, 0x74, 0x65, 0x72, 0x73,
+	0x12, 0x36, 0x0a, 0x10, 0x52, 0x65, 0x70, 0x6c, 0x61, 0x79, 0x44, 0x65, 0x61, 0x64, 0x4c, 0x65,
+	0x74, 0x74, 0x65, 0x72, 0x12, 0x10, 0x2e, 0x74, 0x77, 0x65, 0x65, 0x74, 0x2e, 0x52, 0x65, 0x70,
+	0x6c, 0x61, 0x79, 0x52, 0x65, 0x71, 0x1a, 0x10, 0x2e, 0x74, 0x77, 0x65, 0x65, 0x74, 0x2e, 0x52,
+	0x65, 0x70, 0x6c, 0x61, 0x79, 0x52, 0x65, 0x73, 0x42, 0x10, 0x5a, 0x0e, 0x67, 0x65, 0x6e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x74, 0x77, 0x65, 0x65, 0x74, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x33,
 }
 
 var (
@@ -2190,99 +2680,116 @@ func file_tweet_tweet_proto_rawDescGZIP() []byte {
 	return file_tweet_tweet_proto_rawDescData
 }
 
-var file_tweet_tweet_proto_msgTypes = make([]protoimpl.MessageInfo, 28)
+var file_tweet_tweet_proto_msgTypes = make([]protoimpl.MessageInfo, 35)
 var file_tweet_tweet_proto_goTypes = []any{
-	(*ReTweetRes)(nil),     // 0: tweet.ReTweetRes
-	(*ReTweetReq)(nil),     // 1: tweet.ReTweetReq
-	(*Tweet)(nil),          // 2: tweet.Tweet
-	(*TweetResponse)(nil),  // 3: tweet.TweetResponse
-	(*UpdateATweet)(nil),   // 4: tweet.UpdateATweet
-	(*Url)(nil),            // 5: tweet.Url
-	(*Message)(nil),        // 6: tweet.Message
-	(*UserId)(nil),         // 7: tweet.UserId
-	(*Tweets)(nil),         // 8: tweet.Tweets
-	(*TweetId)(nil),        // 9: tweet.TweetId
-	(*TweetFilter)(nil),    // 10: tweet.TweetFilter
-	(*FollowReq)(nil),      // 11: tweet.FollowReq
-	(*FollowRes)(nil),      // 12: tweet.FollowRes
-	(*DFollowRes)(nil),     // 13: tweet.DFollowRes
-	(*Count)(nil),          // 14: tweet.Count
-	(*Void)(nil),           // 15: tweet.Void
-	(*User)(nil),           // 16: tweet.User
-	(*LikeReq)(nil),        // 17: tweet.LikeReq
-	(*LikeRes)(nil),        // 18: tweet.LikeRes
-	(*DLikeRes)(nil),       // 19: tweet.DLikeRes
-	(*TweetTitles)(nil),    // 20: tweet.TweetTitles
-	(*Comment)(nil),        // 21: tweet.Comment
-	(*CommentRes)(nil),     // 22: tweet.CommentRes
-	(*UpdateAComment)(nil), // 23: tweet.UpdateAComment
-	(*CommentId)(nil),      // 24: tweet.CommentId
-	(*CommentFilter)(nil),  // 25: tweet.CommentFilter
-	(*Comments)(nil),       // 26: tweet.Comments
-	(*CommentLikeReq)(nil), // 27: tweet.CommentLikeReq
+	(*ReTweetRes)(nil),       // 0: tweet.ReTweetRes
+	(*ReTweetReq)(nil),       // 1: tweet.ReTweetReq
+	(*Tweet)(nil),            // 2: tweet.Tweet
+	(*TweetResponse)(nil),    // 3: tweet.TweetResponse
+	(*UpdateATweet)(nil),     // 4: tweet.UpdateATweet
+	(*Url)(nil),              // 5: tweet.Url
+	(*Message)(nil),          // 6: tweet.Message
+	(*UserId)(nil),           // 7: tweet.UserId
+	(*Tweets)(nil),           // 8: tweet.Tweets
+	(*TweetId)(nil),          // 9: tweet.TweetId
+	(*TweetFilter)(nil),      // 10: tweet.TweetFilter
+	(*FollowReq)(nil),        // 11: tweet.FollowReq
+	(*FollowRes)(nil),        // 12: tweet.FollowRes
+	(*DFollowRes)(nil),       // 13: tweet.DFollowRes
+	(*Count)(nil),            // 14: tweet.Count
+	(*Void)(nil),             // 15: tweet.Void
+	(*User)(nil),             // 16: tweet.User
+	(*LikeReq)(nil),          // 17: tweet.LikeReq
+	(*LikeRes)(nil),          // 18: tweet.LikeRes
+	(*DLikeRes)(nil),         // 19: tweet.DLikeRes
+	(*TweetTitles)(nil),      // 20: tweet.TweetTitles
+	(*Comment)(nil),          // 21: tweet.Comment
+	(*CommentRes)(nil),       // 22: tweet.CommentRes
+	(*UpdateAComment)(nil),   // 23: tweet.UpdateAComment
+	(*CommentId)(nil),        // 24: tweet.CommentId
+	(*CommentFilter)(nil),    // 25: tweet.CommentFilter
+	(*Comments)(nil),         // 26: tweet.Comments
+	(*CommentLikeReq)(nil),   // 27: tweet.CommentLikeReq
+	(*DeadLetterFilter)(nil), // 28: tweet.DeadLetterFilter
+	(*DeadLetter)(nil),       // 29: tweet.DeadLetter
+	(*DeadLetters)(nil),      // 30: tweet.DeadLetters
+	(*ReplayReq)(nil),        // 31: tweet.ReplayReq
+	(*ReplayRes)(nil),        // 32: tweet.ReplayRes
+	(*LikedTweet)(nil),       // 33: tweet.LikedTweet
+	(*UserContent)(nil),      // 34: tweet.UserContent
 }
 var file_tweet_tweet_proto_depIdxs = []int32{
 	3,  // 0: tweet.Tweets.tweets:type_name -> tweet.TweetResponse
 	22, // 1: tweet.Comments.comments:type_name -> tweet.CommentRes
-	2,  // 2: tweet.TweetService.PostTweet:input_type -> tweet.Tweet
-	4,  // 3: tweet.TweetService.UpdateTweet:input_type -> tweet.UpdateATweet
-	5,  // 4: tweet.TweetService.AddImageToTweet:input_type -> tweet.Url
-	7,  // 5: tweet.TweetService.UserTweets:input_type -> tweet.UserId
-	9,  // 6: tweet.TweetService.GetTweet:input_type -> tweet.TweetId
-	10, // 7: tweet.TweetService.GetAllTweets:input_type -> tweet.TweetFilter
-	7,  // 8: tweet.TweetService.RecommendTweets:input_type -> tweet.UserId
-	7,  // 9: tweet.TweetService.GetNewTweets:input_type -> tweet.UserId
-	1,  // 10: tweet.TweetService.ReTweet:input_type -> tweet.ReTweetReq
-	11, // 11: tweet.TweetService.Follow:input_type -> tweet.FollowReq
-	11, // 12: tweet.TweetService.Unfollow:input_type -> tweet.FollowReq
-	7,  // 13: tweet.TweetService.GetUserFollowers:input_type -> tweet.UserId
-	7,  // 14: tweet.TweetService.GetUserFollows:input_type -> tweet.UserId
-	15, // 15: tweet.TweetService.MostPopularUser:input_type -> tweet.Void
-	17, // 16: tweet.TweetService.AddLike:input_type -> tweet.LikeReq
-	17, // 17: tweet.TweetService.DeleteLike:input_type -> tweet.LikeReq
-	7,  // 18: tweet.TweetService.GetUserLikes:input_type -> tweet.UserId
-	9,  // 19: tweet.TweetService.GetCountTweetLikes:input_type -> tweet.TweetId
-	15, // 20: tweet.TweetService.MostLikedTweets:input_type -> tweet.Void
-	21, // 21: tweet.TweetService.PostComment:input_type -> tweet.Comment
-	23, // 22: tweet.TweetService.UpdateComment:input_type -> tweet.UpdateAComment
-	24, // 23: tweet.TweetService.DeleteComment:input_type -> tweet.CommentId
-	24, // 24: tweet.TweetService.GetComment:input_type -> tweet.CommentId
-	25, // 25: tweet.TweetService.GetAllComments:input_type -> tweet.CommentFilter
-	7,  // 26: tweet.TweetService.GetUserComments:input_type -> tweet.UserId
-	27, // 27: tweet.TweetService.AddLikeToComment:input_type -> tweet.CommentLikeReq
-	27, // 28: tweet.TweetService.DeleteLikeComment:input_type -> tweet.CommentLikeReq
-	3,  // 29: tweet.TweetService.PostTweet:output_type -> tweet.TweetResponse
-	3,  // 30: tweet.TweetService.UpdateTweet:output_type -> tweet.TweetResponse
-	6,  // 31: tweet.TweetService.AddImageToTweet:output_type -> tweet.Message
-	8,  // 32: tweet.TweetService.UserTweets:output_type -> tweet.Tweets
-	3,  // 33: tweet.TweetService.GetTweet:output_type -> tweet.TweetResponse
-	8,  // 34: tweet.TweetService.GetAllTweets:output_type -> tweet.Tweets
-	8,  // 35: tweet.TweetService.RecommendTweets:output_type -> tweet.Tweets
-	8,  // 36: tweet.TweetService.GetNewTweets:output_type -> tweet.Tweets
-	3,  // 37: tweet.TweetService.ReTweet:output_type -> tweet.TweetResponse
-	12, // 38: tweet.TweetService.Follow:output_type -> tweet.FollowRes
-	13, // 39: tweet.TweetService.Unfollow:output_type -> tweet.DFollowRes
-	14, // 40: tweet.TweetService.GetUserFollowers:output_type -> tweet.Count
-	14, // 41: tweet.TweetService.GetUserFollows:output_type -> tweet.Count
-	16, // 42: tweet.TweetService.MostPopularUser:output_type -> tweet.User
-	18, // 43: tweet.TweetService.AddLike:output_type -> tweet.LikeRes
-	19, // 44: tweet.TweetService.DeleteLike:output_type -> tweet.DLikeRes
-	20, // 45: tweet.TweetService.GetUserLikes:output_type -> tweet.TweetTitles
-	14, // 46: tweet.TweetService.GetCountTweetLikes:output_type -> tweet.Count
-	3,  // 47: tweet.TweetService.MostLikedTweets:output_type -> tweet.TweetResponse
-	22, // 48: tweet.TweetService.PostComment:output_type -> tweet.CommentRes
-	22, // 49: tweet.TweetService.UpdateComment:output_type -> tweet.CommentRes
-	6,  // 50: tweet.TweetService.DeleteComment:output_type -> tweet.Message
-	21, // 51: tweet.TweetService.GetComment:output_type -> tweet.Comment
-	26, // 52: tweet.TweetService.GetAllComments:output_type -> tweet.Comments
-	26, // 53: tweet.TweetService.GetUserComments:output_type -> tweet.Comments
-	6,  // 54: tweet.TweetService.AddLikeToComment:output_type -> tweet.Message
-	6,  // 55: tweet.TweetService.DeleteLikeComment:output_type -> tweet.Message
-	29, // [29:56] is the sub-list for method output_type
-	2,  // [2:29] is the sub-list for method input_type
-	2,  // [2:2] is the sub-list for extension type_name
-	2,  // [2:2] is the sub-list for extension extendee
-	0,  // [0:2] is the sub-list for field type_name
+	29, // 2: tweet.DeadLetters.deadLetters:type_name -> tweet.DeadLetter
+	3,  // 3: tweet.UserContent.tweets:type_name -> tweet.TweetResponse
+	22, // 4: tweet.UserContent.comments:type_name -> tweet.CommentRes
+	33, // 5: tweet.UserContent.likes:type_name -> tweet.LikedTweet
+	2,  // 6: tweet.TweetService.PostTweet:input_type -> tweet.Tweet
+	4,  // 7: tweet.TweetService.UpdateTweet:input_type -> tweet.UpdateATweet
+	5,  // 8: tweet.TweetService.AddImageToTweet:input_type -> tweet.Url
+	7,  // 9: tweet.TweetService.UserTweets:input_type -> tweet.UserId
+	9,  // 10: tweet.TweetService.GetTweet:input_type -> tweet.TweetId
+	10, // 11: tweet.TweetService.GetAllTweets:input_type -> tweet.TweetFilter
+	7,  // 12: tweet.TweetService.RecommendTweets:input_type -> tweet.UserId
+	7,  // 13: tweet.TweetService.GetNewTweets:input_type -> tweet.UserId
+	1,  // 14: tweet.TweetService.ReTweet:input_type -> tweet.ReTweetReq
+	11, // 15: tweet.TweetService.Follow:input_type -> tweet.FollowReq
+	11, // 16: tweet.TweetService.Unfollow:input_type -> tweet.FollowReq
+	7,  // 17: tweet.TweetService.GetUserFollowers:input_type -> tweet.UserId
+	7,  // 18: tweet.TweetService.GetUserFollows:input_type -> tweet.UserId
+	15, // 19: tweet.TweetService.MostPopularUser:input_type -> tweet.Void
+	17, // 20: tweet.TweetService.AddLike:input_type -> tweet.LikeReq
+	17, // 21: tweet.TweetService.DeleteLike:input_type -> tweet.LikeReq
+	7,  // 22: tweet.TweetService.GetUserLikes:input_type -> tweet.UserId
+	9,  // 23: tweet.TweetService.GetCountTweetLikes:input_type -> tweet.TweetId
+	15, // 24: tweet.TweetService.MostLikedTweets:input_type -> tweet.Void
+	21, // 25: tweet.TweetService.PostComment:input_type -> tweet.Comment
+	23, // 26: tweet.TweetService.UpdateComment:input_type -> tweet.UpdateAComment
+	24, // 27: tweet.TweetService.DeleteComment:input_type -> tweet.CommentId
+	24, // 28: tweet.TweetService.GetComment:input_type -> tweet.CommentId
+	25, // 29: tweet.TweetService.GetAllComments:input_type -> tweet.CommentFilter
+	7,  // 30: tweet.TweetService.GetUserComments:input_type -> tweet.UserId
+	27, // 31: tweet.TweetService.AddLikeToComment:input_type -> tweet.CommentLikeReq
+	27, // 32: tweet.TweetService.DeleteLikeComment:input_type -> tweet.CommentLikeReq
+	7,  // 33: tweet.TweetService.ExportUserData:input_type -> tweet.UserId
+	28, // 34: tweet.DeadLetterService.ListDeadLetters:input_type -> tweet.DeadLetterFilter
+	31, // 35: tweet.DeadLetterService.ReplayDeadLetter:input_type -> tweet.ReplayReq
+	3,  // 36: tweet.TweetService.PostTweet:output_type -> tweet.TweetResponse
+	3,  // 37: tweet.TweetService.UpdateTweet:output_type -> tweet.TweetResponse
+	6,  // 38: tweet.TweetService.AddImageToTweet:output_type -> tweet.Message
+	8,  // 39: tweet.TweetService.UserTweets:output_type -> tweet.Tweets
+	3,  // 40: tweet.TweetService.GetTweet:output_type -> tweet.TweetResponse
+	8,  // 41: tweet.TweetService.GetAllTweets:output_type -> tweet.Tweets
+	8,  // 42: tweet.TweetService.RecommendTweets:output_type -> tweet.Tweets
+	8,  // 43: tweet.TweetService.GetNewTweets:output_type -> tweet.Tweets
+	3,  // 44: tweet.TweetService.ReTweet:output_type -> tweet.TweetResponse
+	12, // 45: tweet.TweetService.Follow:output_type -> tweet.FollowRes
+	13, // 46: tweet.TweetService.Unfollow:output_type -> tweet.DFollowRes
+	14, // 47: tweet.TweetService.GetUserFollowers:output_type -> tweet.Count
+	14, // 48: tweet.TweetService.GetUserFollows:output_type -> tweet.Count
+	16, // 49: tweet.TweetService.MostPopularUser:output_type -> tweet.User
+	18, // 50: tweet.TweetService.AddLike:output_type -> tweet.LikeRes
+	19, // 51: tweet.TweetService.DeleteLike:output_type -> tweet.DLikeRes
+	20, // 52: tweet.TweetService.GetUserLikes:output_type -> tweet.TweetTitles
+	14, // 53: tweet.TweetService.GetCountTweetLikes:output_type -> tweet.Count
+	3,  // 54: tweet.TweetService.MostLikedTweets:output_type -> tweet.TweetResponse
+	22, // 55: tweet.TweetService.PostComment:output_type -> tweet.CommentRes
+	22, // 56: tweet.TweetService.UpdateComment:output_type -> tweet.CommentRes
+	6,  // 57: tweet.TweetService.DeleteComment:output_type -> tweet.Message
+	21, // 58: tweet.TweetService.GetComment:output_type -> tweet.Comment
+	26, // 59: tweet.TweetService.GetAllComments:output_type -> tweet.Comments
+	26, // 60: tweet.TweetService.GetUserComments:output_type -> tweet.Comments
+	6,  // 61: tweet.TweetService.AddLikeToComment:output_type -> tweet.Message
+	6,  // 62: tweet.TweetService.DeleteLikeComment:output_type -> tweet.Message
+	34, // 63: tweet.TweetService.ExportUserData:output_type -> tweet.UserContent
+	30, // 64: tweet.DeadLetterService.ListDeadLetters:output_type -> tweet.DeadLetters
+	32, // 65: tweet.DeadLetterService.ReplayDeadLetter:output_type -> tweet.ReplayRes
+	36, // [36:66] is the sub-list for method output_type
+	6,  // [6:36] is the sub-list for method input_type
+	6,  // [6:6] is the sub-list for extension type_name
+	6,  // [6:6] is the sub-list for extension extendee
+	0,  // [0:6] is the sub-list for field type_name
 }
 
 func init() { file_tweet_tweet_proto_init() }
@@ -2627,6 +3134,90 @@ func file_tweet_tweet_proto_init() {
 				return nil
 			}
 		}
+		file_tweet_tweet_proto_msgTypes[28].Exporter = func(v any, i int) any {
+			switch v := v.(*DeadLetterFilter); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_tweet_tweet_proto_msgTypes[29].Exporter = func(v any, i int) any {
+			switch v := v.(*DeadLetter); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_tweet_tweet_proto_msgTypes[30].Exporter = func(v any, i int) any {
+			switch v := v.(*DeadLetters); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_tweet_tweet_proto_msgTypes[31].Exporter = func(v any, i int) any {
+			switch v := v.(*ReplayReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_tweet_tweet_proto_msgTypes[32].Exporter = func(v any, i int) any {
+			switch v := v.(*ReplayRes); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_tweet_tweet_proto_msgTypes[33].Exporter = func(v any, i int) any {
+			switch v := v.(*LikedTweet); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_tweet_tweet_proto_msgTypes[34].Exporter = func(v any, i int) any {
+			switch v := v.(*UserContent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -2634,9 +3225,9 @@ func file_tweet_tweet_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_tweet_tweet_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   28,
+			NumMessages:   35,
 			NumExtensions: 0,
-			NumServices:   1,
+			NumServices:   2,
 		},
 		GoTypes:           file_tweet_tweet_proto_goTypes,
 		DependencyIndexes: file_tweet_tweet_proto_depIdxs,
@@ -46,6 +46,7 @@ const (
 	TweetService_GetUserComments_FullMethodName    = "/tweet.TweetService/GetUserComments"
 	TweetService_AddLikeToComment_FullMethodName   = "/tweet.TweetService/AddLikeToComment"
 	TweetService_DeleteLikeComment_FullMethodName  = "/tweet.TweetService/DeleteLikeComment"
+	TweetService_ExportUserData_FullMethodName     = "/tweet.TweetService/ExportUserData"
 )
 
 // TweetServiceClient is the client API for TweetService service.
@@ -83,6 +84,8 @@ type TweetServiceClient interface {
 	GetUserComments(ctx context.Context, in *UserId, opts ...grpc.CallOption) (*Comments, error)
 	AddLikeToComment(ctx context.Context, in *CommentLikeReq, opts ...grpc.CallOption) (*Message, error)
 	DeleteLikeComment(ctx context.Context, in *CommentLikeReq, opts ...grpc.CallOption) (*Message, error)
+	// Account data
+	ExportUserData(ctx context.Context, in *UserId, opts ...grpc.CallOption) (*UserContent, error)
 }
 
 type tweetServiceClient struct {
@@ -363,6 +366,16 @@ func (c *tweetServiceClient) DeleteLikeComment(ctx context.Context, in *CommentL
 	return out, nil
 }
 
+func (c *tweetServiceClient) ExportUserData(ctx context.Context, in *UserId, opts ...grpc.CallOption) (*UserContent, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UserContent)
+	err := c.cc.Invoke(ctx, TweetService_ExportUserData_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // TweetServiceServer is the server API for TweetService service.
 // All implementations must embed UnimplementedTweetServiceServer
 // for forward compatibility
@@ -398,6 +411,8 @@ type TweetServiceServer interface {
 	GetUserComments(context.Context, *UserId) (*Comments, error)
 	AddLikeToComment(context.Context, *CommentLikeReq) (*Message, error)
 	DeleteLikeComment(context.Context, *CommentLikeReq) (*Message, error)
+	// Account data
+	ExportUserData(context.Context, *UserId) (*UserContent, error)
 	mustEmbedUnimplementedTweetServiceServer()
 }
 
@@ -486,6 +501,9 @@ func (UnimplementedTweetServiceServer) AddLikeToComment(context.Context, *Commen
 func (UnimplementedTweetServiceServer) DeleteLikeComment(context.Context, *CommentLikeReq) (*Message, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteLikeComment not implemented")
 }
+func (UnimplementedTweetServiceServer) ExportUserData(context.Context, *UserId) (*UserContent, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportUserData not implemented")
+}
 func (UnimplementedTweetServiceServer) mustEmbedUnimplementedTweetServiceServer() {}
 
 // UnsafeTweetServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -985,6 +1003,24 @@ func _TweetService_DeleteLikeComment_Handler(srv interface{}, ctx context.Contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TweetService_ExportUserData_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserId)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TweetServiceServer).ExportUserData(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TweetService_ExportUserData_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TweetServiceServer).ExportUserData(ctx, req.(*UserId))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // TweetService_ServiceDesc is the grpc.ServiceDesc for TweetService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -1100,6 +1136,145 @@ var TweetService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteLikeComment",
 			Handler:    _TweetService_DeleteLikeComment_Handler,
 		},
+		{
+			MethodName: "ExportUserData",
+			Handler:    _TweetService_ExportUserData_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "tweet/tweet.proto",
+}
+
+const (
+	DeadLetterService_ListDeadLetters_FullMethodName  = "/tweet.DeadLetterService/ListDeadLetters"
+	DeadLetterService_ReplayDeadLetter_FullMethodName = "/tweet.DeadLetterService/ReplayDeadLetter"
+)
+
+// DeadLetterServiceClient is the client API for DeadLetterService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// DeadLetterService lets admins inspect and replay commands that exhausted
+// their retries or could not be processed at all.
+type DeadLetterServiceClient interface {
+	ListDeadLetters(ctx context.Context, in *DeadLetterFilter, opts ...grpc.CallOption) (*DeadLetters, error)
+	ReplayDeadLetter(ctx context.Context, in *ReplayReq, opts ...grpc.CallOption) (*ReplayRes, error)
+}
+
+type deadLetterServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewDeadLetterServiceClient(cc grpc.ClientConnInterface) DeadLetterServiceClient {
+	return &deadLetterServiceClient{cc}
+}
+
+func (c *deadLetterServiceClient) ListDeadLetters(ctx context.Context, in *DeadLetterFilter, opts ...grpc.CallOption) (*DeadLetters, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeadLetters)
+	err := c.cc.Invoke(ctx, DeadLetterService_ListDeadLetters_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *deadLetterServiceClient) ReplayDeadLetter(ctx context.Context, in *ReplayReq, opts ...grpc.CallOption) (*ReplayRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReplayRes)
+	err := c.cc.Invoke(ctx, DeadLetterService_ReplayDeadLetter_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DeadLetterServiceServer is the server API for DeadLetterService service.
+// All implementations must embed UnimplementedDeadLetterServiceServer
+// for forward compatibility
+//
+// DeadLetterService lets admins inspect and replay commands that exhausted
+// their retries or could not be processed at all.
+type DeadLetterServiceServer interface {
+	ListDeadLetters(context.Context, *DeadLetterFilter) (*DeadLetters, error)
+	ReplayDeadLetter(context.Context, *ReplayReq) (*ReplayRes, error)
+	mustEmbedUnimplementedDeadLetterServiceServer()
+}
+
+// UnimplementedDeadLetterServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedDeadLetterServiceServer struct {
+}
+
+func (UnimplementedDeadLetterServiceServer) ListDeadLetters(context.Context, *DeadLetterFilter) (*DeadLetters, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDeadLetters not implemented")
+}
+func (UnimplementedDeadLetterServiceServer) ReplayDeadLetter(context.Context, *ReplayReq) (*ReplayRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReplayDeadLetter not implemented")
+}
+func (UnimplementedDeadLetterServiceServer) mustEmbedUnimplementedDeadLetterServiceServer() {}
+
+// UnsafeDeadLetterServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to DeadLetterServiceServer will
+// result in compilation errors.
+type UnsafeDeadLetterServiceServer interface {
+	mustEmbedUnimplementedDeadLetterServiceServer()
+}
+
+func RegisterDeadLetterServiceServer(s grpc.ServiceRegistrar, srv DeadLetterServiceServer) {
+	s.RegisterService(&DeadLetterService_ServiceDesc, srv)
+}
+
+func _DeadLetterService_ListDeadLetters_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeadLetterFilter)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeadLetterServiceServer).ListDeadLetters(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DeadLetterService_ListDeadLetters_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeadLetterServiceServer).ListDeadLetters(ctx, req.(*DeadLetterFilter))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DeadLetterService_ReplayDeadLetter_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReplayReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeadLetterServiceServer).ReplayDeadLetter(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DeadLetterService_ReplayDeadLetter_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeadLetterServiceServer).ReplayDeadLetter(ctx, req.(*ReplayReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// DeadLetterService_ServiceDesc is the grpc.ServiceDesc for DeadLetterService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var DeadLetterService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "tweet.DeadLetterService",
+	HandlerType: (*DeadLetterServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListDeadLetters",
+			Handler:    _DeadLetterService_ListDeadLetters_Handler,
+		},
+		{
+			MethodName: "ReplayDeadLetter",
+			Handler:    _DeadLetterService_ReplayDeadLetter_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "tweet/tweet.proto",
@@ -30,11 +30,26 @@ const (
 	UserService_ListOfFollowingByUsername_FullMethodName = "/user.UserService/ListOfFollowingByUsername"
 	UserService_ListOfFollowersByUsername_FullMethodName = "/user.UserService/ListOfFollowersByUsername"
 	UserService_DeleteUser_FullMethodName                = "/user.UserService/DeleteUser"
+	UserService_ChangeUsername_FullMethodName            = "/user.UserService/ChangeUsername"
+	UserService_CheckUsername_FullMethodName             = "/user.UserService/CheckUsername"
+	UserService_ResolveUsername_FullMethodName           = "/user.UserService/ResolveUsername"
 	UserService_Follow_FullMethodName                    = "/user.UserService/Follow"
 	UserService_Unfollow_FullMethodName                  = "/user.UserService/Unfollow"
 	UserService_GetUserFollowers_FullMethodName          = "/user.UserService/GetUserFollowers"
 	UserService_GetUserFollows_FullMethodName            = "/user.UserService/GetUserFollows"
 	UserService_MostPopularUser_FullMethodName           = "/user.UserService/MostPopularUser"
+	UserService_SuggestUsers_FullMethodName              = "/user.UserService/SuggestUsers"
+	UserService_SearchUsers_FullMethodName               = "/user.UserService/SearchUsers"
+	UserService_Block_FullMethodName                     = "/user.UserService/Block"
+	UserService_Unblock_FullMethodName                   = "/user.UserService/Unblock"
+	UserService_GetRelationships_FullMethodName          = "/user.UserService/GetRelationships"
+	UserService_GetMutualFollowers_FullMethodName        = "/user.UserService/GetMutualFollowers"
+	UserService_RequestDataExport_FullMethodName         = "/user.UserService/RequestDataExport"
+	UserService_RequestDeletion_FullMethodName           = "/user.UserService/RequestDeletion"
+	UserService_CancelDeletion_FullMethodName            = "/user.UserService/CancelDeletion"
+	UserService_SetRole_FullMethodName                   = "/user.UserService/SetRole"
+	UserService_SetVerified_FullMethodName               = "/user.UserService/SetVerified"
+	UserService_ListAuditLog_FullMethodName              = "/user.UserService/ListAuditLog"
 )
 
 // UserServiceClient is the client API for UserService service.
@@ -55,12 +70,30 @@ type UserServiceClient interface {
 	ListOfFollowingByUsername(ctx context.Context, in *Id, opts ...grpc.CallOption) (*Followings, error)
 	ListOfFollowersByUsername(ctx context.Context, in *Id, opts ...grpc.CallOption) (*Followers, error)
 	DeleteUser(ctx context.Context, in *Id, opts ...grpc.CallOption) (*Void, error)
+	ChangeUsername(ctx context.Context, in *ChangeUsernameReq, opts ...grpc.CallOption) (*UserResponse, error)
+	CheckUsername(ctx context.Context, in *Username, opts ...grpc.CallOption) (*UsernameAvailability, error)
+	// ResolveUsername finds the account of a current or former username.
+	ResolveUsername(ctx context.Context, in *Username, opts ...grpc.CallOption) (*ResolvedUsername, error)
 	// subscribe
 	Follow(ctx context.Context, in *FollowReq, opts ...grpc.CallOption) (*FollowRes, error)
 	Unfollow(ctx context.Context, in *FollowReq, opts ...grpc.CallOption) (*DFollowRes, error)
 	GetUserFollowers(ctx context.Context, in *Id, opts ...grpc.CallOption) (*Count, error)
 	GetUserFollows(ctx context.Context, in *Id, opts ...grpc.CallOption) (*Count, error)
 	MostPopularUser(ctx context.Context, in *Void, opts ...grpc.CallOption) (*UserResponse, error)
+	SuggestUsers(ctx context.Context, in *Id, opts ...grpc.CallOption) (*Suggestions, error)
+	SearchUsers(ctx context.Context, in *SearchReq, opts ...grpc.CallOption) (*SearchResults, error)
+	Block(ctx context.Context, in *BlockReq, opts ...grpc.CallOption) (*Void, error)
+	Unblock(ctx context.Context, in *BlockReq, opts ...grpc.CallOption) (*Void, error)
+	GetRelationships(ctx context.Context, in *RelationshipsReq, opts ...grpc.CallOption) (*Relationships, error)
+	GetMutualFollowers(ctx context.Context, in *MutualFollowersReq, opts ...grpc.CallOption) (*MutualFollowers, error)
+	// account data
+	RequestDataExport(ctx context.Context, in *Id, opts ...grpc.CallOption) (*UserDataExport, error)
+	RequestDeletion(ctx context.Context, in *Id, opts ...grpc.CallOption) (*DeletionStatus, error)
+	CancelDeletion(ctx context.Context, in *Id, opts ...grpc.CallOption) (*DeletionStatus, error)
+	// administration
+	SetRole(ctx context.Context, in *SetRoleReq, opts ...grpc.CallOption) (*UserResponse, error)
+	SetVerified(ctx context.Context, in *SetVerifiedReq, opts ...grpc.CallOption) (*UserResponse, error)
+	ListAuditLog(ctx context.Context, in *AuditFilter, opts ...grpc.CallOption) (*AuditEntries, error)
 }
 
 type userServiceClient struct {
@@ -181,6 +214,36 @@ func (c *userServiceClient) DeleteUser(ctx context.Context, in *Id, opts ...grpc
 	return out, nil
 }
 
+func (c *userServiceClient) ChangeUsername(ctx context.Context, in *ChangeUsernameReq, opts ...grpc.CallOption) (*UserResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UserResponse)
+	err := c.cc.Invoke(ctx, UserService_ChangeUsername_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) CheckUsername(ctx context.Context, in *Username, opts ...grpc.CallOption) (*UsernameAvailability, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UsernameAvailability)
+	err := c.cc.Invoke(ctx, UserService_CheckUsername_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) ResolveUsername(ctx context.Context, in *Username, opts ...grpc.CallOption) (*ResolvedUsername, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ResolvedUsername)
+	err := c.cc.Invoke(ctx, UserService_ResolveUsername_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *userServiceClient) Follow(ctx context.Context, in *FollowReq, opts ...grpc.CallOption) (*FollowRes, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(FollowRes)
@@ -231,6 +294,126 @@ func (c *userServiceClient) MostPopularUser(ctx context.Context, in *Void, opts
 	return out, nil
 }
 
+func (c *userServiceClient) SuggestUsers(ctx context.Context, in *Id, opts ...grpc.CallOption) (*Suggestions, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Suggestions)
+	err := c.cc.Invoke(ctx, UserService_SuggestUsers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) SearchUsers(ctx context.Context, in *SearchReq, opts ...grpc.CallOption) (*SearchResults, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SearchResults)
+	err := c.cc.Invoke(ctx, UserService_SearchUsers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) Block(ctx context.Context, in *BlockReq, opts ...grpc.CallOption) (*Void, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Void)
+	err := c.cc.Invoke(ctx, UserService_Block_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) Unblock(ctx context.Context, in *BlockReq, opts ...grpc.CallOption) (*Void, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Void)
+	err := c.cc.Invoke(ctx, UserService_Unblock_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) GetRelationships(ctx context.Context, in *RelationshipsReq, opts ...grpc.CallOption) (*Relationships, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Relationships)
+	err := c.cc.Invoke(ctx, UserService_GetRelationships_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) GetMutualFollowers(ctx context.Context, in *MutualFollowersReq, opts ...grpc.CallOption) (*MutualFollowers, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MutualFollowers)
+	err := c.cc.Invoke(ctx, UserService_GetMutualFollowers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) RequestDataExport(ctx context.Context, in *Id, opts ...grpc.CallOption) (*UserDataExport, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UserDataExport)
+	err := c.cc.Invoke(ctx, UserService_RequestDataExport_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) RequestDeletion(ctx context.Context, in *Id, opts ...grpc.CallOption) (*DeletionStatus, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeletionStatus)
+	err := c.cc.Invoke(ctx, UserService_RequestDeletion_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) CancelDeletion(ctx context.Context, in *Id, opts ...grpc.CallOption) (*DeletionStatus, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeletionStatus)
+	err := c.cc.Invoke(ctx, UserService_CancelDeletion_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) SetRole(ctx context.Context, in *SetRoleReq, opts ...grpc.CallOption) (*UserResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UserResponse)
+	err := c.cc.Invoke(ctx, UserService_SetRole_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) SetVerified(ctx context.Context, in *SetVerifiedReq, opts ...grpc.CallOption) (*UserResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UserResponse)
+	err := c.cc.Invoke(ctx, UserService_SetVerified_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) ListAuditLog(ctx context.Context, in *AuditFilter, opts ...grpc.CallOption) (*AuditEntries, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AuditEntries)
+	err := c.cc.Invoke(ctx, UserService_ListAuditLog_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // UserServiceServer is the server API for UserService service.
 // All implementations must embed UnimplementedUserServiceServer
 // for forward compatibility
@@ -249,12 +432,30 @@ type UserServiceServer interface {
 	ListOfFollowingByUsername(context.Context, *Id) (*Followings, error)
 	ListOfFollowersByUsername(context.Context, *Id) (*Followers, error)
 	DeleteUser(context.Context, *Id) (*Void, error)
+	ChangeUsername(context.Context, *ChangeUsernameReq) (*UserResponse, error)
+	CheckUsername(context.Context, *Username) (*UsernameAvailability, error)
+	// ResolveUsername finds the account of a current or former username.
+	ResolveUsername(context.Context, *Username) (*ResolvedUsername, error)
 	// subscribe
 	Follow(context.Context, *FollowReq) (*FollowRes, error)
 	Unfollow(context.Context, *FollowReq) (*DFollowRes, error)
 	GetUserFollowers(context.Context, *Id) (*Count, error)
 	GetUserFollows(context.Context, *Id) (*Count, error)
 	MostPopularUser(context.Context, *Void) (*UserResponse, error)
+	SuggestUsers(context.Context, *Id) (*Suggestions, error)
+	SearchUsers(context.Context, *SearchReq) (*SearchResults, error)
+	Block(context.Context, *BlockReq) (*Void, error)
+	Unblock(context.Context, *BlockReq) (*Void, error)
+	GetRelationships(context.Context, *RelationshipsReq) (*Relationships, error)
+	GetMutualFollowers(context.Context, *MutualFollowersReq) (*MutualFollowers, error)
+	// account data
+	RequestDataExport(context.Context, *Id) (*UserDataExport, error)
+	RequestDeletion(context.Context, *Id) (*DeletionStatus, error)
+	CancelDeletion(context.Context, *Id) (*DeletionStatus, error)
+	// administration
+	SetRole(context.Context, *SetRoleReq) (*UserResponse, error)
+	SetVerified(context.Context, *SetVerifiedReq) (*UserResponse, error)
+	ListAuditLog(context.Context, *AuditFilter) (*AuditEntries, error)
 	mustEmbedUnimplementedUserServiceServer()
 }
 
@@ -295,6 +496,15 @@ func (UnimplementedUserServiceServer) ListOfFollowersByUsername(context.Context,
 func (UnimplementedUserServiceServer) DeleteUser(context.Context, *Id) (*Void, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteUser not implemented")
 }
+func (UnimplementedUserServiceServer) ChangeUsername(context.Context, *ChangeUsernameReq) (*UserResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ChangeUsername not implemented")
+}
+func (UnimplementedUserServiceServer) CheckUsername(context.Context, *Username) (*UsernameAvailability, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckUsername not implemented")
+}
+func (UnimplementedUserServiceServer) ResolveUsername(context.Context, *Username) (*ResolvedUsername, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResolveUsername not implemented")
+}
 func (UnimplementedUserServiceServer) Follow(context.Context, *FollowReq) (*FollowRes, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Follow not implemented")
 }
@@ -310,6 +520,42 @@ func (UnimplementedUserServiceServer) GetUserFollows(context.Context, *Id) (*Cou
 func (UnimplementedUserServiceServer) MostPopularUser(context.Context, *Void) (*UserResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method MostPopularUser not implemented")
 }
+func (UnimplementedUserServiceServer) SuggestUsers(context.Context, *Id) (*Suggestions, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SuggestUsers not implemented")
+}
+func (UnimplementedUserServiceServer) SearchUsers(context.Context, *SearchReq) (*SearchResults, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchUsers not implemented")
+}
+func (UnimplementedUserServiceServer) Block(context.Context, *BlockReq) (*Void, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Block not implemented")
+}
+func (UnimplementedUserServiceServer) Unblock(context.Context, *BlockReq) (*Void, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Unblock not implemented")
+}
+func (UnimplementedUserServiceServer) GetRelationships(context.Context, *RelationshipsReq) (*Relationships, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRelationships not implemented")
+}
+func (UnimplementedUserServiceServer) GetMutualFollowers(context.Context, *MutualFollowersReq) (*MutualFollowers, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMutualFollowers not implemented")
+}
+func (UnimplementedUserServiceServer) RequestDataExport(context.Context, *Id) (*UserDataExport, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RequestDataExport not implemented")
+}
+func (UnimplementedUserServiceServer) RequestDeletion(context.Context, *Id) (*DeletionStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RequestDeletion not implemented")
+}
+func (UnimplementedUserServiceServer) CancelDeletion(context.Context, *Id) (*DeletionStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelDeletion not implemented")
+}
+func (UnimplementedUserServiceServer) SetRole(context.Context, *SetRoleReq) (*UserResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetRole not implemented")
+}
+func (UnimplementedUserServiceServer) SetVerified(context.Context, *SetVerifiedReq) (*UserResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetVerified not implemented")
+}
+func (UnimplementedUserServiceServer) ListAuditLog(context.Context, *AuditFilter) (*AuditEntries, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAuditLog not implemented")
+}
 func (UnimplementedUserServiceServer) mustEmbedUnimplementedUserServiceServer() {}
 
 // UnsafeUserServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -521,6 +767,60 @@ func _UserService_DeleteUser_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _UserService_ChangeUsername_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChangeUsernameReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).ChangeUsername(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_ChangeUsername_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).ChangeUsername(ctx, req.(*ChangeUsernameReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_CheckUsername_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Username)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).CheckUsername(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_CheckUsername_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).CheckUsername(ctx, req.(*Username))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_ResolveUsername_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Username)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).ResolveUsername(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_ResolveUsername_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).ResolveUsername(ctx, req.(*Username))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _UserService_Follow_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(FollowReq)
 	if err := dec(in); err != nil {
@@ -611,6 +911,222 @@ func _UserService_MostPopularUser_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _UserService_SuggestUsers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Id)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).SuggestUsers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_SuggestUsers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).SuggestUsers(ctx, req.(*Id))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_SearchUsers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).SearchUsers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_SearchUsers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).SearchUsers(ctx, req.(*SearchReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_Block_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BlockReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).Block(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_Block_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).Block(ctx, req.(*BlockReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_Unblock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BlockReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).Unblock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_Unblock_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).Unblock(ctx, req.(*BlockReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_GetRelationships_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RelationshipsReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).GetRelationships(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_GetRelationships_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).GetRelationships(ctx, req.(*RelationshipsReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_GetMutualFollowers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MutualFollowersReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).GetMutualFollowers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_GetMutualFollowers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).GetMutualFollowers(ctx, req.(*MutualFollowersReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_RequestDataExport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Id)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).RequestDataExport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_RequestDataExport_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).RequestDataExport(ctx, req.(*Id))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_RequestDeletion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Id)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).RequestDeletion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_RequestDeletion_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).RequestDeletion(ctx, req.(*Id))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_CancelDeletion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Id)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).CancelDeletion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_CancelDeletion_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).CancelDeletion(ctx, req.(*Id))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_SetRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetRoleReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).SetRole(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_SetRole_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).SetRole(ctx, req.(*SetRoleReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_SetVerified_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetVerifiedReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).SetVerified(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_SetVerified_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).SetVerified(ctx, req.(*SetVerifiedReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_ListAuditLog_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AuditFilter)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).ListAuditLog(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_ListAuditLog_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).ListAuditLog(ctx, req.(*AuditFilter))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // UserService_ServiceDesc is the grpc.ServiceDesc for UserService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -662,6 +1178,18 @@ var UserService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteUser",
 			Handler:    _UserService_DeleteUser_Handler,
 		},
+		{
+			MethodName: "ChangeUsername",
+			Handler:    _UserService_ChangeUsername_Handler,
+		},
+		{
+			MethodName: "CheckUsername",
+			Handler:    _UserService_CheckUsername_Handler,
+		},
+		{
+			MethodName: "ResolveUsername",
+			Handler:    _UserService_ResolveUsername_Handler,
+		},
 		{
 			MethodName: "Follow",
 			Handler:    _UserService_Follow_Handler,
@@ -682,6 +1210,54 @@ var UserService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "MostPopularUser",
 			Handler:    _UserService_MostPopularUser_Handler,
 		},
+		{
+			MethodName: "SuggestUsers",
+			Handler:    _UserService_SuggestUsers_Handler,
+		},
+		{
+			MethodName: "SearchUsers",
+			Handler:    _UserService_SearchUsers_Handler,
+		},
+		{
+			MethodName: "Block",
+			Handler:    _UserService_Block_Handler,
+		},
+		{
+			MethodName: "Unblock",
+			Handler:    _UserService_Unblock_Handler,
+		},
+		{
+			MethodName: "GetRelationships",
+			Handler:    _UserService_GetRelationships_Handler,
+		},
+		{
+			MethodName: "GetMutualFollowers",
+			Handler:    _UserService_GetMutualFollowers_Handler,
+		},
+		{
+			MethodName: "RequestDataExport",
+			Handler:    _UserService_RequestDataExport_Handler,
+		},
+		{
+			MethodName: "RequestDeletion",
+			Handler:    _UserService_RequestDeletion_Handler,
+		},
+		{
+			MethodName: "CancelDeletion",
+			Handler:    _UserService_CancelDeletion_Handler,
+		},
+		{
+			MethodName: "SetRole",
+			Handler:    _UserService_SetRole_Handler,
+		},
+		{
+			MethodName: "SetVerified",
+			Handler:    _UserService_SetVerified_Handler,
+		},
+		{
+			MethodName: "ListAuditLog",
+			Handler:    _UserService_ListAuditLog_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "user/user.proto",
@@ -1,7 +1,12 @@
 package config
 
 import (
+	"fmt"
+	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/spf13/cast"
 )
@@ -9,9 +14,63 @@ import (
 type Config struct {
 	TWITTER_SERVICE string
 	USER_SERVICE    string
+	REDIS_ADDR      string
 
-	ACCESS_TOKEN  string
+	// JWKS_URL is where auth-service publishes the keys that verify access
+	// tokens.
+	JWKS_URL      string
 	REFRESH_TOKEN string
+
+	// TRACING_EXPORTER is otlp, stdout or none. OTLP_ENDPOINT is the
+	// collector the otlp exporter sends spans to over gRPC, and
+	// TRACING_SAMPLE_RATIO the share of new traces that are kept.
+	TRACING_EXPORTER     string
+	OTLP_ENDPOINT        string
+	TRACING_SAMPLE_RATIO float64
+
+	// RATE_LIMIT_<GROUP>_USER and RATE_LIMIT_<GROUP>_IP bound the requests
+	// one user and one client address can make to a route group, written as
+	// requests/period, e.g. 30/1m. The IP limits also cover users spreading
+	// their requests over several accounts.
+	RATE_LIMIT_TWEET_USER  RateLimit
+	RATE_LIMIT_TWEET_IP    RateLimit
+	RATE_LIMIT_LIKE_USER   RateLimit
+	RATE_LIMIT_LIKE_IP     RateLimit
+	RATE_LIMIT_FOLLOW_USER RateLimit
+	RATE_LIMIT_FOLLOW_IP   RateLimit
+
+	// TRUSTED_PROXIES are the addresses or CIDR ranges of the proxies in
+	// front of the gateway, comma separated. Only they may name the client
+	// in X-Forwarded-For; by default no one can, and the client address is
+	// the one the connection comes from.
+	TRUSTED_PROXIES []string
+}
+
+// RateLimit allows bursts of Requests, refilled evenly over Per.
+type RateLimit struct {
+	Requests int
+	Per      time.Duration
+}
+
+func (l RateLimit) String() string {
+	return fmt.Sprintf("%d/%s", l.Requests, l.Per)
+}
+
+// ParseRateLimit reads a limit written as requests/period, e.g. 30/1m.
+func ParseRateLimit(s string) (RateLimit, error) {
+	requests, period, ok := strings.Cut(s, "/")
+	if !ok {
+		return RateLimit{}, fmt.Errorf("rate limit %q is not requests/period", s)
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(requests))
+	if err != nil || n <= 0 {
+		return RateLimit{}, fmt.Errorf("rate limit %q: requests must be a positive number", s)
+	}
+	per, err := time.ParseDuration(strings.TrimSpace(period))
+	if err != nil || per <= 0 {
+		return RateLimit{}, fmt.Errorf("rate limit %q: period must be a positive duration", s)
+	}
+	return RateLimit{Requests: n, Per: per}, nil
 }
 
 func Load() Config {
@@ -23,8 +82,19 @@ func Load() Config {
 
 	config.TWITTER_SERVICE = cast.ToString(coalesce("TWITTER_SERVICE", ":8088"))
 	config.USER_SERVICE = cast.ToString(coalesce("USER_SERVICE", "localhost:50050"))
-	config.ACCESS_TOKEN = cast.ToString(coalesce("ACCESS_TOKEN", "hello world"))
+	config.REDIS_ADDR = cast.ToString(coalesce("REDIS_ADDR", "localhost:6379"))
+	config.JWKS_URL = cast.ToString(coalesce("JWKS_URL", "http://localhost:8081/.well-known/jwks.json"))
 	config.REFRESH_TOKEN = cast.ToString(coalesce("REFRESH_TOKEN", "dodi"))
+	config.TRACING_EXPORTER = cast.ToString(coalesce("TRACING_EXPORTER", "none"))
+	config.OTLP_ENDPOINT = cast.ToString(coalesce("OTLP_ENDPOINT", "localhost:4317"))
+	config.TRACING_SAMPLE_RATIO = cast.ToFloat64(coalesce("TRACING_SAMPLE_RATIO", "1"))
+	config.RATE_LIMIT_TWEET_USER = rateLimit("RATE_LIMIT_TWEET_USER", "30/1m")
+	config.RATE_LIMIT_TWEET_IP = rateLimit("RATE_LIMIT_TWEET_IP", "120/1m")
+	config.RATE_LIMIT_LIKE_USER = rateLimit("RATE_LIMIT_LIKE_USER", "60/1m")
+	config.RATE_LIMIT_LIKE_IP = rateLimit("RATE_LIMIT_LIKE_IP", "240/1m")
+	config.RATE_LIMIT_FOLLOW_USER = rateLimit("RATE_LIMIT_FOLLOW_USER", "20/1m")
+	config.RATE_LIMIT_FOLLOW_IP = rateLimit("RATE_LIMIT_FOLLOW_IP", "60/1m")
+	config.TRUSTED_PROXIES = list(cast.ToString(coalesce("TRUSTED_PROXIES", "")))
 	return config
 
 }
@@ -38,3 +108,22 @@ func coalesce(key string, defaultValue interface{}) interface{} {
 
 	return defaultValue
 }
+
+func rateLimit(key string, defaultValue string) RateLimit {
+	limit, err := ParseRateLimit(cast.ToString(coalesce(key, defaultValue)))
+	if err != nil {
+		log.Fatalf("%s: %v", key, err)
+	}
+	return limit
+}
+
+// list splits a comma separated value, dropping empty entries.
+func list(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,32 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRateLimit(t *testing.T) {
+	tests := []struct {
+		in   string
+		want RateLimit
+		err  bool
+	}{
+		{"30/1m", RateLimit{30, time.Minute}, false},
+		{" 5 / 10s ", RateLimit{5, 10 * time.Second}, false},
+		{"30", RateLimit{}, true},
+		{"0/1m", RateLimit{}, true},
+		{"ten/1m", RateLimit{}, true},
+		{"10/forever", RateLimit{}, true},
+		{"10/-1m", RateLimit{}, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseRateLimit(tt.in)
+		if (err != nil) != tt.err {
+			t.Errorf("ParseRateLimit(%q) error = %v, want error %v", tt.in, err, tt.err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseRateLimit(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
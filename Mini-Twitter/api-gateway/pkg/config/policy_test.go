@@ -0,0 +1,38 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/casbin/casbin/v2"
+)
+
+func TestPolicyRoles(t *testing.T) {
+	enf, err := casbin.NewEnforcer("model.conf", "policy.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		role, path, method string
+		allow              bool
+	}{
+		{"user", "/user/get_profile", "GET", true},
+		{"user", "/tweet/add", "POST", true},
+		{"user", "/admin/users/:user_id/role", "PUT", false},
+		{"admin", "/user/get_profile", "GET", true},
+		{"admin", "/admin/users/:user_id/verified", "PUT", true},
+		{"admin", "/admin/delete/:user_id", "DELETE", true},
+		{"c-admin", "/admin/users/:user_id/role", "PUT", true},
+		{"c-admin", "/like/add", "POST", true},
+		{"", "/user/get_profile", "GET", false},
+	}
+	for _, tt := range tests {
+		allow, err := enf.Enforce(tt.role, tt.path, tt.method)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if allow != tt.allow {
+			t.Errorf("%s %s %s: allow = %v, want %v", tt.role, tt.method, tt.path, allow, tt.allow)
+		}
+	}
+}
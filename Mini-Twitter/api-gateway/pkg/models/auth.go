@@ -31,6 +31,8 @@ type UserResponse struct {
 	Nationality string `json:"nationality" db:"nationality"`
 	Bio         string `json:"bio" db:"bio"`
 	CreatedAt   string `json:"created_at" db:"created_at"`
+	Role        string `json:"role" db:"role"`
+	Verified    bool   `json:"verified" db:"verified"`
 }
 
 // LoginRequest message uchun struktura
@@ -61,6 +63,7 @@ type GetProfileResponse struct {
 	PostsCount     int32  `json:"posts_count" db:"posts_count"`
 	CreatedAt      string `json:"created_at" db:"created_at"`
 	UpdatedAt      string `json:"updated_at" db:"updated_at"`
+	Verified       bool   `json:"verified" db:"verified"`
 }
 
 // UpdateProfileRequest message uchun struktura
@@ -199,8 +202,16 @@ type UserID struct {
 	ID string `json:"id" db:"id"`
 }
 
+// Error is the body of every failed request.
 type Error struct {
-	Message string `json:"message" db:"message"`
+	Error ErrorBody `json:"error"`
+}
+
+// ErrorBody says what went wrong. Code is stable and meant for clients to
+// switch on, Message is meant for people.
+type ErrorBody struct {
+	Code    string `json:"code" example:"NOT_FOUND"`
+	Message string `json:"message" example:"tweet not found"`
 }
 
 // Tweets struct corresponds to the Tweets message
@@ -305,3 +316,33 @@ type Comments struct {
 type CommentLikeReq struct {
 	CommentID string `json:"comment_id" db:"comment_id"`
 }
+
+// CommandAccepted is returned for writes that are processed asynchronously.
+type CommandAccepted struct {
+	CommandID string `json:"command_id"`
+	Status    string `json:"status"`
+}
+
+// SetRoleRequest is the body of an admin role change.
+type SetRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=user admin c-admin"`
+}
+
+// SetVerifiedRequest grants or takes a verified badge.
+type SetVerifiedRequest struct {
+	Verified bool   `json:"verified"`
+	Reason   string `json:"reason"`
+}
+
+// ChangeUsernameRequest is the body of a username change.
+type ChangeUsernameRequest struct {
+	Username string `json:"username" binding:"required"`
+}
+
+// Readiness says whether the gateway can serve requests, and which
+// dependency it cannot serve without.
+type Readiness struct {
+	Status string `json:"status" example:"ok"`
+	// Checks are "ok" or "unavailable" by dependency.
+	Checks map[string]string `json:"checks"`
+}
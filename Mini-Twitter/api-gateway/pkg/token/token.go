@@ -2,28 +2,52 @@ package token
 
 import (
 	"apigateway/pkg/config"
+	"common/jwks"
+	"context"
 	"fmt"
 	"strings"
+	"sync"
 
 	"github.com/golang-jwt/jwt"
 )
 
-func ValidateToken(tokenstr string) (bool, error) {
-	_, err := ExtractClaims(tokenstr)
+var (
+	keysOnce sync.Once
+	jwksKeys *jwks.Cache
+)
+
+// keys returns the JWKS cache of auth-service, created on first use.
+func keys() *jwks.Cache {
+	keysOnce.Do(func() {
+		jwksKeys = jwks.New(config.Load().JWKS_URL)
+	})
+	return jwksKeys
+}
+
+func ValidateToken(ctx context.Context, tokenstr string) (bool, error) {
+	_, err := ExtractClaims(ctx, tokenstr)
 	if err != nil {
 		return false, err
 	}
 	return true, nil
 }
 
-func ExtractClaims(tokenstr string) (jwt.MapClaims, error) {
+// ExtractClaims verifies an access token with the auth-service key named by
+// its kid header. ctx bounds fetching the key.
+func ExtractClaims(ctx context.Context, tokenstr string) (jwt.MapClaims, error) {
+	return extractClaims(ctx, keys(), tokenstr)
+}
+
+func extractClaims(ctx context.Context, cache *jwks.Cache, tokenstr string) (jwt.MapClaims, error) {
 	tokenstr = strings.TrimPrefix(tokenstr, "\"")
 	tokenstr = strings.TrimSuffix(tokenstr, "\"")
+	tokenstr = strings.TrimPrefix(tokenstr, "Bearer ")
 	token, err := jwt.ParseWithClaims(tokenstr, jwt.MapClaims{}, func(t *jwt.Token) (interface{}, error) {
-		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+		if _, ok := t.Method.(*jwt.SigningMethodRSA); !ok {
 			return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
 		}
-		return []byte(config.Load().ACCESS_TOKEN), nil
+		kid, _ := t.Header["kid"].(string)
+		return cache.Key(ctx, kid)
 	})
 
 	if err != nil {
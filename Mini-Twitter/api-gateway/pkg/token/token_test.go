@@ -0,0 +1,140 @@
+package token
+
+import (
+	"common/jwks"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+)
+
+// jwk is a key as auth-service publishes it.
+type jwk struct {
+	Kty string `json:"kty"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+type jwksServer struct {
+	*httptest.Server
+	keys    map[string]*rsa.PrivateKey
+	fetches atomic.Int32
+}
+
+func newJWKSServer(t *testing.T, kids ...string) *jwksServer {
+	t.Helper()
+
+	s := &jwksServer{keys: map[string]*rsa.PrivateKey{}}
+	for _, kid := range kids {
+		s.addKey(t, kid)
+	}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.fetches.Add(1)
+		var set struct {
+			Keys []jwk `json:"keys"`
+		}
+		for kid, key := range s.keys {
+			set.Keys = append(set.Keys, jwk{
+				Kty: "RSA",
+				Alg: "RS256",
+				Kid: kid,
+				N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			})
+		}
+		json.NewEncoder(w).Encode(set)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *jwksServer) addKey(t *testing.T, kid string) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.keys[kid] = key
+}
+
+func (s *jwksServer) sign(t *testing.T, kid string) string {
+	t.Helper()
+
+	tok := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"user_id": "u1",
+		"role":    "user",
+		"exp":     time.Now().Add(time.Hour).Unix(),
+	})
+	tok.Header["kid"] = kid
+	signed, err := tok.SignedString(s.keys[kid])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func TestExtractClaimsJWKS(t *testing.T) {
+	srv := newJWKSServer(t, "k1")
+	cache := jwks.New(srv.URL)
+
+	claims, err := extractClaims(context.Background(), cache, srv.sign(t, "k1"))
+	if err != nil {
+		t.Fatalf("valid token rejected: %v", err)
+	}
+	if claims["user_id"] != "u1" {
+		t.Fatalf("unexpected claims: %v", claims)
+	}
+
+	// Cached keys are reused.
+	if _, err := extractClaims(context.Background(), cache, srv.sign(t, "k1")); err != nil {
+		t.Fatal(err)
+	}
+	if n := srv.fetches.Load(); n != 1 {
+		t.Fatalf("jwks fetched %d times, want 1", n)
+	}
+}
+
+func TestExtractClaimsRejects(t *testing.T) {
+	srv := newJWKSServer(t, "k1")
+	cache := jwks.New(srv.URL)
+
+	other := newJWKSServer(t, "k1")
+	if _, err := extractClaims(context.Background(), cache, other.sign(t, "k1")); err == nil {
+		t.Fatal("token signed with a foreign key accepted")
+	}
+
+	hmac := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"user_id": "u1"})
+	hmac.Header["kid"] = "k1"
+	signed, _ := hmac.SignedString([]byte("secret"))
+	if _, err := extractClaims(context.Background(), cache, signed); err == nil {
+		t.Fatal("HMAC token accepted")
+	}
+
+	if _, err := extractClaims(context.Background(), cache, srv.sign(t, "k1")); err != nil {
+		t.Fatal(err)
+	}
+	srv.fetches.Store(0)
+	// Unknown kids do not refetch more often than minRefresh.
+	for i := 0; i < 3; i++ {
+		tok := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{"user_id": "u1"})
+		tok.Header["kid"] = "missing"
+		signed, _ := tok.SignedString(srv.keys["k1"])
+		if _, err := extractClaims(context.Background(), cache, signed); err == nil {
+			t.Fatal("token with unknown kid accepted")
+		}
+	}
+	if n := srv.fetches.Load(); n != 0 {
+		t.Fatalf("unknown kid caused %d refetches", n)
+	}
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"apigateway/genproto/event"
+	"common/tracing"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/protobuf/proto"
+)
+
+// resultsQueue is where twitt-service reports the outcome of every command.
+const resultsQueue = "tweet.results"
+
+// commandTTL is how long the status of a command can be looked up.
+const commandTTL = 24 * time.Hour
+
+var ErrCommandNotFound = errors.New("command not found")
+
+// Command is the status of an asynchronous write, as tracked in Redis.
+type Command struct {
+	ID          string          `json:"id"`
+	Type        string          `json:"type"`
+	Status      string          `json:"status"`
+	Error       string          `json:"error,omitempty"`
+	ResourceID  string          `json:"resource_id,omitempty"`
+	Resource    json.RawMessage `json:"resource,omitempty" swaggertype:"object"`
+	CreatedAt   string          `json:"created_at"`
+	CompletedAt string          `json:"completed_at,omitempty"`
+	UserID      string          `json:"-"`
+}
+
+type CommandStore struct {
+	rdb *redis.Client
+}
+
+func NewCommandStore(rdb *redis.Client) *CommandStore {
+	return &CommandStore{rdb: rdb}
+}
+
+func commandKey(id string) string {
+	return "command:" + id
+}
+
+// Pending records a command that has just been published. The status is only
+// set if no result has arrived yet, so a fast consumer cannot be overwritten.
+func (s *CommandStore) Pending(ctx context.Context, id, typ, userID string) error {
+	key := commandKey(id)
+
+	_, err := s.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSetNX(ctx, key, "status", strings.ToLower(event.Status_PENDING.String()))
+		pipe.HSet(ctx, key, "id", id, "type", typ, "user_id", userID, "created_at", time.Now().UTC().Format(time.RFC3339Nano))
+		pipe.Expire(ctx, key, commandTTL)
+		return nil
+	})
+	return err
+}
+
+// Complete stores the outcome reported by twitt-service.
+func (s *CommandStore) Complete(ctx context.Context, res *event.Result) error {
+	key := commandKey(res.CommandId)
+
+	_, err := s.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, key,
+			"id", res.CommandId,
+			"type", res.Type.String(),
+			"status", strings.ToLower(res.Status.String()),
+			"error", res.Error,
+			"resource_id", res.ResourceId,
+			"resource", res.Resource,
+			"completed_at", res.CompletedAt,
+		)
+		pipe.Expire(ctx, key, commandTTL)
+		return nil
+	})
+	return err
+}
+
+func (s *CommandStore) Get(ctx context.Context, id string) (*Command, error) {
+	fields, err := s.rdb.HGetAll(ctx, commandKey(id)).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) == 0 {
+		return nil, ErrCommandNotFound
+	}
+
+	cmd := &Command{
+		ID:          id,
+		Type:        fields["type"],
+		Status:      fields["status"],
+		Error:       fields["error"],
+		ResourceID:  fields["resource_id"],
+		CreatedAt:   fields["created_at"],
+		CompletedAt: fields["completed_at"],
+		UserID:      fields["user_id"],
+	}
+	if fields["resource"] != "" {
+		cmd.Resource = json.RawMessage(fields["resource"])
+	}
+	return cmd, nil
+}
+
+// ConsumeResults copies every command result published by twitt-service into
+// the store until ctx is done or the channel closes.
+func ConsumeResults(ctx context.Context, channel *amqp.Channel, store *CommandStore, logger *slog.Logger) error {
+	q, err := channel.QueueDeclare(resultsQueue, true, false, false, false, nil)
+	if err != nil {
+		logger.Error("Failed to declare queue: "+resultsQueue, "error", err)
+		return err
+	}
+
+	messages, err := channel.Consume(q.Name, "", false, false, false, false, nil)
+	if err != nil {
+		logger.Error("Failed to consume messages from queue: "+resultsQueue, "error", err)
+		return err
+	}
+
+	for {
+		select {
+		case val, ok := <-messages:
+			if !ok {
+				logger.Info("Message channel closed", "consumer", resultsQueue)
+				return nil
+			}
+
+			msgCtx, span := tracing.StartConsume(ctx, resultsQueue, val)
+			var res event.Result
+			if err := proto.Unmarshal(val.Body, &res); err != nil {
+				logger.Error("Failed to unmarshal command result", "error", err)
+				tracing.End(span, err)
+				val.Nack(false, false)
+				continue
+			}
+
+			if err := store.Complete(msgCtx, &res); err != nil {
+				logger.Error("Failed to store command result", "command_id", res.CommandId, "error", err)
+				tracing.End(span, err)
+				val.Nack(false, true)
+				continue
+			}
+
+			tracing.End(span, nil)
+			val.Ack(false)
+
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// Probe reports whether a dependency of the gateway can be used.
+type Probe func(ctx context.Context) error
+
+// GRPCProbe asks the health service behind conn whether it is serving.
+func GRPCProbe(conn *grpc.ClientConn) Probe {
+	client := healthpb.NewHealthClient(conn)
+	return func(ctx context.Context) error {
+		res, err := client.Check(ctx, &healthpb.HealthCheckRequest{})
+		if err != nil {
+			return err
+		}
+		if res.Status != healthpb.HealthCheckResponse_SERVING {
+			return fmt.Errorf("%s is %s", conn.Target(), res.Status)
+		}
+		return nil
+	}
+}
+
+// RedisProbe pings rdb.
+func RedisProbe(rdb *redis.Client) Probe {
+	return func(ctx context.Context) error {
+		return rdb.Ping(ctx).Err()
+	}
+}
+
+// AMQPProbe checks that conn to RabbitMQ is still open.
+func AMQPProbe(conn *amqp.Connection) Probe {
+	return func(ctx context.Context) error {
+		if conn.IsClosed() {
+			return amqp.ErrClosed
+		}
+		return nil
+	}
+}
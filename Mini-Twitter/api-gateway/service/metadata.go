@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// The metadata keys the services read the caller and request id from.
+const (
+	authorizationHeader = "authorization"
+	requestIDHeader     = "x-request-id"
+)
+
+type accessTokenKey struct{}
+
+type requestIDKey struct{}
+
+// WithAccessToken makes every gRPC call made with ctx carry the caller's
+// access token, so the services know whom the call is for.
+func WithAccessToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, accessTokenKey{}, token)
+}
+
+// WithRequestID makes every gRPC call made with ctx carry the request id, so
+// the logs of the services can be matched with the gateway's.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request id ctx carries.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// forwardMetadata puts the access token and request id of ctx in the
+// outgoing metadata of a call.
+func forwardMetadata(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	if token, ok := ctx.Value(accessTokenKey{}).(string); ok && token != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, authorizationHeader, token)
+	}
+	if id := RequestID(ctx); id != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, requestIDHeader, id)
+	}
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"apigateway/pkg/config"
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// takeToken refills every bucket in KEYS for the time since it was last
+// used and takes one token from each, but only when all of them have one, so
+// a request turned away by one bucket does not drain the others. ARGV holds
+// the size and refill period in milliseconds of each bucket. The clock of
+// Redis is used, so every gateway instance sees the same time.
+//
+// It returns whether the request is allowed, then for every bucket its
+// size, the tokens left, and the milliseconds until a token is available
+// and until the bucket is full again.
+var takeToken = redis.NewScript(`
+local now = redis.call('TIME')
+now = tonumber(now[1]) * 1000 + math.floor(tonumber(now[2]) / 1000)
+
+local tokens = {}
+local allowed = 1
+for i, key in ipairs(KEYS) do
+	local size = tonumber(ARGV[2 * i - 1])
+	local rate = size / tonumber(ARGV[2 * i])
+	local bucket = redis.call('HMGET', key, 'tokens', 'ts')
+	local left = tonumber(bucket[1])
+	if left == nil then
+		left = size
+	else
+		left = math.min(size, left + math.max(0, now - tonumber(bucket[2])) * rate)
+	end
+	tokens[i] = left
+	if left < 1 then
+		allowed = 0
+	end
+end
+
+local res = {allowed}
+for i, key in ipairs(KEYS) do
+	local size = tonumber(ARGV[2 * i - 1])
+	local period = tonumber(ARGV[2 * i])
+	local rate = size / period
+	local left = tokens[i]
+	if allowed == 1 then
+		left = left - 1
+	end
+	redis.call('HSET', key, 'tokens', tostring(left), 'ts', now)
+	redis.call('PEXPIRE', key, period)
+
+	local retry = 0
+	if left < 1 then
+		retry = math.ceil((1 - left) / rate)
+	end
+	table.insert(res, size)
+	table.insert(res, math.floor(left))
+	table.insert(res, retry)
+	table.insert(res, math.ceil((size - left) / rate))
+end
+return res
+`)
+
+// Bucket is a token bucket kept in Redis under Key.
+type Bucket struct {
+	Key   string
+	Limit config.RateLimit
+}
+
+// Decision is the outcome of RateLimiter.Take, described by the bucket that
+// is closest to running out.
+type Decision struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// RetryAfter is how long until the request would be allowed, zero when
+	// it is.
+	RetryAfter time.Duration
+	// Reset is how long until the bucket is full again.
+	Reset time.Duration
+}
+
+// RateLimiter throttles callers with token buckets shared by every gateway
+// instance through Redis.
+type RateLimiter struct {
+	rdb *redis.Client
+}
+
+func NewRateLimiter(rdb *redis.Client) *RateLimiter {
+	return &RateLimiter{rdb: rdb}
+}
+
+// Take takes a token from every bucket if all of them have one.
+func (l *RateLimiter) Take(ctx context.Context, buckets ...Bucket) (Decision, error) {
+	keys := make([]string, len(buckets))
+	args := make([]any, 0, 2*len(buckets))
+	for i, b := range buckets {
+		keys[i] = "ratelimit:" + b.Key
+		args = append(args, b.Limit.Requests, b.Limit.Per.Milliseconds())
+	}
+
+	res, err := takeToken.Run(ctx, l.rdb, keys, args...).Int64Slice()
+	if err != nil {
+		return Decision{}, err
+	}
+
+	d := Decision{Allowed: res[0] == 1}
+	for i := range buckets {
+		r := res[1+4*i : 5+4*i]
+		b := Decision{
+			Allowed:    d.Allowed,
+			Limit:      int(r[0]),
+			Remaining:  int(r[1]),
+			RetryAfter: time.Duration(r[2]) * time.Millisecond,
+			Reset:      time.Duration(r[3]) * time.Millisecond,
+		}
+		if i == 0 || b.RetryAfter > d.RetryAfter || (b.RetryAfter == d.RetryAfter && b.Remaining < d.Remaining) {
+			d = b
+		}
+	}
+	return d, nil
+}
@@ -1,11 +1,42 @@
 package service
 
 import (
+	"apigateway/genproto/event"
+	pbb "apigateway/genproto/tweet"
+	"common/tracing"
+	"context"
 	"errors"
+	"github.com/google/uuid"
 	amqp "github.com/rabbitmq/amqp091-go"
+	"google.golang.org/protobuf/proto"
 	"log/slog"
+	"time"
 )
 
+// eventVersion is the payload schema version published for every event type.
+const eventVersion = 1
+
+type idempotencyKey struct{}
+
+// WithIdempotencyKey makes every command published with ctx carry key, so
+// twitt-service applies it at most once.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKey{}, key)
+}
+
+type actorKey struct{}
+
+type actor struct {
+	userID string
+	role   string
+}
+
+// WithActor makes every command published with ctx name the user as its
+// actor, so twitt-service can check they may run it.
+func WithActor(ctx context.Context, userID, role string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor{userID: userID, role: role})
+}
+
 type MsgBroker struct {
 	channel *amqp.Channel
 	logger  *slog.Logger
@@ -22,45 +53,94 @@ func NewMsgBroker(channel *amqp.Channel, logger *slog.Logger) *MsgBroker {
 	}
 }
 
-func (b *MsgBroker) PostComment(body []byte) error {
+func (b *MsgBroker) PostComment(ctx context.Context, in *pbb.Comment) (string, error) {
 	b.logger.Info("Publishing PostComment message")
-	return b.publishMessage("PostComment", body)
+	return b.publishEvent(ctx, event.Type_POST_COMMENT, in)
 }
 
-func (b *MsgBroker) UpdateComment(body []byte) error {
+func (b *MsgBroker) UpdateComment(ctx context.Context, in *pbb.UpdateAComment) (string, error) {
 	b.logger.Info("Publishing UpdateComment message")
-	return b.publishMessage("UpdateComment", body)
+	return b.publishEvent(ctx, event.Type_UPDATE_COMMENT, in)
 }
 
-func (b *MsgBroker) AddLike(body []byte) error {
+func (b *MsgBroker) AddLike(ctx context.Context, in *pbb.LikeReq) (string, error) {
 	b.logger.Info("Publishing AddLike message")
-	return b.publishMessage("AddLike", body)
+	return b.publishEvent(ctx, event.Type_ADD_LIKE, in)
 }
 
-func (b *MsgBroker) PostTweet(body []byte) error {
+func (b *MsgBroker) PostTweet(ctx context.Context, in *pbb.Tweet) (string, error) {
 	b.logger.Info("Publishing PostTweet message")
-	return b.publishMessage("PostTweet", body)
+	return b.publishEvent(ctx, event.Type_POST_TWEET, in)
 }
 
-func (b *MsgBroker) UpdateTweet(body []byte) error {
+func (b *MsgBroker) UpdateTweet(ctx context.Context, in *pbb.UpdateATweet) (string, error) {
 	b.logger.Info("Publishing UpdateTweet message")
-	return b.publishMessage("UpdateTweet", body)
+	return b.publishEvent(ctx, event.Type_UPDATE_TWEET, in)
+}
+
+// publishEvent wraps payload in an event.Envelope and publishes it to the
+// queue owned by typ. The envelope id is returned as the command id.
+func (b *MsgBroker) publishEvent(ctx context.Context, typ event.Type, payload proto.Message) (string, error) {
+	data, err := proto.Marshal(payload)
+	if err != nil {
+		b.logger.Error("Failed to marshal payload", "type", typ.String(), "error", err.Error())
+		return "", err
+	}
+
+	id := uuid.NewString()
+	key := id
+	if k, ok := ctx.Value(idempotencyKey{}).(string); ok && k != "" {
+		key = typ.String() + ":" + k
+	}
+
+	a, _ := ctx.Value(actorKey{}).(actor)
+
+	env := &event.Envelope{
+		Id:             id,
+		IdempotencyKey: key,
+		Type:           typ,
+		Version:        eventVersion,
+		OccurredAt:     time.Now().UTC().Format(time.RFC3339Nano),
+		Encoding:       event.Encoding_PROTOBUF,
+		Payload:        data,
+		ActorId:        a.userID,
+		ActorRole:      a.role,
+	}
+
+	body, err := proto.Marshal(env)
+	if err != nil {
+		b.logger.Error("Failed to marshal envelope", "type", typ.String(), "error", err.Error())
+		return "", err
+	}
+
+	if err := b.publishMessage(ctx, typ.String(), env.Id, body); err != nil {
+		return "", err
+	}
+	return env.Id, nil
 }
 
-func (b *MsgBroker) publishMessage(queueName string, body []byte) error {
+func (b *MsgBroker) publishMessage(ctx context.Context, queueName, messageID string, body []byte) (err error) {
 	if b.channel == nil {
 		b.logger.Error("Failed to publish message: channel is nil", "queue", queueName)
 		return errors.New("failed to publish message: channel is nil") // Creating a more descriptive error
 	}
 
-	err := b.channel.Publish(
+	ctx, span, headers := tracing.StartPublish(ctx, "", queueName, nil)
+	defer func() { tracing.End(span, err) }()
+
+	err = b.channel.PublishWithContext(
+		ctx,
 		"",        // exchange, keeping it blank if default exchange is intended
 		queueName, // routing key (queue name)
 		false,     // mandatory
 		false,     // immediate
 		amqp.Publishing{
-			ContentType: "application/json",
-			Body:        body,
+			Headers:      headers,
+			ContentType:  "application/x-protobuf",
+			DeliveryMode: amqp.Persistent,
+			MessageId:    messageID,
+			Type:         queueName,
+			Body:         body,
 		},
 	)
 	if err != nil {
@@ -68,6 +148,6 @@ func (b *MsgBroker) publishMessage(queueName string, body []byte) error {
 		return err
 	}
 
-	b.logger.Info("Message published successfully", "queue", queueName)
+	b.logger.Info("Message published successfully", "queue", queueName, "message_id", messageID)
 	return nil
 }
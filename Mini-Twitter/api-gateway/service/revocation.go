@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/redis/go-redis/v9"
+)
+
+// RevocationList reads the access token denylist that auth-service writes on
+// logout, password change and account deletion. The keys must match the ones
+// in auth-service's storage/redis package.
+type RevocationList struct {
+	rdb *redis.Client
+}
+
+func NewRevocationList(rdb *redis.Client) *RevocationList {
+	return &RevocationList{rdb: rdb}
+}
+
+// IsRevoked reports whether the token was logged out by its jti or its
+// session, or issued before all tokens of its user were revoked.
+func (l *RevocationList) IsRevoked(ctx context.Context, claims jwt.MapClaims) (bool, error) {
+	userID, _ := claims["user_id"].(string)
+	keys := []string{"revoked:user:" + userID}
+	if jti, ok := claims["jti"].(string); ok && jti != "" {
+		keys = append(keys, "revoked:token:"+jti)
+	}
+	if sid, ok := claims["fid"].(string); ok && sid != "" {
+		keys = append(keys, "revoked:session:"+sid)
+	}
+
+	values, err := l.rdb.MGet(ctx, keys...).Result()
+	if err != nil {
+		return false, err
+	}
+
+	for _, v := range values[1:] {
+		if v != nil {
+			return true, nil
+		}
+	}
+
+	cutoff, ok := values[0].(string)
+	if !ok {
+		return false, nil
+	}
+	revokedAt, err := strconv.ParseInt(cutoff, 10, 64)
+	if err != nil {
+		return false, err
+	}
+	return issuedAtMs(claims) < revokedAt, nil
+}
+
+// issuedAtMs returns when the token was issued in Unix milliseconds. Tokens
+// without iat_ms count as issued at the start of their iat second.
+func issuedAtMs(claims jwt.MapClaims) int64 {
+	if ms, ok := claims["iat_ms"].(float64); ok {
+		return int64(ms)
+	}
+	iat, _ := claims["iat"].(float64)
+	return int64(iat) * 1000
+}
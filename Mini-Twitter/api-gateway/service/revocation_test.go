@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/golang-jwt/jwt"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestIsRevokedCutoff(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer rdb.Close()
+	list := NewRevocationList(rdb)
+
+	cutoff := time.Date(2024, 1, 1, 12, 0, 0, 500*int(time.Millisecond), time.UTC)
+	mr.Set("revoked:user:u1", strconv.FormatInt(cutoff.UnixMilli(), 10))
+
+	tests := []struct {
+		name   string
+		claims jwt.MapClaims
+		want   bool
+	}{
+		{"before in the same second", claimsAt(cutoff.Add(-100*time.Millisecond)), true},
+		{"after in the same second", claimsAt(cutoff.Add(100*time.Millisecond)), false},
+		{"without iat_ms in the same second", jwt.MapClaims{"user_id": "u1", "iat": float64(cutoff.Unix())}, true},
+		{"without iat_ms after", jwt.MapClaims{"user_id": "u1", "iat": float64(cutoff.Unix() + 1)}, false},
+		{"without iat", jwt.MapClaims{"user_id": "u1"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := list.IsRevoked(context.Background(), tt.claims)
+			if err != nil {
+				t.Fatalf("IsRevoked: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("IsRevoked = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func claimsAt(at time.Time) jwt.MapClaims {
+	return jwt.MapClaims{
+		"user_id": "u1",
+		"iat":     float64(at.Unix()),
+		"iat_ms":  float64(at.UnixMilli()),
+	}
+}
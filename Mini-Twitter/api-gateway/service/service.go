@@ -4,6 +4,8 @@ import (
 	pbb "apigateway/genproto/tweet"
 	pb "apigateway/genproto/user"
 	"apigateway/pkg/config"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc/filters"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 )
@@ -11,11 +13,16 @@ import (
 type Service interface {
 	UserService() pb.UserServiceClient
 	TweetService() pbb.TweetServiceClient
+	DeadLetterService() pbb.DeadLetterServiceClient
+	// Probes check the services behind the clients, keyed by service name.
+	Probes() map[string]Probe
 }
 
 type service struct {
 	userClient  pb.UserServiceClient
 	tweetClient pbb.TweetServiceClient
+	deadLetters pbb.DeadLetterServiceClient
+	probes      map[string]Probe
 }
 
 func (s *service) UserService() pb.UserServiceClient {
@@ -26,18 +33,34 @@ func (s *service) TweetService() pbb.TweetServiceClient {
 	return s.tweetClient
 }
 
+func (s *service) DeadLetterService() pbb.DeadLetterServiceClient {
+	return s.deadLetters
+}
+
+func (s *service) Probes() map[string]Probe {
+	return s.probes
+}
+
 func NewService(cfg *config.Config) (Service, error) {
-	userConn, err := grpc.NewClient("localhost:50050", grpc.WithTransportCredentials(insecure.NewCredentials()))
+	// Calls carry the trace context of the request in their metadata; the
+	// readiness checks are left out of the traces.
+	traced := grpc.WithStatsHandler(otelgrpc.NewClientHandler(otelgrpc.WithFilter(filters.Not(filters.HealthCheck()))))
+	userConn, err := grpc.NewClient("localhost:50050", grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithUnaryInterceptor(forwardMetadata), traced)
 	if err != nil {
 		return nil, err
 	}
-	twitter, err := grpc.NewClient("localhost:8088", grpc.WithTransportCredentials(insecure.NewCredentials()))
+	twitter, err := grpc.NewClient("localhost:8088", grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithUnaryInterceptor(forwardMetadata), traced)
 	if err != nil {
 		return nil, err
 	}
 	return &service{
 		userClient:  pb.NewUserServiceClient(userConn),
 		tweetClient: pbb.NewTweetServiceClient(twitter),
+		deadLetters: pbb.NewDeadLetterServiceClient(twitter),
+		probes: map[string]Probe{
+			"auth-service":  GRPCProbe(userConn),
+			"twitt-service": GRPCProbe(twitter),
+		},
 	}, nil
 
 }
@@ -2,71 +2,116 @@ package email
 
 import (
 	"bytes"
+	"crypto/rand"
+	"embed"
 	"fmt"
 	"html/template"
-	"log"
-	"math/rand"
-	"net/smtp"
-	"strconv"
-	"time"
+	"math/big"
+	"strings"
 )
 
-func Email(email string) (string, error) {
+//go:embed templates/*.html
+var templateFiles embed.FS
 
-	source := rand.NewSource(time.Now().UnixNano())
-	myRand := rand.New(source)
+// Sender renders the mails of the service from templates/ and queues them.
+// Every template defines a "subject" block next to its HTML body.
+type Sender struct {
+	queue     *Queue
+	templates map[string]*template.Template
+}
+
+func NewSender(queue *Queue) (*Sender, error) {
+	s := &Sender{
+		queue:     queue,
+		templates: make(map[string]*template.Template),
+	}
 
-	// Generate a random 6-digit number (100000 to 999999)
-	randomNumber := myRand.Intn(900000) + 100000
-	code := strconv.Itoa(randomNumber)
+	for _, name := range []string{"verification", "reset", "locked", "notification"} {
+		t, err := template.ParseFS(templateFiles, "templates/"+name+".html")
+		if err != nil {
+			return nil, err
+		}
+		s.templates[name] = t
+	}
 
-	err := SendCode(email, code)
+	return s, nil
+}
 
+// NewCode returns a random 6-digit verification code.
+func NewCode() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(900000))
 	if err != nil {
 		return "", err
 	}
+	return fmt.Sprintf("%d", n.Int64()+100000), nil
+}
 
-	return code, nil
+func (s *Sender) SendVerificationCode(to, code string) error {
+	return s.send(to, "verification", struct {
+		Code string
+	}{
+		Code: code,
+	})
 }
 
-func SendCode(email string, code string) error {
-	// sender data
-	from := "ozodjon16062005@gmail.com"
-	password := "gbto uogq imku ayud"
+// SendPasswordReset emails the one-time link that lets the user choose a new
+// password.
+func (s *Sender) SendPasswordReset(to, link string) error {
+	return s.send(to, "reset", struct {
+		Link string
+	}{
+		Link: link,
+	})
+}
 
-	// Receiver email address
-	to := []string{
-		email,
-	}
+// SendAccountLocked tells the user their account was locked by failed logins
+// and emails the link that unlocks it.
+func (s *Sender) SendAccountLocked(to, link string) error {
+	return s.send(to, "locked", struct {
+		Link string
+	}{
+		Link: link,
+	})
+}
 
-	// smtp server configuration.
-	smtpHost := "smtp.gmail.com"
-	smtpPort := "587"
+func (s *Sender) SendNotification(to, title, text string) error {
+	return s.send(to, "notification", struct {
+		Title string
+		Text  string
+	}{
+		Title: title,
+		Text:  text,
+	})
+}
 
-	// Authentication.
-	auth := smtp.PlainAuth("", from, password, smtpHost)
+// Ready reports ErrMailNotConfigured when no mail driver is set, so a
+// handler can refuse before promising a mail it cannot send.
+func (s *Sender) Ready() error {
+	return s.queue.Ready()
+}
 
-	t, err := template.ParseFiles("api/email/template.html")
+func (s *Sender) send(to, name string, data any) error {
+	msg, err := s.render(to, name, data)
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
+	return s.queue.Enqueue(msg)
+}
 
-	var body bytes.Buffer
-
-	mimeHeaders := "MIME-version: 1.0;\nContent-Type: text/html; charset=\"UTF-8\";\n\n"
-	body.Write([]byte(fmt.Sprintf("Subject: Your verification code \n%s\n\n", mimeHeaders)))
-	t.Execute(&body, struct {
-		Passwd string
-	}{
-
-		Passwd: code,
-	})
+func (s *Sender) render(to, name string, data any) (Message, error) {
+	t := s.templates[name]
 
-	// Sending email.
-	err = smtp.SendMail(smtpHost+":"+smtpPort, auth, from, to, body.Bytes())
-	if err != nil {
-		return err
+	var subject, body bytes.Buffer
+	if err := t.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return Message{}, err
+	}
+	if err := t.Execute(&body, data); err != nil {
+		return Message{}, err
 	}
-	fmt.Println("Email sent to:", email)
-	return nil
+
+	return Message{
+		To:      to,
+		Subject: strings.TrimSpace(subject.String()),
+		HTML:    body.String(),
+	}, nil
 }
@@ -0,0 +1,290 @@
+package email
+
+import (
+	"auth-service/pkg/config"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func testLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func newTestSender(t *testing.T, mailer Mailer) (*Sender, *Queue) {
+	queue := NewQueue(mailer, testLogger(), 10)
+	queue.baseDelay = time.Millisecond
+	queue.Start(context.Background(), 1)
+
+	sender, err := NewSender(queue)
+	if err != nil {
+		t.Fatalf("NewSender: %v", err)
+	}
+	return sender, queue
+}
+
+func TestSenderRendersTemplates(t *testing.T) {
+	mailer := NewMemoryMailer()
+	sender, queue := newTestSender(t, mailer)
+
+	if err := sender.SendVerificationCode("a@example.com", "123456"); err != nil {
+		t.Fatalf("SendVerificationCode: %v", err)
+	}
+	if err := sender.SendPasswordReset("b@example.com", "http://localhost/reset?token=abc"); err != nil {
+		t.Fatalf("SendPasswordReset: %v", err)
+	}
+	if err := sender.SendNotification("c@example.com", "Password changed", "Your password was changed."); err != nil {
+		t.Fatalf("SendNotification: %v", err)
+	}
+	if err := sender.SendAccountLocked("d@example.com", "http://localhost/unlock?token=def"); err != nil {
+		t.Fatalf("SendAccountLocked: %v", err)
+	}
+	queue.Close()
+
+	messages := mailer.Messages()
+	if len(messages) != 4 {
+		t.Fatalf("got %d messages, want 4", len(messages))
+	}
+
+	tests := []struct {
+		to, subject, contains string
+	}{
+		{"a@example.com", "Your verification code", "123456"},
+		{"b@example.com", "Reset your password", "http://localhost/reset?token=abc"},
+		{"c@example.com", "Password changed", "Your password was changed."},
+		{"d@example.com", "Your account has been locked", "http://localhost/unlock?token=def"},
+	}
+	for i, tt := range tests {
+		msg := messages[i]
+		if msg.To != tt.to {
+			t.Errorf("message %d: To = %q, want %q", i, msg.To, tt.to)
+		}
+		if msg.Subject != tt.subject {
+			t.Errorf("message %d: Subject = %q, want %q", i, msg.Subject, tt.subject)
+		}
+		if !strings.Contains(msg.HTML, tt.contains) {
+			t.Errorf("message %d: HTML does not contain %q", i, tt.contains)
+		}
+		if strings.Contains(msg.HTML, "{{") {
+			t.Errorf("message %d: HTML has unrendered template actions", i)
+		}
+	}
+}
+
+func TestSenderEscapesHTML(t *testing.T) {
+	mailer := NewMemoryMailer()
+	sender, queue := newTestSender(t, mailer)
+
+	if err := sender.SendNotification("a@example.com", "Hi", "<script>alert(1)</script>"); err != nil {
+		t.Fatalf("SendNotification: %v", err)
+	}
+	queue.Close()
+
+	if html := mailer.Messages()[0].HTML; strings.Contains(html, "<script>") {
+		t.Errorf("notification text was not escaped: %s", html)
+	}
+}
+
+// flakyMailer fails its first failures sends, then delivers.
+type flakyMailer struct {
+	MemoryMailer
+	mu       sync.Mutex
+	failures int
+	calls    int
+}
+
+func (m *flakyMailer) Send(ctx context.Context, msg Message) error {
+	m.mu.Lock()
+	m.calls++
+	fail := m.calls <= m.failures
+	m.mu.Unlock()
+
+	if fail {
+		return errors.New("smtp unavailable")
+	}
+	return m.MemoryMailer.Send(ctx, msg)
+}
+
+func TestQueueRetriesFailedSends(t *testing.T) {
+	mailer := &flakyMailer{failures: 2}
+	sender, queue := newTestSender(t, mailer)
+
+	if err := sender.SendVerificationCode("a@example.com", "123456"); err != nil {
+		t.Fatalf("SendVerificationCode: %v", err)
+	}
+	queue.Close()
+
+	if mailer.calls != 3 {
+		t.Errorf("calls = %d, want 3", mailer.calls)
+	}
+	if got := len(mailer.Messages()); got != 1 {
+		t.Errorf("delivered %d messages, want 1", got)
+	}
+}
+
+func TestQueueGivesUp(t *testing.T) {
+	mailer := &flakyMailer{failures: 100}
+	sender, queue := newTestSender(t, mailer)
+
+	if err := sender.SendVerificationCode("a@example.com", "123456"); err != nil {
+		t.Fatalf("SendVerificationCode: %v", err)
+	}
+	queue.Close()
+
+	if mailer.calls != defaultMaxAttempts {
+		t.Errorf("calls = %d, want %d", mailer.calls, defaultMaxAttempts)
+	}
+	if got := len(mailer.Messages()); got != 0 {
+		t.Errorf("delivered %d messages, want 0", got)
+	}
+}
+
+func TestQueueFull(t *testing.T) {
+	queue := NewQueue(NewMemoryMailer(), testLogger(), 1)
+
+	if err := queue.Enqueue(Message{To: "a@example.com"}); err != nil {
+		t.Fatalf("first Enqueue: %v", err)
+	}
+	if err := queue.Enqueue(Message{To: "b@example.com"}); !errors.Is(err, ErrQueueFull) {
+		t.Errorf("second Enqueue = %v, want ErrQueueFull", err)
+	}
+}
+
+func TestQueueClosed(t *testing.T) {
+	queue := NewQueue(NewMemoryMailer(), testLogger(), 1)
+	queue.Close()
+
+	if err := queue.Enqueue(Message{To: "a@example.com"}); !errors.Is(err, ErrQueueClosed) {
+		t.Errorf("Enqueue after Close = %v, want ErrQueueClosed", err)
+	}
+	// Closing twice is harmless.
+	queue.Close()
+}
+
+func TestQueueRefusesUnconfigured(t *testing.T) {
+	sender, queue := newTestSender(t, UnconfiguredMailer{})
+	defer queue.Close()
+
+	if err := sender.Ready(); !errors.Is(err, ErrMailNotConfigured) {
+		t.Errorf("Ready = %v, want ErrMailNotConfigured", err)
+	}
+	if err := sender.SendVerificationCode("a@example.com", "123456"); !errors.Is(err, ErrMailNotConfigured) {
+		t.Errorf("SendVerificationCode = %v, want ErrMailNotConfigured", err)
+	}
+}
+
+func TestLogMailerOmitsBody(t *testing.T) {
+	var out strings.Builder
+	mailer := NewLogMailer(slog.New(slog.NewTextHandler(&out, nil)))
+
+	err := mailer.Send(context.Background(), Message{To: "a@example.com", Subject: "Reset your password", HTML: "token=secret"})
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if !strings.Contains(out.String(), "a@example.com") || !strings.Contains(out.String(), "Reset your password") {
+		t.Errorf("log lacks the recipient or subject: %s", out.String())
+	}
+	if strings.Contains(out.String(), "secret") {
+		t.Errorf("log contains the body: %s", out.String())
+	}
+}
+
+func TestSMTPMailerHonoursContext(t *testing.T) {
+	// The server accepts connections but never greets.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	host, port, _ := net.SplitHostPort(listener.Addr().String())
+	mailer := NewSMTPMailer(config.Config{SMTP_HOST: host, SMTP_PORT: port, MAIL_FROM: "no-reply@example.com"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err = mailer.Send(ctx, Message{To: "a@example.com", Subject: "Hi"})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Send = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Send took %v after its deadline", elapsed)
+	}
+}
+
+func TestQueueBackoff(t *testing.T) {
+	queue := NewQueue(NewMemoryMailer(), testLogger(), 1)
+
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{3, 4 * time.Second},
+		{10, time.Minute},
+	}
+	for _, tt := range tests {
+		if got := queue.backoff(tt.attempt); got != tt.want {
+			t.Errorf("backoff(%d) = %v, want %v", tt.attempt, got, tt.want)
+		}
+	}
+}
+
+func TestNewMailer(t *testing.T) {
+	tests := []struct {
+		driver  string
+		want    string
+		wantErr bool
+	}{
+		{driver: "smtp", want: "*email.SMTPMailer"},
+		{driver: "log", want: "*email.LogMailer"},
+		{driver: "memory", want: "*email.MemoryMailer"},
+		{driver: "none", want: "email.UnconfiguredMailer"},
+		{driver: "pigeon", wantErr: true},
+	}
+	for _, tt := range tests {
+		mailer, err := New(config.Config{MAIL_DRIVER: tt.driver}, testLogger())
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("New(%q) succeeded, want error", tt.driver)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("New(%q): %v", tt.driver, err)
+			continue
+		}
+		if got := fmt.Sprintf("%T", mailer); got != tt.want {
+			t.Errorf("New(%q) = %s, want %s", tt.driver, got, tt.want)
+		}
+	}
+}
+
+func TestNewCode(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		code, err := NewCode()
+		if err != nil {
+			t.Fatalf("NewCode: %v", err)
+		}
+		if len(code) != 6 {
+			t.Fatalf("NewCode() = %q, want 6 digits", code)
+		}
+	}
+}
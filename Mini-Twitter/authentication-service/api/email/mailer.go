@@ -0,0 +1,193 @@
+package email
+
+import (
+	"auth-service/pkg/config"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log/slog"
+	"mime"
+	"net"
+	"net/smtp"
+	"sync"
+	"time"
+)
+
+// ErrMailNotConfigured is returned for every message when no mail driver is
+// set.
+var ErrMailNotConfigured = errors.New("mail is not configured, set MAIL_DRIVER")
+
+// Message is a rendered email.
+type Message struct {
+	To      string
+	Subject string
+	HTML    string
+}
+
+// Mailer delivers a message. Implementations are chosen by MAIL_DRIVER.
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// New returns the mailer configured by MAIL_DRIVER: smtp, log or memory. With
+// none, nothing can be queued, so a deployment that forgot to configure mail
+// does not look like it is sending.
+func New(cfg config.Config, logger *slog.Logger) (Mailer, error) {
+	switch cfg.MAIL_DRIVER {
+	case "none":
+		logger.Warn("No mail driver configured, emails will not be sent")
+		return UnconfiguredMailer{}, nil
+	case "smtp":
+		return NewSMTPMailer(cfg), nil
+	case "log":
+		return NewLogMailer(logger), nil
+	case "memory":
+		return NewMemoryMailer(), nil
+	default:
+		return nil, fmt.Errorf("unknown mail driver %q", cfg.MAIL_DRIVER)
+	}
+}
+
+// SMTPMailer sends through an SMTP server. Without a username it does not
+// authenticate, which is what local sinks like Mailpit expect.
+type SMTPMailer struct {
+	addr     string
+	host     string
+	username string
+	password string
+	from     string
+}
+
+func NewSMTPMailer(cfg config.Config) *SMTPMailer {
+	return &SMTPMailer{
+		addr:     net.JoinHostPort(cfg.SMTP_HOST, cfg.SMTP_PORT),
+		host:     cfg.SMTP_HOST,
+		username: cfg.SMTP_USERNAME,
+		password: cfg.SMTP_PASSWORD,
+		from:     cfg.MAIL_FROM,
+	}
+}
+
+// Send gives up when ctx is done, even halfway through the SMTP exchange.
+func (m *SMTPMailer) Send(ctx context.Context, msg Message) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", m.addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	stop := context.AfterFunc(ctx, func() {
+		conn.SetDeadline(time.Now())
+	})
+	defer stop()
+
+	err = m.send(conn, msg)
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
+
+// send is smtp.SendMail on an open connection.
+func (m *SMTPMailer) send(conn net.Conn, msg Message) error {
+	c, err := smtp.NewClient(conn, m.host)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: m.host}); err != nil {
+			return err
+		}
+	}
+	if m.username != "" {
+		if err := c.Auth(smtp.PlainAuth("", m.username, m.password, m.host)); err != nil {
+			return err
+		}
+	}
+
+	if err := c.Mail(m.from); err != nil {
+		return err
+	}
+	if err := c.Rcpt(msg.To); err != nil {
+		return err
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(m.body(msg)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return c.Quit()
+}
+
+func (m *SMTPMailer) body(msg Message) []byte {
+	var body bytes.Buffer
+	fmt.Fprintf(&body, "From: %s\r\n", m.from)
+	fmt.Fprintf(&body, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&body, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	body.WriteString("MIME-Version: 1.0\r\n")
+	body.WriteString("Content-Type: text/html; charset=\"UTF-8\"\r\n\r\n")
+	body.WriteString(msg.HTML)
+	return body.Bytes()
+}
+
+// LogMailer only logs that a message would have been sent, for running
+// without a mail server. The body is left out, since it holds codes and links
+// that grant access to the account.
+type LogMailer struct {
+	log *slog.Logger
+}
+
+func NewLogMailer(logger *slog.Logger) *LogMailer {
+	return &LogMailer{log: logger}
+}
+
+func (m *LogMailer) Send(ctx context.Context, msg Message) error {
+	m.log.Info("Email not sent, log mail driver", "to", msg.To, "subject", msg.Subject)
+	return nil
+}
+
+// UnconfiguredMailer fails every send with ErrMailNotConfigured.
+type UnconfiguredMailer struct{}
+
+func (UnconfiguredMailer) Send(ctx context.Context, msg Message) error {
+	return ErrMailNotConfigured
+}
+
+// MemoryMailer keeps every message it is given, for tests.
+type MemoryMailer struct {
+	mu       sync.Mutex
+	messages []Message
+}
+
+func NewMemoryMailer() *MemoryMailer {
+	return &MemoryMailer{}
+}
+
+func (m *MemoryMailer) Send(ctx context.Context, msg Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.messages = append(m.messages, msg)
+	return nil
+}
+
+// Messages returns a copy of the messages sent so far.
+func (m *MemoryMailer) Messages() []Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]Message(nil), m.messages...)
+}
@@ -0,0 +1,148 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+var (
+	ErrQueueFull   = errors.New("mail queue is full")
+	ErrQueueClosed = errors.New("mail queue is closed")
+)
+
+const (
+	defaultMaxAttempts = 5
+	defaultBaseDelay   = time.Second
+	defaultMaxDelay    = time.Minute
+	// sendTimeout bounds one attempt at sending a message.
+	sendTimeout = 30 * time.Second
+)
+
+// Queue sends messages in the background, so requests never wait on the mail
+// server, and retries failed sends with exponential backoff.
+type Queue struct {
+	mailer   Mailer
+	log      *slog.Logger
+	messages chan Message
+	wg       sync.WaitGroup
+
+	// mu guards closed, so nothing is sent on messages once it is closed.
+	mu     sync.RWMutex
+	closed bool
+
+	maxAttempts int
+	baseDelay   time.Duration
+	maxDelay    time.Duration
+}
+
+func NewQueue(mailer Mailer, logger *slog.Logger, size int) *Queue {
+	return &Queue{
+		mailer:      mailer,
+		log:         logger,
+		messages:    make(chan Message, size),
+		maxAttempts: defaultMaxAttempts,
+		baseDelay:   defaultBaseDelay,
+		maxDelay:    defaultMaxDelay,
+	}
+}
+
+// Start runs workers until Close is called or ctx is done.
+func (q *Queue) Start(ctx context.Context, workers int) {
+	for i := 0; i < workers; i++ {
+		q.wg.Add(1)
+		go func() {
+			defer q.wg.Done()
+			for {
+				select {
+				case msg, ok := <-q.messages:
+					if !ok {
+						return
+					}
+					q.send(ctx, msg)
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+}
+
+// Enqueue adds msg to the queue without blocking. Without a mail driver it
+// fails with ErrMailNotConfigured, since msg would never be sent.
+func (q *Queue) Enqueue(msg Message) error {
+	if err := q.Ready(); err != nil {
+		return err
+	}
+
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	if q.closed {
+		return ErrQueueClosed
+	}
+
+	select {
+	case q.messages <- msg:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// Ready reports ErrMailNotConfigured when no mail driver is set.
+func (q *Queue) Ready() error {
+	if _, ok := q.mailer.(UnconfiguredMailer); ok {
+		return ErrMailNotConfigured
+	}
+	return nil
+}
+
+// Close stops accepting messages and waits until the queued ones are sent.
+func (q *Queue) Close() {
+	q.mu.Lock()
+	if !q.closed {
+		q.closed = true
+		close(q.messages)
+	}
+	q.mu.Unlock()
+
+	q.wg.Wait()
+}
+
+func (q *Queue) send(ctx context.Context, msg Message) {
+	for attempt := 1; ; attempt++ {
+		sendCtx, cancel := context.WithTimeout(ctx, sendTimeout)
+		err := q.mailer.Send(sendCtx, msg)
+		cancel()
+		if err == nil {
+			return
+		}
+		if attempt >= q.maxAttempts {
+			q.log.Error("Giving up sending email", "to", msg.To, "subject", msg.Subject, "attempts", attempt, "error", err)
+			return
+		}
+
+		delay := q.backoff(attempt)
+		q.log.Warn("Failed to send email, retrying", "to", msg.To, "attempt", attempt, "delay", delay, "error", err)
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// backoff doubles the delay with each attempt, capped at maxDelay.
+func (q *Queue) backoff(attempt int) time.Duration {
+	delay := q.baseDelay
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= q.maxDelay {
+			return q.maxDelay
+		}
+	}
+	return delay
+}
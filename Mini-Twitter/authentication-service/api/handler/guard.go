@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"auth-service/pkg/models"
+	"auth-service/service"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// loginError maps the errors of logins and registration codes, which share
+// the login guard, to a response.
+func (h *authHandler) loginError(c *gin.Context, err error) {
+	var tooMany *service.TooManyAttemptsError
+	switch {
+	case errors.Is(err, service.ErrInvalidCredentials):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrInvalidRegistrationCode):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrAccountLocked):
+		c.JSON(http.StatusLocked, gin.H{"error": err.Error()})
+	case errors.As(err, &tooMany):
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(tooMany.RetryAfter.Seconds()))))
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+	default:
+		h.log.Error("Error occurred while login", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process request"})
+	}
+}
+
+// @Summary Unlock account
+// @Description lifts a lockout caused by failed logins, with the token from the emailed link
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param Unlock body models.UnlockRequest true "unlock token"
+// @Success 200 {object} models.Message
+// @Failure 400 {object} models.Error
+// @Failure 500 {object} models.Error
+// @Router /unlock [post]
+func (h *authHandler) Unlock(c *gin.Context) {
+	var req models.UnlockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Error("Error occurred while binding json", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err := h.srv.Unlock(c, req.Token)
+	if errors.Is(err, service.ErrInvalidUnlockToken) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		h.log.Error("Error occurred while unlocking account", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Message{Message: "account unlocked"})
+}
+
+// @Summary Security events
+// @Description lists the latest lockouts and other security events, newest first. Admin only
+// @Tags Auth
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "max events, up to 100"
+// @Success 200 {object} models.SecurityEvents
+// @Failure 401 {object} models.Error
+// @Failure 403 {object} models.Error
+// @Failure 500 {object} models.Error
+// @Router /security-events [get]
+func (h *authHandler) SecurityEvents(c *gin.Context) {
+	limit, _ := strconv.ParseInt(c.Query("limit"), 10, 64)
+
+	events, err := h.srv.SecurityEvents(c, accessToken(c), limit)
+	if err != nil {
+		h.authError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SecurityEvents{Events: events})
+}
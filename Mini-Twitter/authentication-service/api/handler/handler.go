@@ -3,12 +3,14 @@ package handler
 import (
 	"auth-service/api/email"
 	"auth-service/pkg/models"
+	"auth-service/pkg/token"
 	"auth-service/service"
-	"auth-service/storage/redis"
 	"context"
+	"errors"
 	"github.com/badoux/checkmail"
 	"github.com/gin-gonic/gin"
 	"log/slog"
+	"strings"
 	"time"
 
 	"net/http"
@@ -21,16 +23,38 @@ type AuthHandler interface {
 	LoginEmail(c *gin.Context)
 	LoginUsername(c *gin.Context)
 	AcceptCodeToRegister(c *gin.Context)
+	Refresh(c *gin.Context)
+	Logout(c *gin.Context)
+	LogoutAll(c *gin.Context)
+	ForgotPassword(c *gin.Context)
+	ResetPassword(c *gin.Context)
+
+	EnrollTwoFactor(c *gin.Context)
+	ConfirmTwoFactor(c *gin.Context)
+	LoginTwoFactor(c *gin.Context)
+	ResetTwoFactor(c *gin.Context)
+
+	Unlock(c *gin.Context)
+	SecurityEvents(c *gin.Context)
+
+	Sessions(c *gin.Context)
+	RevokeSession(c *gin.Context)
+
+	OIDCLogin(c *gin.Context)
+	OIDCCallback(c *gin.Context)
+
+	JWKS(c *gin.Context)
 }
 
 type authHandler struct {
-	srv   service.AuthService
-	log   *slog.Logger
-	redis *redis.RedisStorage
+	srv  service.AuthService
+	log  *slog.Logger
+	mail *email.Sender
+	keys *token.KeySet
 }
 
-func NewAuthHandler(log *slog.Logger, sr service.AuthService, redis *redis.RedisStorage) AuthHandler {
-	return &authHandler{log: log, srv: sr, redis: redis}
+func NewAuthHandler(log *slog.Logger, sr service.AuthService, mail *email.Sender, keys *token.KeySet) AuthHandler {
+	return &authHandler{log: log, srv: sr, mail: mail, keys: keys}
 }
 
 // Register godoc
@@ -44,25 +68,31 @@ func NewAuthHandler(log *slog.Logger, sr service.AuthService, redis *redis.Redis
 // @Failure 400 {object} models.Error
 // @Failure 404 {object} models.Error
 // @Failure 500 {object} models.Error
+// @Failure 503 {object} models.Error
 // @Router /register [post]
 func (h *authHandler) Register(c *gin.Context) {
 	var auth models.RegisterRequest
 
 	if err := c.ShouldBindJSON(&auth); err != nil {
-		h.log.Error("Error occurred while binding json", err)
+		h.log.Error("Error occurred while binding json", "error", err)
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 	err := checkmail.ValidateFormat(auth.Email)
 	if err != nil {
-		h.log.Error("Invalid email provided", err)
+		h.log.Error("Invalid email provided", "error", err)
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid email provided"})
 		return
 	}
-	code, err := email.Email(auth.Email)
+	if err := h.mail.Ready(); err != nil {
+		h.log.Error("Cannot send verification code", "error", err)
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Email is not available, try again later"})
+		return
+	}
+	code, err := email.NewCode()
 	if err != nil {
-		h.log.Error("Invalid email provided", "error", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid email provided: " + err.Error()})
+		h.log.Error("Failed to generate verification code", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to register user"})
 		return
 	}
 	req1 := models.RegisterRequest1{
@@ -77,12 +107,19 @@ func (h *authHandler) Register(c *gin.Context) {
 	}
 	req1.Code = code
 
-	err = h.redis.SetRegister(c, req1)
+	err = h.srv.StartRegistration(c, req1)
 	if err != nil {
-		h.log.Error("Failed to register user", err)
+		h.log.Error("Failed to register user", "error", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to register user"})
 		return
 	}
+
+	err = h.mail.SendVerificationCode(auth.Email, code)
+	if err != nil {
+		h.log.Error("Failed to send verification code", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to send verification code"})
+		return
+	}
 	h.log.Info("Successfully saved to redis")
 
 	c.JSON(http.StatusOK, gin.H{"info": "code sent to this email " + req1.Email})
@@ -95,6 +132,7 @@ func (h *authHandler) Register(c *gin.Context) {
 // @Param token body models.AcceptCode true "enough"
 // @Success 200 {object} models.RegisterResponse
 // @Failure 400 {object} models.Error
+// @Failure 429 {object} models.Error
 // @Failure 500 {object} models.Error
 // @Router /accept-code [post]
 func (h *authHandler) AcceptCodeToRegister(c *gin.Context) {
@@ -108,32 +146,9 @@ func (h *authHandler) AcceptCodeToRegister(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c, 10*time.Second)
 	defer cancel()
 
-	register, err := h.redis.GetRegister(ctx, req.Email)
-	if err != nil {
-		h.log.Error("Failed to get register from redis", "error", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get register from redis; " + err.Error()})
-		return
-	}
-
-	if register.Code != req.Code {
-		h.log.Error("Invalid code", "code", req.Code)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid code"})
-		return
-	}
-
-	response, err := h.srv.Register(models.RegisterRequest{
-		FirstName:   register.FirstName,
-		LastName:    register.LastName,
-		Email:       register.Email,
-		Phone:       register.Phone,
-		Username:    register.Username,
-		Nationality: register.Nationality,
-		Bio:         register.Bio,
-		Password:    register.Password,
-	})
+	response, err := h.srv.ConfirmRegistration(ctx, req.Email, req.Code, c.ClientIP())
 	if err != nil {
-		h.log.Error("Failed to register student", "error", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to register student; " + err.Error()})
+		h.loginError(c, err)
 		return
 	}
 
@@ -141,60 +156,111 @@ func (h *authHandler) AcceptCodeToRegister(c *gin.Context) {
 }
 
 // @Summary LoginEmail Users
-// @Description sign in user
+// @Description sign in user. Accounts with two-factor authentication get an mfa_token instead of tokens
 // @Tags Auth
 // @Accept json
 // @Produce json
 // @Param LoginEmail body models.LoginEmailRequest true "register user"
 // @Success 200 {object} models.Tokens
 // @Failure 400 {object} models.Error
-// @Failure 404 {object} models.Error
+// @Failure 401 {object} models.Error
+// @Failure 423 {object} models.Error
+// @Failure 429 {object} models.Error
 // @Failure 500 {object} models.Error
 // @Router /login/email [post]
 func (h *authHandler) LoginEmail(c *gin.Context) {
 	var auth models.LoginEmailRequest
 
 	if err := c.ShouldBindJSON(&auth); err != nil {
-		h.log.Error("Error occurred while binding json", err)
+		h.log.Error("Error occurred while binding json", "error", err)
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	res, err := h.srv.LoginEmail(auth)
+	res, err := h.srv.LoginEmail(c, auth, client(c))
 	if err != nil {
-		h.log.Error("Error occurred while login", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		h.loginError(c, err)
 		return
 	}
 
-	c.SetCookie("access_token", res.AccessToken, 3600, "", "", false, true)
-	c.SetCookie("refresh_token", res.RefreshToken, 3600, "", "", false, true)
+	if res.AccessToken != "" {
+		c.SetCookie("access_token", res.AccessToken, 3600, "", "", false, true)
+		c.SetCookie("refresh_token", res.RefreshToken, 3600, "", "", false, true)
+	}
 
 	c.JSON(http.StatusOK, res)
 }
 
 // @Summary LoginUsername Users
-// @Description sign in user
+// @Description sign in user. Accounts with two-factor authentication get an mfa_token instead of tokens
 // @Tags Auth
 // @Accept json
 // @Produce json
 // @Param LoginUsername body models.LoginUsernameRequest true "register user"
 // @Success 200 {object} models.Tokens
 // @Failure 400 {object} models.Error
-// @Failure 404 {object} models.Error
+// @Failure 401 {object} models.Error
+// @Failure 423 {object} models.Error
+// @Failure 429 {object} models.Error
 // @Failure 500 {object} models.Error
 // @Router /login/username [post]
 func (h *authHandler) LoginUsername(c *gin.Context) {
 	var auth models.LoginUsernameRequest
 	if err := c.ShouldBindJSON(&auth); err != nil {
-		h.log.Error("Error occurred while binding json", err)
+		h.log.Error("Error occurred while binding json", "error", err)
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	res, err := h.srv.LoginUsername(auth)
+	res, err := h.srv.LoginUsername(c, auth, client(c))
 	if err != nil {
-		h.log.Error("Error occurred while login", err)
+		h.loginError(c, err)
+		return
+	}
+
+	if res.AccessToken != "" {
+		c.SetCookie("access_token", res.AccessToken, 3600, "", "", false, true)
+		c.SetCookie("refresh_token", res.RefreshToken, 3600, "", "", false, true)
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// @Summary Refresh tokens
+// @Description exchanges a refresh token for a new access/refresh pair. Every refresh token can be used once; reusing an old one revokes all tokens issued from the same login
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param Refresh body models.RefreshRequest false "refresh token, defaults to the refresh_token cookie"
+// @Success 200 {object} models.Tokens
+// @Failure 400 {object} models.Error
+// @Failure 401 {object} models.Error
+// @Failure 500 {object} models.Error
+// @Router /refresh [post]
+func (h *authHandler) Refresh(c *gin.Context) {
+	var req models.RefreshRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			h.log.Error("Error occurred while binding json", "error", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if req.RefreshToken == "" {
+		req.RefreshToken, _ = c.Cookie("refresh_token")
+	}
+	if req.RefreshToken == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "refresh token is required"})
+		return
+	}
+
+	res, err := h.srv.Refresh(c, req.RefreshToken, client(c))
+	if errors.Is(err, service.ErrInvalidRefreshToken) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		h.log.Error("Error occurred while refreshing tokens", "error", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -204,3 +270,192 @@ func (h *authHandler) LoginUsername(c *gin.Context) {
 
 	c.JSON(http.StatusOK, res)
 }
+
+// client describes the device a request comes from.
+func client(c *gin.Context) models.Client {
+	return models.Client{IP: c.ClientIP(), UserAgent: c.Request.UserAgent()}
+}
+
+// accessToken reads the caller's access token from the Authorization header,
+// falling back to the access_token cookie.
+func accessToken(c *gin.Context) string {
+	if auth := c.GetHeader("Authorization"); auth != "" {
+		return strings.TrimPrefix(auth, "Bearer ")
+	}
+	tok, _ := c.Cookie("access_token")
+	return tok
+}
+
+func clearTokenCookies(c *gin.Context) {
+	c.SetCookie("access_token", "", -1, "", "", false, true)
+	c.SetCookie("refresh_token", "", -1, "", "", false, true)
+}
+
+// @Summary Logout
+// @Description revokes the access token and the refresh token issued with it
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param Logout body models.RefreshRequest false "refresh token, defaults to the refresh_token cookie"
+// @Success 200 {object} models.Message
+// @Failure 401 {object} models.Error
+// @Failure 500 {object} models.Error
+// @Router /logout [post]
+func (h *authHandler) Logout(c *gin.Context) {
+	var req models.RefreshRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			h.log.Error("Error occurred while binding json", "error", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if req.RefreshToken == "" {
+		req.RefreshToken, _ = c.Cookie("refresh_token")
+	}
+
+	access := accessToken(c)
+	if access == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "access token is required"})
+		return
+	}
+
+	err := h.srv.Logout(c, access, req.RefreshToken)
+	if errors.Is(err, service.ErrInvalidAccessToken) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		h.log.Error("Error occurred while logging out", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	clearTokenCookies(c)
+	c.JSON(http.StatusOK, models.Message{Message: "logged out"})
+}
+
+// @Summary Logout everywhere
+// @Description revokes every access and refresh token of the user, on all devices
+// @Tags Auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.Message
+// @Failure 401 {object} models.Error
+// @Failure 500 {object} models.Error
+// @Router /logout/all [post]
+func (h *authHandler) LogoutAll(c *gin.Context) {
+	access := accessToken(c)
+	if access == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "access token is required"})
+		return
+	}
+
+	err := h.srv.LogoutAll(c, access)
+	if errors.Is(err, service.ErrInvalidAccessToken) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		h.log.Error("Error occurred while logging out everywhere", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	clearTokenCookies(c)
+	c.JSON(http.StatusOK, models.Message{Message: "logged out everywhere"})
+}
+
+// @Summary Forgot password
+// @Description emails a one-time link to reset the password. The response is the same whether or not an account has the email
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param ForgotPassword body models.ForgotPasswordRequest true "account email"
+// @Success 202 {object} models.Message
+// @Failure 400 {object} models.Error
+// @Failure 429 {object} models.Error
+// @Failure 500 {object} models.Error
+// @Failure 503 {object} models.Error
+// @Router /forgot-password [post]
+func (h *authHandler) ForgotPassword(c *gin.Context) {
+	var req models.ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Error("Error occurred while binding json", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Refused for every email alike, so this does not tell whether the
+	// account exists either.
+	if err := h.mail.Ready(); err != nil {
+		h.log.Error("Cannot send password reset email", "error", err)
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Email is not available, try again later"})
+		return
+	}
+
+	link, err := h.srv.ForgotPassword(c, req.Email)
+	if errors.Is(err, service.ErrTooManyResetRequests) {
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		h.log.Error("Error occurred while issuing reset token", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process request"})
+		return
+	}
+
+	// Sending is queued, so the response time does not tell whether the
+	// account exists.
+	if link != "" {
+		if err := h.mail.SendPasswordReset(req.Email, link); err != nil {
+			h.log.Error("Failed to send password reset email", "error", err)
+		}
+	}
+
+	c.JSON(http.StatusAccepted, models.Message{Message: "if an account with this email exists, a reset link has been sent to it"})
+}
+
+// @Summary Reset password
+// @Description sets a new password of 8 to 72 characters with the token from the reset link and logs the user out everywhere
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param ResetPassword body models.ResetPasswordRequest true "reset token and new password"
+// @Success 200 {object} models.Message
+// @Failure 400 {object} models.Error
+// @Failure 500 {object} models.Error
+// @Router /reset-password [post]
+func (h *authHandler) ResetPassword(c *gin.Context) {
+	var req models.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Error("Error occurred while binding json", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err := h.srv.ResetPassword(c, req.Token, req.NewPassword)
+	if errors.Is(err, service.ErrInvalidResetToken) || errors.Is(err, service.ErrInvalidPassword) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		h.log.Error("Error occurred while resetting password", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reset password"})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Message{Message: "password has been reset"})
+}
+
+// JWKS serves the public keys that verify access tokens, selected by their
+// kid header, including the next key before it starts signing. It lives at
+// /.well-known/jwks.json, outside the /auth base path, so it is not in the
+// swagger docs.
+func (h *authHandler) JWKS(c *gin.Context) {
+	// Verifiers may cache the set for less than a new key is published
+	// ahead of signing.
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.keys.JWKS())
+}
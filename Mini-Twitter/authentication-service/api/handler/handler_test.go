@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"auth-service/api/email"
+	"auth-service/pkg/models"
+	"auth-service/service"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// recordingService counts the calls that would lead to an email. Methods no
+// test needs are left to the nil embedded interface.
+type recordingService struct {
+	service.AuthService
+	registrations int
+	resets        int
+}
+
+func (s *recordingService) StartRegistration(ctx context.Context, in models.RegisterRequest1) error {
+	s.registrations++
+	return nil
+}
+
+func (s *recordingService) ForgotPassword(ctx context.Context, email string) (string, error) {
+	s.resets++
+	return "https://example.com/reset?token=secret", nil
+}
+
+func TestUnconfiguredMailFails(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	queue := email.NewQueue(email.UnconfiguredMailer{}, logger, 10)
+	defer queue.Close()
+	mail, err := email.NewSender(queue)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv := &recordingService{}
+	h := NewAuthHandler(logger, srv, mail, nil)
+	r := gin.New()
+	r.POST("/register", h.Register)
+	r.POST("/forgot-password", h.ForgotPassword)
+
+	tests := []struct {
+		path string
+		body string
+	}{
+		{"/register", `{"email": "alice@example.com", "username": "alice", "password": "long enough"}`},
+		{"/forgot-password", `{"email": "alice@example.com"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body)))
+			if w.Code != http.StatusServiceUnavailable {
+				t.Fatalf("got %d %s, want %d", w.Code, w.Body, http.StatusServiceUnavailable)
+			}
+		})
+	}
+
+	if srv.registrations != 0 || srv.resets != 0 {
+		t.Errorf("service called without mail: %d registrations, %d resets", srv.registrations, srv.resets)
+	}
+}
@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"auth-service/service"
+	"errors"
+	"net/http"
+	"path"
+
+	"github.com/gin-gonic/gin"
+)
+
+// oidcStateCookie binds a login at a provider to the browser that started it.
+const oidcStateCookie = "oidc_state"
+
+// oidcError maps the errors of logins at external providers to a response.
+func (h *authHandler) oidcError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrUnknownProvider):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrInvalidOIDCState):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrOIDCLoginFailed):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrEmailNotVerified):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	default:
+		h.log.Error("Error occurred in external login", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process request"})
+	}
+}
+
+// @Summary Login with identity provider
+// @Description redirects to the provider's sign-in page and sets the oidc_state cookie. The provider sends the user back to the callback, which needs the cookie
+// @Tags OIDC
+// @Param provider path string true "provider name, e.g. google"
+// @Success 302
+// @Failure 404 {object} models.Error
+// @Failure 500 {object} models.Error
+// @Router /oidc/{provider}/login [get]
+func (h *authHandler) OIDCLogin(c *gin.Context) {
+	url, binding, err := h.srv.OIDCLoginURL(c, c.Param("provider"))
+	if err != nil {
+		h.oidcError(c, err)
+		return
+	}
+
+	// Lax, so the cookie comes along when the provider redirects back.
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oidcStateCookie, binding, int(service.OIDCStateTTL.Seconds()), path.Dir(c.Request.URL.Path), "", false, true)
+	c.Redirect(http.StatusFound, url)
+}
+
+// @Summary Identity provider callback
+// @Description finishes a login at the provider. The account is found by the linked identity or the verified email, or created. Accounts with two-factor authentication get an mfa_token instead of tokens
+// @Tags OIDC
+// @Produce json
+// @Param provider path string true "provider name"
+// @Param code query string true "authorization code"
+// @Param state query string true "state from the login redirect"
+// @Success 200 {object} models.Tokens
+// @Failure 400 {object} models.Error
+// @Failure 401 {object} models.Error
+// @Failure 403 {object} models.Error
+// @Failure 404 {object} models.Error
+// @Failure 500 {object} models.Error
+// @Router /oidc/{provider}/callback [get]
+func (h *authHandler) OIDCCallback(c *gin.Context) {
+	// The state can be used once either way.
+	binding, _ := c.Cookie(oidcStateCookie)
+	c.SetCookie(oidcStateCookie, "", -1, path.Dir(c.Request.URL.Path), "", false, true)
+
+	if reason := c.Query("error"); reason != "" {
+		// The user declined or the provider failed.
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "login with the identity provider failed: " + reason})
+		return
+	}
+
+	res, err := h.srv.OIDCCallback(c, c.Param("provider"), c.Query("state"), binding, c.Query("code"), client(c))
+	if err != nil {
+		h.oidcError(c, err)
+		return
+	}
+
+	if res.AccessToken != "" {
+		c.SetCookie("access_token", res.AccessToken, 3600, "", "", false, true)
+		c.SetCookie("refresh_token", res.RefreshToken, 3600, "", "", false, true)
+	}
+
+	c.JSON(http.StatusOK, res)
+}
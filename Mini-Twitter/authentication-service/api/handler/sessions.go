@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"auth-service/pkg/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary List sessions
+// @Description lists the devices the caller is logged in on, most recently used first. The session of the calling token is marked current
+// @Tags Sessions
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.Sessions
+// @Failure 401 {object} models.Error
+// @Failure 500 {object} models.Error
+// @Router /sessions [get]
+func (h *authHandler) Sessions(c *gin.Context) {
+	sessions, err := h.srv.Sessions(c, accessToken(c))
+	if err != nil {
+		h.authError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Sessions{Sessions: sessions})
+}
+
+// @Summary Revoke session
+// @Description logs the caller out on one device. Its refresh token stops working at once, as do the access tokens issued for it
+// @Tags Sessions
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "session id"
+// @Success 200 {object} models.Message
+// @Failure 401 {object} models.Error
+// @Failure 404 {object} models.Error
+// @Failure 500 {object} models.Error
+// @Router /sessions/{id} [delete]
+func (h *authHandler) RevokeSession(c *gin.Context) {
+	err := h.srv.RevokeSession(c, accessToken(c), c.Param("id"))
+	if err != nil {
+		h.authError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Message{Message: "session revoked"})
+}
@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"auth-service/pkg/models"
+	"auth-service/service"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// authError maps the errors of authenticated and two-factor requests to a
+// response.
+func (h *authHandler) authError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidAccessToken),
+		errors.Is(err, service.ErrInvalidTwoFactorCode),
+		errors.Is(err, service.ErrInvalidMFAChallenge):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrTwoFactorEnabled):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrTwoFactorNotEnrolled):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrSessionNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		h.log.Error("Failed to process request", "path", c.FullPath(), "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process request"})
+	}
+}
+
+// @Summary Enroll two-factor authentication
+// @Description generates a TOTP secret and the otpauth URI to show as a QR code. Confirm it with a code to enable two-factor authentication
+// @Tags TwoFactor
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.TwoFactorEnrollment
+// @Failure 401 {object} models.Error
+// @Failure 409 {object} models.Error
+// @Failure 500 {object} models.Error
+// @Router /2fa/enroll [post]
+func (h *authHandler) EnrollTwoFactor(c *gin.Context) {
+	res, err := h.srv.EnrollTwoFactor(c, accessToken(c))
+	if err != nil {
+		h.authError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// @Summary Confirm two-factor authentication
+// @Description enables two-factor authentication with a code from the authenticator app and returns one-time recovery codes
+// @Tags TwoFactor
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param Code body models.TwoFactorCode true "code from the authenticator app"
+// @Success 200 {object} models.RecoveryCodes
+// @Failure 400 {object} models.Error
+// @Failure 401 {object} models.Error
+// @Failure 409 {object} models.Error
+// @Failure 500 {object} models.Error
+// @Router /2fa/confirm [post]
+func (h *authHandler) ConfirmTwoFactor(c *gin.Context) {
+	var req models.TwoFactorCode
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Error("Error occurred while binding json", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.srv.ConfirmTwoFactor(c, accessToken(c), req.Code)
+	if err != nil {
+		h.authError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// @Summary Login with two-factor code
+// @Description exchanges the mfa_token from a login and a TOTP or recovery code for tokens
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param Login body models.TwoFactorLoginRequest true "mfa token and code"
+// @Success 200 {object} models.Tokens
+// @Failure 400 {object} models.Error
+// @Failure 401 {object} models.Error
+// @Failure 500 {object} models.Error
+// @Router /login/2fa [post]
+func (h *authHandler) LoginTwoFactor(c *gin.Context) {
+	var req models.TwoFactorLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Error("Error occurred while binding json", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.srv.LoginTwoFactor(c, req, client(c))
+	if err != nil {
+		h.authError(c, err)
+		return
+	}
+
+	c.SetCookie("access_token", res.AccessToken, 3600, "", "", false, true)
+	c.SetCookie("refresh_token", res.RefreshToken, 3600, "", "", false, true)
+
+	c.JSON(http.StatusOK, res)
+}
+
+// @Summary Reset two-factor authentication
+// @Description turns off two-factor authentication of a user, for when they lost their authenticator and recovery codes. Admin only
+// @Tags TwoFactor
+// @Produce json
+// @Security BearerAuth
+// @Param user_id path string true "user id"
+// @Success 200 {object} models.Message
+// @Failure 401 {object} models.Error
+// @Failure 403 {object} models.Error
+// @Failure 500 {object} models.Error
+// @Router /2fa/{user_id} [delete]
+func (h *authHandler) ResetTwoFactor(c *gin.Context) {
+	err := h.srv.ResetTwoFactor(c, accessToken(c), c.Param("user_id"))
+	if err != nil {
+		h.authError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Message{Message: "two-factor authentication has been reset"})
+}
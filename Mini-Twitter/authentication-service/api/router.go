@@ -4,8 +4,12 @@ import (
 	"auth-service/api/handler"
 	"auth-service/pkg/config"
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"net/http"
+	"strings"
 
 	_ "auth-service/api/docs"
 )
@@ -22,20 +26,35 @@ type router struct {
 
 func NewRouter(authHandler handler.AuthHandler) Router {
 	routers := gin.Default()
+	routers.Use(otelgin.Middleware("auth-service", otelgin.WithFilter(untraced)))
 	return &router{
 		router:   routers,
 		handlers: authHandler,
 	}
 }
 
+// untraced leaves out the requests of scrapers and docs.
+func untraced(r *http.Request) bool {
+	switch r.URL.Path {
+	case "/metrics", "/.well-known/jwks.json":
+		return false
+	}
+	return !strings.HasPrefix(r.URL.Path, "/swagger/")
+}
+
 // @title Authenfication service
 // @version 1.0
 // @description server for siginIn or signUp
 // @BasePath /auth
 // @schemes http
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
 func (r *router) InitRouter() {
 
 	r.router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	r.router.GET("/.well-known/jwks.json", r.handlers.JWKS)
+	r.router.GET("/metrics", gin.WrapH(promhttp.Handler()))
 
 	auth := r.router.Group("/auth")
 	{
@@ -43,6 +62,21 @@ func (r *router) InitRouter() {
 		auth.POST("/login/email", r.handlers.LoginEmail)
 		auth.POST("/login/username", r.handlers.LoginUsername)
 		auth.POST("/accept-code", r.handlers.AcceptCodeToRegister)
+		auth.POST("/refresh", r.handlers.Refresh)
+		auth.POST("/logout", r.handlers.Logout)
+		auth.POST("/logout/all", r.handlers.LogoutAll)
+		auth.POST("/forgot-password", r.handlers.ForgotPassword)
+		auth.POST("/reset-password", r.handlers.ResetPassword)
+		auth.POST("/login/2fa", r.handlers.LoginTwoFactor)
+		auth.POST("/2fa/enroll", r.handlers.EnrollTwoFactor)
+		auth.POST("/2fa/confirm", r.handlers.ConfirmTwoFactor)
+		auth.DELETE("/2fa/:user_id", r.handlers.ResetTwoFactor)
+		auth.POST("/unlock", r.handlers.Unlock)
+		auth.GET("/security-events", r.handlers.SecurityEvents)
+		auth.GET("/sessions", r.handlers.Sessions)
+		auth.DELETE("/sessions/:id", r.handlers.RevokeSession)
+		auth.GET("/oidc/:provider/login", r.handlers.OIDCLogin)
+		auth.GET("/oidc/:provider/callback", r.handlers.OIDCCallback)
 	}
 }
 
@@ -2,13 +2,27 @@ package main
 
 import (
 	"auth-service/api"
+	"auth-service/api/email"
 	"auth-service/api/handler"
 	"auth-service/genproto/user"
 	"auth-service/pkg/config"
+	"auth-service/pkg/events"
 	"auth-service/pkg/logs"
+	"auth-service/pkg/oidc"
+	"auth-service/pkg/token"
 	"auth-service/service"
 	"auth-service/storage/postgres"
 	"auth-service/storage/redis"
+	"common/health"
+	"common/interceptor"
+	"common/outbox"
+	"common/tracing"
+	"context"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc/filters"
 	"google.golang.org/grpc"
 	"log"
 	"net"
@@ -17,6 +31,19 @@ import (
 func main() {
 	logger := logs.InitLogger()
 	cofg := config.Load()
+
+	shutdown, err := tracing.Init(context.Background(), tracing.Config{
+		ServiceName: "auth-service",
+		Exporter:    cofg.TRACING_EXPORTER,
+		Endpoint:    cofg.OTLP_ENDPOINT,
+		SampleRatio: cofg.TRACING_SAMPLE_RATIO,
+	})
+	if err != nil {
+		logger.Error("Failed to initialize tracing", "error", err)
+		log.Fatal(err)
+	}
+	defer shutdown(context.Background())
+
 	redisClient := redis.ConnectDB()
 
 	db, err := postgres.ConnectPostgres(cofg)
@@ -24,18 +51,84 @@ func main() {
 		logger.Error("Error connecting to database", "error", err)
 		log.Fatal(err)
 	}
+	prometheus.MustRegister(collectors.NewDBStatsCollector(db.DB, cofg.DB_NAME))
+
+	conn, err := amqp.Dial(cofg.RABBITMQ_URL)
+	if err != nil {
+		logger.Error("Failed to connect to RabbitMQ", "error", err)
+		log.Fatal(err)
+	}
+	defer conn.Close()
+
+	ch, err := conn.Channel()
+	if err != nil {
+		logger.Error("Failed to open a channel", "error", err)
+		log.Fatal(err)
+	}
+	defer ch.Close()
+
+	err = ch.ExchangeDeclare(events.Exchange, amqp.ExchangeTopic, true, false, false, false, nil)
+	if err != nil {
+		logger.Error("Failed to declare exchange "+events.Exchange, "error", err)
+		log.Fatal(err)
+	}
+
+	relay, err := outbox.NewRelay(db, ch, logger)
+	if err != nil {
+		logger.Error("Failed to start outbox relay", "error", err)
+		log.Fatal(err)
+	}
+	go relay.Run(context.Background())
+
+	signingKeys, err := token.SealKeys(postgres.NewSigningKeyRepo(db), cofg.SIGNING_KEY_SECRET)
+	if err != nil {
+		logger.Error("Failed to set up signing key storage", "error", err)
+		log.Fatal(err)
+	}
+	keys := token.NewKeySet(signingKeys, cofg.KEY_ROTATION_INTERVAL, logger)
+	if err := keys.Sync(context.Background()); err != nil {
+		logger.Error("Failed to load signing keys", "error", err)
+		log.Fatal(err)
+	}
+	token.UseKeySet(keys)
+	go keys.Run(context.Background())
+
+	redis1 := redis.NewRedisStorage(redisClient, logger)
 
 	userSt := postgres.NewUserRepo(db)
-	userSr := service.NewUserService(userSt, logger)
+	userSr := service.NewUserService(userSt, redis1, cofg.ACCOUNT_DELETION_GRACE, cofg.POPULAR_USER_WINDOW, cofg.USERNAME_CHANGE_COOLDOWN, logger)
+	go userSr.RunDeletions(context.Background())
 	listen, err := net.Listen("tcp", cofg.USER_PORT)
 	if err != nil {
 		logger.Error("Error listening on port "+cofg.USER_PORT, "error", err)
 		log.Fatal(err)
 	}
 
+	server := grpc.NewServer(grpc.StatsHandler(otelgrpc.NewServerHandler(otelgrpc.WithFilter(filters.Not(filters.HealthCheck())))), interceptor.Chain(interceptor.Config{
+		Logger:         logger,
+		Verify:         verifyAccessToken,
+		Rules:          service.UserRules,
+		DefaultTimeout: cofg.GRPC_DEFAULT_TIMEOUT,
+		MaxTimeout:     cofg.GRPC_MAX_TIMEOUT,
+		Errors:         service.Status,
+	}))
+	user.RegisterUserServiceServer(server, userSr)
+
+	healthSr := health.Register(server, user.UserService_ServiceDesc.ServiceName)
+	go health.Watch(context.Background(), healthSr, []string{user.UserService_ServiceDesc.ServiceName}, map[string]health.Probe{
+		"postgres": db.PingContext,
+		"redis": func(ctx context.Context) error {
+			return redisClient.Ping(ctx).Err()
+		},
+		"rabbitmq": func(ctx context.Context) error {
+			if conn.IsClosed() {
+				return amqp.ErrClosed
+			}
+			return nil
+		},
+	}, cofg.HEALTH_CHECK_INTERVAL, logger)
+
 	go func() {
-		server := grpc.NewServer()
-		user.RegisterUserServiceServer(server, userSr)
 		logger.Info("Starting server on port " + cofg.USER_PORT)
 		log.Println("Starting server on port " + cofg.USER_PORT)
 
@@ -47,10 +140,36 @@ func main() {
 
 	//------------------------------------------------------------------
 
+	mailer, err := email.New(cofg, logger)
+	if err != nil {
+		logger.Error("Failed to create mailer", "error", err)
+		log.Fatal(err)
+	}
+	mailQueue := email.NewQueue(mailer, logger, 100)
+	mailQueue.Start(context.Background(), 2)
+	mail, err := email.NewSender(mailQueue)
+	if err != nil {
+		logger.Error("Failed to load mail templates", "error", err)
+		log.Fatal(err)
+	}
+
+	providers := make(map[string]*oidc.Provider)
+	for _, p := range cofg.OIDC_PROVIDERS {
+		providers[p.Name] = oidc.NewProvider(oidc.Config{
+			Name:         p.Name,
+			Issuer:       p.Issuer,
+			ClientID:     p.ClientID,
+			ClientSecret: p.ClientSecret,
+			RedirectURL:  cofg.OIDC_REDIRECT_URL + "/" + p.Name + "/callback",
+		})
+	}
+
 	authSt := postgres.NewAuthRepo(db)
-	authSr := service.NewAuthService(authSt, logger)
-	redis1 := redis.NewRedisStorage(redisClient, logger)
-	authHd := handler.NewAuthHandler(logger, authSr, redis1)
+	twoFactorSt := postgres.NewTwoFactorRepo(db)
+	identitySt := postgres.NewIdentityRepo(db)
+	authSr := service.NewAuthService(authSt, redis1, redis1, redis1, redis1, twoFactorSt, redis1, redis1, identitySt, redis1, providers, mail, logger)
+
+	authHd := handler.NewAuthHandler(logger, authSr, mail, keys)
 	router := api.NewRouter(authHd)
 
 	router.InitRouter()
@@ -61,3 +180,13 @@ func main() {
 		log.Fatal(err)
 	}
 }
+
+// verifyAccessToken checks an access token forwarded by the gateway against
+// the signing keys of this service.
+func verifyAccessToken(ctx context.Context, accessToken string) (interceptor.Caller, error) {
+	claims, err := token.ExtractClaimsAccess(accessToken)
+	if err != nil {
+		return interceptor.Caller{}, err
+	}
+	return interceptor.Caller{UserID: claims.ID, Role: claims.Role}, nil
+}
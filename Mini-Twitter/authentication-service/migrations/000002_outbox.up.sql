@@ -0,0 +1,12 @@
+create table outbox (
+    id bigserial primary key,
+    exchange varchar not null default '',
+    routing_key varchar not null,
+    content_type varchar not null,
+    message_id varchar not null,
+    body bytea not null,
+    created_at timestamp with time zone default now(),
+    sent_at timestamp with time zone
+);
+
+create index outbox_unsent_idx on outbox (id) where sent_at is null;
@@ -0,0 +1,17 @@
+create table if not exists two_factor(
+    user_id uuid primary key references users(id),
+    -- The TOTP secret has to be readable to check codes, so it cannot be hashed.
+    secret varchar not null,
+    enabled bool not null default false,
+    created_at timestamp with time zone default now(),
+    enabled_at timestamp with time zone
+);
+
+create table if not exists recovery_codes(
+    id bigserial primary key,
+    user_id uuid not null references users(id),
+    code_hash varchar not null,
+    used_at timestamp with time zone,
+    created_at timestamp with time zone default now(),
+    unique(user_id, code_hash)
+);
@@ -0,0 +1,11 @@
+create table if not exists signing_keys(
+    -- The kid header of the tokens signed with the key.
+    id varchar primary key,
+    algorithm varchar not null,
+    -- PKCS#8 PEM.
+    private_key text not null,
+    -- The key is published in the JWKS as soon as it is created, but only
+    -- signs from active_from on, so verifiers have time to fetch it.
+    active_from timestamp with time zone not null,
+    created_at timestamp with time zone default now()
+);
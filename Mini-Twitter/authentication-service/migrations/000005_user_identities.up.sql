@@ -0,0 +1,11 @@
+create table if not exists user_identities(
+    provider varchar not null,
+    -- The provider's stable id of the user; emails can change.
+    subject varchar not null,
+    user_id uuid not null references users(id),
+    email varchar,
+    created_at timestamp with time zone default now(),
+    primary key(provider, subject)
+);
+
+create index if not exists user_identities_user_id_idx on user_identities(user_id);
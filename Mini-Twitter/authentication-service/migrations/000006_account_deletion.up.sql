@@ -0,0 +1,5 @@
+-- When set, the account is purged for good once this time has passed. Until
+-- then the user can sign in and cancel the deletion.
+alter table users add column if not exists delete_after timestamp with time zone;
+
+create index if not exists users_delete_after_idx on users(delete_after) where delete_after is not null;
@@ -0,0 +1,4 @@
+drop index if exists follows_following_id_created_at_idx;
+drop index if exists follows_follower_id_idx;
+
+drop table if exists user_blocks;
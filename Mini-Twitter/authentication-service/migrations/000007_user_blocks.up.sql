@@ -0,0 +1,12 @@
+create table if not exists user_blocks(
+    blocker_id uuid not null references users(id),
+    blocked_id uuid not null references users(id),
+    created_at timestamp with time zone default now(),
+    primary key(blocker_id, blocked_id)
+);
+
+create index if not exists user_blocks_blocked_id_idx on user_blocks(blocked_id);
+
+-- Suggestions and popularity walk follows from both ends.
+create index if not exists follows_follower_id_idx on follows(follower_id);
+create index if not exists follows_following_id_created_at_idx on follows(following_id, created_at);
@@ -0,0 +1,3 @@
+drop table if exists admin_audit;
+
+alter table user_profile drop column if exists verified;
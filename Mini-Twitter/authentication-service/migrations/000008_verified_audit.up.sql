@@ -0,0 +1,13 @@
+alter table user_profile add column if not exists verified bool not null default false;
+
+create table if not exists admin_audit(
+    id bigserial primary key,
+    -- No foreign keys, so the record outlives the accounts it is about.
+    actor_id uuid not null,
+    user_id uuid not null,
+    action varchar not null,
+    detail varchar not null default '',
+    created_at timestamp with time zone default now()
+);
+
+create index if not exists admin_audit_user_id_idx on admin_audit(user_id, id);
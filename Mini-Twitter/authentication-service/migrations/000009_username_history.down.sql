@@ -0,0 +1,3 @@
+drop table if exists username_history;
+
+alter table user_profile drop column if exists username_changed_at;
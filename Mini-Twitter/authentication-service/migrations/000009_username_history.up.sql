@@ -0,0 +1,11 @@
+alter table user_profile add column if not exists username_changed_at timestamp with time zone;
+
+-- Former usernames. They stay with their account, so links to them keep
+-- resolving and no one else can take them over.
+create table if not exists username_history(
+    username varchar primary key,
+    user_id uuid not null references users(id),
+    changed_at timestamp with time zone default now()
+);
+
+create index if not exists username_history_user_id_idx on username_history(user_id);
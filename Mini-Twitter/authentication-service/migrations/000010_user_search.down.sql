@@ -0,0 +1,3 @@
+drop index if exists user_profile_last_name_trgm_idx;
+drop index if exists user_profile_first_name_trgm_idx;
+drop index if exists user_profile_username_trgm_idx;
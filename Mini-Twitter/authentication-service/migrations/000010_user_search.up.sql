@@ -0,0 +1,7 @@
+create extension if not exists pg_trgm;
+
+-- User search matches names by trigram similarity and by prefix; both use
+-- these indexes.
+create index if not exists user_profile_username_trgm_idx on user_profile using gin (lower(username) gin_trgm_ops);
+create index if not exists user_profile_first_name_trgm_idx on user_profile using gin (lower(first_name) gin_trgm_ops);
+create index if not exists user_profile_last_name_trgm_idx on user_profile using gin (lower(last_name) gin_trgm_ops);
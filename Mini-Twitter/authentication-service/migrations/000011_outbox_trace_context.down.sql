@@ -0,0 +1 @@
+alter table outbox drop column if exists trace_context;
@@ -0,0 +1 @@
+alter table outbox add column trace_context jsonb not null default '{}';
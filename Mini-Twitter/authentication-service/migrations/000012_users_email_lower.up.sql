@@ -0,0 +1,2 @@
+-- Password reset looks users up by email regardless of case.
+create index if not exists users_email_lower_idx on users (lower(email)) where deleted_at = 0;
@@ -0,0 +1,2 @@
+drop index if exists follows_created_at_idx;
+alter table follows drop constraint if exists follows_follower_id_following_id_key;
@@ -0,0 +1,8 @@
+-- A follow is kept once; duplicates left by concurrent follows are dropped.
+delete from follows a using follows b
+where a.ctid < b.ctid and a.follower_id = b.follower_id and a.following_id = b.following_id;
+
+alter table follows add constraint follows_follower_id_following_id_key unique (follower_id, following_id);
+
+-- Suggestions rank by followers gained recently.
+create index if not exists follows_created_at_idx on follows(created_at);
@@ -0,0 +1,2 @@
+drop index if exists username_history_username_lower_idx;
+drop index if exists user_profile_username_lower_idx;
@@ -0,0 +1,3 @@
+-- Usernames are looked up regardless of case, current and former ones alike.
+create index if not exists user_profile_username_lower_idx on user_profile (lower(username));
+create index if not exists username_history_username_lower_idx on username_history (lower(username));
@@ -0,0 +1 @@
+drop index if exists user_profile_full_name_trgm_idx;
@@ -0,0 +1,4 @@
+-- Searching by full name matches first and last name joined by a space. The
+-- expression is spelled out, as concat_ws cannot be indexed.
+create index if not exists user_profile_full_name_trgm_idx on user_profile
+    using gin ((lower(COALESCE(first_name, '') || ' ' || COALESCE(last_name, ''))) gin_trgm_ops);
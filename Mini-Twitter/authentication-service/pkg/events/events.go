@@ -0,0 +1,50 @@
+// Package events defines the events auth-service publishes through the
+// outbox.
+package events
+
+import (
+	"common/outbox"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Exchange is the topic exchange all auth-service events are published to.
+const Exchange = "auth.events"
+
+// Routing keys of the published events.
+const (
+	UserRegistered = "user.registered"
+	// UserDeleted is published once an account is purged or deleted by an
+	// admin, so other services drop what they keep about the user.
+	UserDeleted = "user.deleted"
+)
+
+// UserEvent is the JSON body of every user.* event.
+type UserEvent struct {
+	ID         string `json:"id"`
+	UserID     string `json:"user_id"`
+	Email      string `json:"email,omitempty"`
+	Username   string `json:"username,omitempty"`
+	OccurredAt string `json:"occurred_at"`
+}
+
+// NewUserEvent builds the outbox message for a user event.
+func NewUserEvent(routingKey string, e UserEvent) (outbox.Message, error) {
+	e.ID = uuid.NewString()
+	e.OccurredAt = time.Now().UTC().Format(time.RFC3339Nano)
+
+	body, err := json.Marshal(e)
+	if err != nil {
+		return outbox.Message{}, err
+	}
+
+	return outbox.Message{
+		Exchange:    Exchange,
+		RoutingKey:  routingKey,
+		ContentType: "application/json",
+		MessageID:   e.ID,
+		Body:        body,
+	}, nil
+}
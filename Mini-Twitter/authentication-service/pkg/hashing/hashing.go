@@ -12,7 +12,14 @@ func HashPassword(password string) (string, error) {
 	return string(hashPassword), nil
 }
 
+// NoPassword is stored for accounts without a password, such as those
+// created by a login at an identity provider. No password matches it.
+const NoPassword = "!"
+
 func CheckPasswordHash(hash, password string) bool {
+	if hash == NoPassword {
+		return false
+	}
 	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
 
 }
@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 type RegisterRequest struct {
 	FirstName   string `json:"first_name" db:"first_name"`
 	LastName    string `json:"last_name" db:"last_name"`
@@ -52,8 +54,86 @@ type LoginUsernameRequest struct {
 }
 
 type Tokens struct {
-	AccessToken  string `json:"access_token" db:"access_token"`
-	RefreshToken string `json:"refresh_token" db:"refresh_token"`
+	AccessToken  string `json:"access_token,omitempty" db:"access_token"`
+	RefreshToken string `json:"refresh_token,omitempty" db:"refresh_token"`
+	// MFAToken is returned instead of the tokens when the account has
+	// two-factor authentication; exchange it at /login/2fa.
+	MFAToken string `json:"mfa_token,omitempty" db:"-"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required"`
+}
+
+type ResetPasswordRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required"`
+}
+
+type TwoFactor struct {
+	UserID  string `json:"user_id" db:"user_id"`
+	Secret  string `json:"-" db:"secret"`
+	Enabled bool   `json:"enabled" db:"enabled"`
+}
+
+type TwoFactorEnrollment struct {
+	Secret string `json:"secret"`
+	// OtpauthURI is the payload of the QR code to scan with an
+	// authenticator app.
+	OtpauthURI string `json:"otpauth_uri"`
+}
+
+type TwoFactorCode struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type RecoveryCodes struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type TwoFactorLoginRequest struct {
+	MFAToken string `json:"mfa_token" binding:"required"`
+	// Either a code from the authenticator app or an unused recovery code.
+	Code         string `json:"code"`
+	RecoveryCode string `json:"recovery_code"`
+}
+
+// LoginStatus says whether a login may be attempted now.
+type LoginStatus struct {
+	Locked     bool
+	RetryAfter time.Duration
+}
+
+type SecurityEvent struct {
+	Type     string `json:"type"`
+	UserID   string `json:"user_id,omitempty"`
+	Account  string `json:"account,omitempty"`
+	IP       string `json:"ip,omitempty"`
+	Detail   string `json:"detail,omitempty"`
+	Occurred string `json:"occurred_at"`
+}
+
+type SecurityEvents struct {
+	Events []SecurityEvent `json:"events"`
+}
+
+type UnlockRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type SigningKey struct {
+	ID         string    `db:"id"`
+	Algorithm  string    `db:"algorithm"`
+	PrivateKey string    `db:"private_key"`
+	ActiveFrom time.Time `db:"active_from"`
+}
+
+type Message struct {
+	Message string `json:"message"`
 }
 
 type Error struct {
@@ -63,3 +143,40 @@ type AcceptCode struct {
 	Email string `json:"email"`
 	Code  string `json:"code"`
 }
+
+// Client describes where a request comes from.
+type Client struct {
+	IP        string
+	UserAgent string
+}
+
+// Session is one login of a user, alive for as long as its refresh token
+// family.
+type Session struct {
+	ID        string `json:"id"`
+	UserAgent string `json:"user_agent"`
+	IP        string `json:"ip"`
+	CreatedAt string `json:"created_at"`
+	LastSeen  string `json:"last_seen"`
+	// Current marks the session of the access token that listed it.
+	Current bool `json:"current"`
+}
+
+type Sessions struct {
+	Sessions []Session `json:"sessions"`
+}
+
+// OIDCState is what a login at an external provider needs to be finished
+// once the provider redirects back.
+type OIDCState struct {
+	Provider string `json:"provider"`
+	Verifier string `json:"verifier"`
+	Nonce    string `json:"nonce"`
+}
+
+// ExternalIdentity is a user account at an external provider.
+type ExternalIdentity struct {
+	Provider string
+	Subject  string
+	Email    string
+}
@@ -0,0 +1,344 @@
+// Package oidc is a client of OpenID Connect providers for the authorization
+// code flow with PKCE.
+package oidc
+
+import (
+	"auth-service/pkg/token"
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"golang.org/x/oauth2"
+	"golang.org/x/sync/singleflight"
+)
+
+// ErrInvalidIDToken is returned when the provider's ID token does not verify.
+var ErrInvalidIDToken = errors.New("invalid id token")
+
+type Config struct {
+	Name         string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	// RedirectURL is the callback of auth-service registered at the
+	// provider.
+	RedirectURL string
+}
+
+// Identity is the user a provider vouches for.
+type Identity struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+	Nonce         string
+}
+
+type discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// Provider is one configured identity provider. Its endpoints are discovered
+// on first use, so a provider that is down does not keep auth-service from
+// starting.
+type Provider struct {
+	cfg    Config
+	client *http.Client
+	// fetches makes concurrent discoveries and JWKS fetches share one
+	// request, which runs without holding mu.
+	fetches singleflight.Group
+
+	mu      sync.Mutex
+	meta    *discovery
+	keys    map[string]*rsa.PublicKey
+	keysAge time.Time
+}
+
+func NewProvider(cfg Config) *Provider {
+	return &Provider{
+		cfg:    cfg,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (p *Provider) Name() string {
+	return p.cfg.Name
+}
+
+// GenerateVerifier returns a new PKCE code verifier.
+func GenerateVerifier() string {
+	return oauth2.GenerateVerifier()
+}
+
+// AuthCodeURL is where to send the user to sign in. Only the S256 challenge
+// of verifier is sent; the verifier itself is needed for Exchange.
+func (p *Provider) AuthCodeURL(ctx context.Context, state, nonce, verifier string) (string, error) {
+	conf, err := p.oauth(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return conf.AuthCodeURL(state,
+		oauth2.S256ChallengeOption(verifier),
+		oauth2.SetAuthURLParam("nonce", nonce),
+	), nil
+}
+
+// Exchange redeems an authorization code and returns the identity from the
+// verified ID token. Checking the nonce is left to the caller.
+func (p *Provider) Exchange(ctx context.Context, code, verifier string) (Identity, error) {
+	conf, err := p.oauth(ctx)
+	if err != nil {
+		return Identity{}, err
+	}
+
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, p.client)
+	tok, err := conf.Exchange(ctx, code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return Identity{}, fmt.Errorf("failed to exchange code: %w", err)
+	}
+
+	raw, ok := tok.Extra("id_token").(string)
+	if !ok || raw == "" {
+		return Identity{}, fmt.Errorf("%w: missing from token response", ErrInvalidIDToken)
+	}
+
+	return p.verify(ctx, raw)
+}
+
+func (p *Provider) oauth(ctx context.Context) (*oauth2.Config, error) {
+	meta, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &oauth2.Config{
+		ClientID:     p.cfg.ClientID,
+		ClientSecret: p.cfg.ClientSecret,
+		RedirectURL:  p.cfg.RedirectURL,
+		Scopes:       []string{"openid", "email", "profile"},
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  meta.AuthorizationEndpoint,
+			TokenURL: meta.TokenEndpoint,
+		},
+	}, nil
+}
+
+func (p *Provider) discover(ctx context.Context) (*discovery, error) {
+	p.mu.Lock()
+	meta := p.meta
+	p.mu.Unlock()
+	if meta != nil {
+		return meta, nil
+	}
+
+	v, err := p.shared(ctx, "discovery", func(ctx context.Context) (interface{}, error) {
+		url := strings.TrimSuffix(p.cfg.Issuer, "/") + "/.well-known/openid-configuration"
+		var meta discovery
+		if err := p.getJSON(ctx, url, &meta); err != nil {
+			return nil, fmt.Errorf("failed to discover %s: %w", p.cfg.Name, err)
+		}
+		if meta.Issuer != p.cfg.Issuer {
+			return nil, fmt.Errorf("provider %s reports issuer %q, want %q", p.cfg.Name, meta.Issuer, p.cfg.Issuer)
+		}
+
+		p.mu.Lock()
+		p.meta = &meta
+		p.mu.Unlock()
+		return &meta, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*discovery), nil
+}
+
+// shared runs fetch once for all callers asking for key at the same time. A
+// caller stops waiting when its ctx is done; the fetch goes on for the others.
+func (p *Provider) shared(ctx context.Context, key string, fetch func(context.Context) (interface{}, error)) (interface{}, error) {
+	ch := p.fetches.DoChan(key, func() (interface{}, error) {
+		return fetch(context.WithoutCancel(ctx))
+	})
+
+	select {
+	case res := <-ch:
+		return res.Val, res.Err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// idClaims are the ID token claims used here. aud may be a string or a list,
+// which jwt.StandardClaims cannot hold.
+type idClaims struct {
+	Issuer        string   `json:"iss"`
+	Subject       string   `json:"sub"`
+	Audience      audience `json:"aud"`
+	ExpiresAt     int64    `json:"exp"`
+	Nonce         string   `json:"nonce"`
+	Email         string   `json:"email"`
+	EmailVerified bool     `json:"email_verified"`
+	Name          string   `json:"name"`
+}
+
+func (c *idClaims) Valid() error {
+	if time.Now().Unix() > c.ExpiresAt {
+		return errors.New("token is expired")
+	}
+	return nil
+}
+
+type audience []string
+
+func (a *audience) UnmarshalJSON(b []byte) error {
+	var one string
+	if err := json.Unmarshal(b, &one); err == nil {
+		*a = audience{one}
+		return nil
+	}
+
+	var many []string
+	if err := json.Unmarshal(b, &many); err != nil {
+		return err
+	}
+	*a = many
+	return nil
+}
+
+func (a audience) contains(s string) bool {
+	for _, v := range a {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *Provider) verify(ctx context.Context, raw string) (Identity, error) {
+	claims := &idClaims{}
+	_, err := jwt.ParseWithClaims(raw, claims, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodRSA); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
+		}
+		kid, _ := t.Header["kid"].(string)
+		return p.key(ctx, kid)
+	})
+	if err != nil {
+		return Identity{}, fmt.Errorf("%w: %v", ErrInvalidIDToken, err)
+	}
+
+	if claims.Issuer != p.cfg.Issuer || !claims.Audience.contains(p.cfg.ClientID) || claims.Subject == "" {
+		return Identity{}, fmt.Errorf("%w: wrong issuer, audience or subject", ErrInvalidIDToken)
+	}
+
+	return Identity{
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: claims.EmailVerified,
+		Name:          claims.Name,
+		Nonce:         claims.Nonce,
+	}, nil
+}
+
+// key returns the provider's signing key kid, refetching the JWKS when kid is
+// unknown, at most once a minute.
+func (p *Provider) key(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	p.mu.Lock()
+	key, ok := p.keys[kid]
+	keysAge := p.keysAge
+	p.mu.Unlock()
+
+	if ok {
+		return key, nil
+	}
+	if time.Since(keysAge) < time.Minute {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+
+	meta, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = p.shared(ctx, "jwks", func(ctx context.Context) (interface{}, error) {
+		var set token.JWKS
+		if err := p.getJSON(ctx, meta.JWKSURI, &set); err != nil {
+			return nil, fmt.Errorf("failed to fetch jwks: %w", err)
+		}
+
+		keys := make(map[string]*rsa.PublicKey, len(set.Keys))
+		for _, k := range set.Keys {
+			if k.Kty != "RSA" {
+				continue
+			}
+			key, err := rsaKey(k)
+			if err != nil {
+				return nil, fmt.Errorf("jwks key %s: %w", k.Kid, err)
+			}
+			keys[k.Kid] = key
+		}
+
+		p.mu.Lock()
+		p.keys, p.keysAge = keys, time.Now()
+		p.mu.Unlock()
+		return nil, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	key, ok = p.keys[kid]
+	p.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	return key, nil
+}
+
+func rsaKey(k token.JWK) (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(k.N)
+	if err != nil {
+		return nil, err
+	}
+	e, err := base64.RawURLEncoding.DecodeString(k.E)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(n),
+		E: int(new(big.Int).SetBytes(e).Int64()),
+	}, nil
+}
+
+func (p *Provider) getJSON(ctx context.Context, url string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: %s", url, res.Status)
+	}
+
+	return json.NewDecoder(res.Body).Decode(v)
+}
@@ -0,0 +1,97 @@
+package oidc
+
+import (
+	"auth-service/pkg/oidc/oidctest"
+	"context"
+	"testing"
+)
+
+func newTestProvider(t *testing.T) (*Provider, *oidctest.Server) {
+	t.Helper()
+
+	srv := oidctest.NewServer()
+	t.Cleanup(srv.Close)
+
+	p := NewProvider(Config{
+		Name:         "mock",
+		Issuer:       srv.URL,
+		ClientID:     oidctest.ClientID,
+		ClientSecret: oidctest.ClientSecret,
+		RedirectURL:  "http://localhost:8081/auth/oidc/mock/callback",
+	})
+	return p, srv
+}
+
+// signIn runs the browser part of the flow and returns the code and state the
+// provider sent back.
+func signIn(t *testing.T, p *Provider, srv *oidctest.Server, state, nonce, verifier string) (string, string) {
+	t.Helper()
+
+	authURL, err := p.AuthCodeURL(context.Background(), state, nonce, verifier)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	callback, err := srv.Authorize(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return callback.Query().Get("code"), callback.Query().Get("state")
+}
+
+func TestExchange(t *testing.T) {
+	p, srv := newTestProvider(t)
+	srv.SetUser(oidctest.User{Subject: "sub-1", Email: "ann@example.com", EmailVerified: true, Name: "Ann"})
+
+	verifier := GenerateVerifier()
+	code, state := signIn(t, p, srv, "state-1", "nonce-1", verifier)
+	if state != "state-1" {
+		t.Fatalf("state = %q, want state-1", state)
+	}
+
+	id, err := p.Exchange(context.Background(), code, verifier)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := Identity{Subject: "sub-1", Email: "ann@example.com", EmailVerified: true, Name: "Ann", Nonce: "nonce-1"}
+	if id != want {
+		t.Fatalf("identity = %+v, want %+v", id, want)
+	}
+}
+
+func TestExchangeWrongVerifier(t *testing.T) {
+	p, srv := newTestProvider(t)
+	srv.SetUser(oidctest.User{Subject: "sub-1"})
+
+	code, _ := signIn(t, p, srv, "state", "nonce", GenerateVerifier())
+
+	if _, err := p.Exchange(context.Background(), code, GenerateVerifier()); err == nil {
+		t.Fatal("code redeemed with another verifier")
+	}
+}
+
+func TestExchangeCodeOnce(t *testing.T) {
+	p, srv := newTestProvider(t)
+	srv.SetUser(oidctest.User{Subject: "sub-1"})
+
+	verifier := GenerateVerifier()
+	code, _ := signIn(t, p, srv, "state", "nonce", verifier)
+
+	if _, err := p.Exchange(context.Background(), code, verifier); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.Exchange(context.Background(), code, verifier); err == nil {
+		t.Fatal("code redeemed twice")
+	}
+}
+
+func TestDiscoverWrongIssuer(t *testing.T) {
+	p, srv := newTestProvider(t)
+	p.cfg.Issuer = srv.URL + "/other"
+
+	_, err := p.AuthCodeURL(context.Background(), "state", "nonce", GenerateVerifier())
+	if err == nil {
+		t.Fatal("provider with mismatched issuer accepted")
+	}
+}
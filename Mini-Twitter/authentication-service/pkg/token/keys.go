@@ -0,0 +1,280 @@
+package token
+
+import (
+	"auth-service/pkg/models"
+	"auth-service/storage"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SigningAlgorithm is what access tokens are signed with.
+const SigningAlgorithm = "RS256"
+
+const (
+	rsaKeyBits = 2048
+
+	// PublishAhead is how long a new key is in the JWKS before it signs
+	// anything. Verifiers must not cache the JWKS for longer.
+	PublishAhead = 15 * time.Minute
+
+	// syncInterval is how often keys created by other instances are picked
+	// up and rotation is checked.
+	syncInterval = time.Minute
+)
+
+// ErrNoSigningKey is returned when no key is active yet.
+var ErrNoSigningKey = errors.New("no active signing key")
+
+type signingKey struct {
+	id         string
+	private    *rsa.PrivateKey
+	activeFrom time.Time
+}
+
+// KeySet holds the RSA keys access tokens are signed with. The newest active
+// key signs; every key that may still have valid tokens is published in the
+// JWKS. Keys live in storage, so all instances share them.
+type KeySet struct {
+	store       storage.SigningKeyStorage
+	rotateEvery time.Duration
+	log         *slog.Logger
+	now         func() time.Time
+
+	mu sync.RWMutex
+	// keys are ordered by activeFrom, then id.
+	keys []signingKey
+}
+
+func NewKeySet(store storage.SigningKeyStorage, rotateEvery time.Duration, logger *slog.Logger) *KeySet {
+	return &KeySet{
+		store:       store,
+		rotateEvery: rotateEvery,
+		log:         logger,
+		now:         time.Now,
+	}
+}
+
+// Sync reloads the keys from storage, adds the next key when the newest one
+// is due for rotation and deletes the keys whose tokens have all expired.
+func (k *KeySet) Sync(ctx context.Context) error {
+	keys, err := k.load(ctx)
+	if err != nil {
+		return err
+	}
+	now := k.now()
+
+	var next time.Time
+	switch {
+	case len(keys) == 0:
+		// Nothing can have cached a key yet, so the first one signs at once.
+		next = now
+	case !keys[len(keys)-1].activeFrom.Add(k.rotateEvery - PublishAhead).After(now):
+		next = keys[len(keys)-1].activeFrom.Add(k.rotateEvery)
+		if earliest := now.Add(PublishAhead); next.Before(earliest) {
+			next = earliest
+		}
+	}
+	if !next.IsZero() {
+		var latest time.Time
+		if len(keys) > 0 {
+			latest = keys[len(keys)-1].activeFrom
+		}
+		key, added, err := k.create(ctx, next, latest)
+		if err != nil {
+			return err
+		}
+		if added {
+			keys = append(keys, key)
+			sortKeys(keys)
+		} else if keys, err = k.load(ctx); err != nil {
+			// Another instance added the key first; sign with theirs.
+			return err
+		}
+	}
+
+	// A key is retired once its successor has signed for longer than an
+	// access token lives.
+	kept := keys[:0]
+	for i, key := range keys {
+		if i+1 < len(keys) && keys[i+1].activeFrom.Add(AccessTokenTTL).Before(now) {
+			if err := k.store.DeleteSigningKey(ctx, key.id); err != nil {
+				return err
+			}
+			k.log.Info("Retired signing key", "kid", key.id)
+			continue
+		}
+		kept = append(kept, key)
+	}
+
+	k.mu.Lock()
+	k.keys = kept
+	k.mu.Unlock()
+	return nil
+}
+
+// Run syncs the keys until ctx is done.
+func (k *KeySet) Run(ctx context.Context) {
+	ticker := time.NewTicker(syncInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := k.Sync(ctx); err != nil {
+				k.log.Error("Failed to sync signing keys", "error", err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// current returns the newest key that is allowed to sign.
+func (k *KeySet) current() (signingKey, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	now := k.now()
+	for i := len(k.keys) - 1; i >= 0; i-- {
+		if !k.keys[i].activeFrom.After(now) {
+			return k.keys[i], nil
+		}
+	}
+	return signingKey{}, ErrNoSigningKey
+}
+
+// PublicKey returns the public key with the given kid, if it is published.
+func (k *KeySet) PublicKey(kid string) (*rsa.PublicKey, bool) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	for _, key := range k.keys {
+		if key.id == kid {
+			return &key.private.PublicKey, true
+		}
+	}
+	return nil, false
+}
+
+// JWK is an RSA public key in JSON Web Key form (RFC 7517).
+type JWK struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns every published key, including the next one before it signs.
+func (k *KeySet) JWKS() JWKS {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	res := JWKS{Keys: make([]JWK, 0, len(k.keys))}
+	for _, key := range k.keys {
+		pub := key.private.PublicKey
+		res.Keys = append(res.Keys, JWK{
+			Kty: "RSA",
+			Use: "sig",
+			Alg: SigningAlgorithm,
+			Kid: key.id,
+			N:   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		})
+	}
+	return res
+}
+
+func (k *KeySet) load(ctx context.Context) ([]signingKey, error) {
+	stored, err := k.store.ListSigningKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]signingKey, 0, len(stored))
+	for _, s := range stored {
+		if s.Algorithm != SigningAlgorithm {
+			k.log.Warn("Skipping signing key with unsupported algorithm", "kid", s.ID, "algorithm", s.Algorithm)
+			continue
+		}
+		private, err := parsePrivateKey(s.PrivateKey)
+		if err != nil {
+			return nil, fmt.Errorf("signing key %s: %w", s.ID, err)
+		}
+		keys = append(keys, signingKey{id: s.ID, private: private, activeFrom: s.ActiveFrom})
+	}
+	sortKeys(keys)
+	return keys, nil
+}
+
+// create adds a key that signs from activeFrom, unless another instance added
+// one after latest in the meantime.
+func (k *KeySet) create(ctx context.Context, activeFrom, latest time.Time) (signingKey, bool, error) {
+	private, err := rsa.GenerateKey(rand.Reader, rsaKeyBits)
+	if err != nil {
+		return signingKey{}, false, err
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return signingKey{}, false, err
+	}
+
+	key := signingKey{id: uuid.NewString(), private: private, activeFrom: activeFrom}
+	added, err := k.store.AddSigningKey(ctx, models.SigningKey{
+		ID:         key.id,
+		Algorithm:  SigningAlgorithm,
+		PrivateKey: string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		ActiveFrom: activeFrom,
+	}, latest)
+	if err != nil || !added {
+		return signingKey{}, false, err
+	}
+
+	k.log.Info("Created signing key", "kid", key.id, "active_from", activeFrom)
+	return key, true, nil
+}
+
+func parsePrivateKey(data string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	private, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("not an RSA key")
+	}
+	return private, nil
+}
+
+func sortKeys(keys []signingKey) {
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].activeFrom.Equal(keys[j].activeFrom) {
+			return keys[i].id < keys[j].id
+		}
+		return keys[i].activeFrom.Before(keys[j].activeFrom)
+	})
+}
@@ -0,0 +1,201 @@
+package token
+
+import (
+	"auth-service/pkg/models"
+	"context"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+)
+
+type memoryKeys struct {
+	mu   sync.Mutex
+	keys []models.SigningKey
+}
+
+func (m *memoryKeys) ListSigningKeys(ctx context.Context) ([]models.SigningKey, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]models.SigningKey(nil), m.keys...), nil
+}
+
+func (m *memoryKeys) AddSigningKey(ctx context.Context, key models.SigningKey, latest time.Time) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, k := range m.keys {
+		if k.ActiveFrom.After(latest) {
+			return false, nil
+		}
+	}
+	m.keys = append(m.keys, key)
+	return true, nil
+}
+
+func (m *memoryKeys) DeleteSigningKey(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, key := range m.keys {
+		if key.ID == id {
+			m.keys = append(m.keys[:i], m.keys[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func newTestKeySet(store *memoryKeys, now *time.Time) *KeySet {
+	ks := NewKeySet(store, 24*time.Hour, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	ks.now = func() time.Time { return *now }
+	return ks
+}
+
+func TestKeySetRotation(t *testing.T) {
+	ctx := context.Background()
+	store := &memoryKeys{}
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	ks := newTestKeySet(store, &now)
+
+	// The first key signs at once.
+	if err := ks.Sync(ctx); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	first, err := ks.current()
+	if err != nil {
+		t.Fatalf("current: %v", err)
+	}
+	if len(ks.JWKS().Keys) != 1 {
+		t.Fatalf("published %d keys, want 1", len(ks.JWKS().Keys))
+	}
+
+	// Shortly before rotation the next key is published, but does not sign.
+	now = now.Add(24*time.Hour - PublishAhead)
+	if err := ks.Sync(ctx); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	if got := len(ks.JWKS().Keys); got != 2 {
+		t.Fatalf("published %d keys, want 2", got)
+	}
+	if key, _ := ks.current(); key.id != first.id {
+		t.Errorf("next key signs before it is due")
+	}
+
+	// Once due it signs, and the old key stays published for its tokens.
+	now = now.Add(PublishAhead)
+	if err := ks.Sync(ctx); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	second, _ := ks.current()
+	if second.id == first.id {
+		t.Fatalf("key was not rotated")
+	}
+	if _, ok := ks.PublicKey(first.id); !ok {
+		t.Errorf("old key was retired while its tokens are valid")
+	}
+
+	// After the last token of the old key expired it is retired.
+	now = now.Add(AccessTokenTTL + time.Second)
+	if err := ks.Sync(ctx); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	if _, ok := ks.PublicKey(first.id); ok {
+		t.Errorf("old key is still published")
+	}
+	if len(store.keys) != 1 {
+		t.Errorf("store has %d keys, want 1", len(store.keys))
+	}
+}
+
+func TestKeySetSharesKeysThroughStore(t *testing.T) {
+	ctx := context.Background()
+	store := &memoryKeys{}
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	a := newTestKeySet(store, &now)
+	if err := a.Sync(ctx); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	b := newTestKeySet(store, &now)
+	if err := b.Sync(ctx); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+
+	ka, _ := a.current()
+	kb, _ := b.current()
+	if ka.id != kb.id {
+		t.Errorf("instances sign with different keys: %s and %s", ka.id, kb.id)
+	}
+	if len(store.keys) != 1 {
+		t.Errorf("store has %d keys, want 1", len(store.keys))
+	}
+}
+
+func TestKeySetConcurrentFirstSync(t *testing.T) {
+	ctx := context.Background()
+	store := &memoryKeys{}
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	sets := make([]*KeySet, 4)
+	for i := range sets {
+		sets[i] = newTestKeySet(store, &now)
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(sets))
+	for i, ks := range sets {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = ks.Sync(ctx)
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			t.Fatalf("Sync: %v", err)
+		}
+	}
+	if len(store.keys) != 1 {
+		t.Fatalf("store has %d keys, want 1", len(store.keys))
+	}
+	for _, ks := range sets {
+		key, err := ks.current()
+		if err != nil {
+			t.Fatalf("current: %v", err)
+		}
+		if key.id != store.keys[0].ID {
+			t.Errorf("instance signs with %s, want %s", key.id, store.keys[0].ID)
+		}
+	}
+}
+
+func TestAccessTokenRoundTrip(t *testing.T) {
+	store := &memoryKeys{}
+	now := time.Now()
+	ks := newTestKeySet(store, &now)
+	if err := ks.Sync(context.Background()); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	UseKeySet(ks)
+	defer UseKeySet(nil)
+
+	str, err := GenerateAccessToken(models.LoginResponse{Id: "user-1", Role: "user"}, "family-1")
+	if err != nil {
+		t.Fatalf("GenerateAccessToken: %v", err)
+	}
+
+	claims, err := ExtractClaimsAccess(str)
+	if err != nil {
+		t.Fatalf("ExtractClaimsAccess: %v", err)
+	}
+	if claims.ID != "user-1" || claims.Role != "user" {
+		t.Errorf("unexpected claims: %+v", claims)
+	}
+
+	jwks := ks.JWKS()
+	if len(jwks.Keys) != 1 || jwks.Keys[0].Alg != "RS256" || jwks.Keys[0].E != "AQAB" {
+		t.Errorf("unexpected JWKS: %+v", jwks)
+	}
+}
@@ -0,0 +1,108 @@
+package token
+
+import (
+	"auth-service/pkg/models"
+	"auth-service/storage"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// sealedPrefix marks private keys encrypted by SealKeys.
+const sealedPrefix = "sealed:v1:"
+
+var (
+	// ErrNoKeySecret is returned by SealKeys without a secret to seal with.
+	ErrNoKeySecret = errors.New("no signing key secret configured")
+	// ErrKeyNotSealed is returned for a private key stored in the clear,
+	// which SealKeys never writes. Delete the key so a sealed one replaces it.
+	ErrKeyNotSealed = errors.New("private key is stored in plaintext")
+)
+
+// sealedKeys encrypts private keys with AES-GCM on their way into the store,
+// so a dump of the database does not hand out the keys tokens are signed
+// with.
+type sealedKeys struct {
+	store storage.SigningKeyStorage
+	aead  cipher.AEAD
+}
+
+// SealKeys wraps store so private keys are encrypted under a key derived from
+// secret, which must be long and random and shared by all instances.
+func SealKeys(store storage.SigningKeyStorage, secret string) (storage.SigningKeyStorage, error) {
+	if secret == "" {
+		return nil, ErrNoKeySecret
+	}
+
+	sum := sha256.Sum256([]byte(secret))
+	block, err := aes.NewCipher(sum[:])
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sealedKeys{store: store, aead: aead}, nil
+}
+
+func (s *sealedKeys) ListSigningKeys(ctx context.Context) ([]models.SigningKey, error) {
+	keys, err := s.store.ListSigningKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, key := range keys {
+		private, err := s.open(key)
+		if err != nil {
+			return nil, fmt.Errorf("signing key %s: %w", key.ID, err)
+		}
+		keys[i].PrivateKey = private
+	}
+	return keys, nil
+}
+
+func (s *sealedKeys) AddSigningKey(ctx context.Context, key models.SigningKey, latest time.Time) (bool, error) {
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return false, err
+	}
+	// The kid is authenticated too, so sealed keys cannot be swapped.
+	sealed := s.aead.Seal(nonce, nonce, []byte(key.PrivateKey), []byte(key.ID))
+	key.PrivateKey = sealedPrefix + base64.StdEncoding.EncodeToString(sealed)
+
+	return s.store.AddSigningKey(ctx, key, latest)
+}
+
+func (s *sealedKeys) DeleteSigningKey(ctx context.Context, id string) error {
+	return s.store.DeleteSigningKey(ctx, id)
+}
+
+func (s *sealedKeys) open(key models.SigningKey) (string, error) {
+	encoded, ok := strings.CutPrefix(key.PrivateKey, sealedPrefix)
+	if !ok {
+		return "", ErrKeyNotSealed
+	}
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < s.aead.NonceSize() {
+		return "", errors.New("sealed key is too short")
+	}
+
+	nonce, ciphertext := sealed[:s.aead.NonceSize()], sealed[s.aead.NonceSize():]
+	private, err := s.aead.Open(nil, nonce, ciphertext, []byte(key.ID))
+	if err != nil {
+		return "", errors.New("cannot decrypt, is SIGNING_KEY_SECRET right?")
+	}
+	return string(private), nil
+}
@@ -0,0 +1,85 @@
+package token
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSealKeysRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	store := &memoryKeys{}
+	sealed, err := SealKeys(store, "secret")
+	if err != nil {
+		t.Fatalf("SealKeys: %v", err)
+	}
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	ks := newTestKeySet(store, &now)
+	ks.store = sealed
+	if err := ks.Sync(ctx); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+
+	stored := store.keys[0].PrivateKey
+	if !strings.HasPrefix(stored, sealedPrefix) || strings.Contains(stored, "PRIVATE KEY") {
+		t.Fatalf("private key stored in the clear: %.40q", stored)
+	}
+
+	keys, err := sealed.ListSigningKeys(ctx)
+	if err != nil {
+		t.Fatalf("ListSigningKeys: %v", err)
+	}
+	if !strings.Contains(keys[0].PrivateKey, "PRIVATE KEY") {
+		t.Errorf("listed key is not PEM")
+	}
+
+	// Another instance with the same secret signs with the same key.
+	other := newTestKeySet(store, &now)
+	other.store = sealed
+	if err := other.Sync(ctx); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	if len(store.keys) != 1 {
+		t.Errorf("store has %d keys, want 1", len(store.keys))
+	}
+}
+
+func TestSealKeysWrongSecret(t *testing.T) {
+	ctx := context.Background()
+	store := &memoryKeys{}
+	sealed, _ := SealKeys(store, "secret")
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	ks := newTestKeySet(store, &now)
+	ks.store = sealed
+	if err := ks.Sync(ctx); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+
+	wrong, _ := SealKeys(store, "other")
+	if _, err := wrong.ListSigningKeys(ctx); err == nil {
+		t.Error("ListSigningKeys with the wrong secret succeeded")
+	}
+}
+
+func TestSealKeysRefusesPlaintext(t *testing.T) {
+	ctx := context.Background()
+	store := &memoryKeys{}
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	if err := newTestKeySet(store, &now).Sync(ctx); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+
+	sealed, _ := SealKeys(store, "secret")
+	if _, err := sealed.ListSigningKeys(ctx); !errors.Is(err, ErrKeyNotSealed) {
+		t.Errorf("ListSigningKeys with a plaintext key: %v, want ErrKeyNotSealed", err)
+	}
+}
+
+func TestSealKeysNeedsSecret(t *testing.T) {
+	if _, err := SealKeys(&memoryKeys{}, ""); !errors.Is(err, ErrNoKeySecret) {
+		t.Errorf("SealKeys without a secret: %v, want ErrNoKeySecret", err)
+	}
+}
@@ -3,33 +3,85 @@ package token
 import (
 	"auth-service/pkg/config"
 	"auth-service/pkg/models"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/dgrijalva/jwt-go"
+	"github.com/google/uuid"
 )
 
+// AccessTokenTTL is how long an access token is valid unless it is revoked.
+const AccessTokenTTL = 10 * time.Hour
+
+// RefreshTokenTTL is how long a refresh token, and the family it belongs to,
+// stays valid.
+const RefreshTokenTTL = 12 * time.Hour
+
+// keys signs and verifies access tokens. Refresh tokens never leave
+// auth-service, so they keep the REFRESH_TOKEN secret.
+var keys *KeySet
+
+// UseKeySet makes ks sign and verify access tokens. Call it once at startup,
+// after the first Sync.
+func UseKeySet(ks *KeySet) {
+	keys = ks
+}
+
 type Claims struct {
 	ID       string `json:"user_id"`
 	Role     string `json:"role"`
 	Email    string `json:"email"`
 	Username string `json:"username"`
+	// FamilyID groups the refresh tokens issued by rotation from one login.
+	// Access tokens carry it too, naming the session they belong to.
+	FamilyID string `json:"fid,omitempty"`
+	// IssuedAtMs is iat in milliseconds. iat alone cannot tell a token
+	// issued just after a revocation cutoff from one issued in the same
+	// second before it.
+	IssuedAtMs int64 `json:"iat_ms,omitempty"`
 	jwt.StandardClaims
 }
 
-func GenerateAccessToken(in models.LoginResponse) (string, error) {
+// IssuedBefore reports whether the token was issued before t. Tokens without
+// iat_ms count as issued at the start of their iat second.
+func (c *Claims) IssuedBefore(t time.Time) bool {
+	issued := c.IssuedAtMs
+	if issued == 0 {
+		issued = c.IssuedAt * 1000
+	}
+	return issued < t.UnixMilli()
+}
+
+// GenerateAccessToken issues an access token for the session familyID.
+func GenerateAccessToken(in models.LoginResponse, familyID string) (string, error) {
+	now := time.Now()
 	claims := Claims{
-		ID:       in.Id,
-		Role:     in.Role,
-		Email:    in.Email,
-		Username: in.Username,
+		ID:         in.Id,
+		Role:       in.Role,
+		Email:      in.Email,
+		Username:   in.Username,
+		FamilyID:   familyID,
+		IssuedAtMs: now.UnixMilli(),
 		StandardClaims: jwt.StandardClaims{
-			IssuedAt:  time.Now().Unix(),
-			ExpiresAt: time.Now().Add(time.Hour * 10).Unix(),
+			// The jti lets a single access token be put on the denylist.
+			Id:        uuid.NewString(),
+			IssuedAt:  now.Unix(),
+			ExpiresAt: now.Add(AccessTokenTTL).Unix(),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	str, err := token.SignedString([]byte(config.Load().ACCES_TOKEN))
+	if keys == nil {
+		return "", ErrNoSigningKey
+	}
+	key, err := keys.current()
+	if err != nil {
+		return "", err
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = key.id
+	str, err := token.SignedString(key.private)
 	if err != nil {
 		return "", err
 	}
@@ -37,15 +89,21 @@ func GenerateAccessToken(in models.LoginResponse) (string, error) {
 	return str, nil
 }
 
-func GenerateRefreshToken(in models.LoginResponse) (string, error) {
+// GenerateRefreshToken issues a refresh token of the given family. tokenID
+// becomes the jti claim, which is what rotation keys on.
+func GenerateRefreshToken(in models.LoginResponse, familyID, tokenID string) (string, error) {
+	now := time.Now()
 	claims := Claims{
-		ID:       in.Id,
-		Role:     in.Role,
-		Email:    in.Email,
-		Username: in.Username,
+		ID:         in.Id,
+		Role:       in.Role,
+		Email:      in.Email,
+		Username:   in.Username,
+		FamilyID:   familyID,
+		IssuedAtMs: now.UnixMilli(),
 		StandardClaims: jwt.StandardClaims{
-			IssuedAt:  time.Now().Unix(),
-			ExpiresAt: time.Now().Add(time.Hour * 12).Unix(),
+			Id:        tokenID,
+			IssuedAt:  now.Unix(),
+			ExpiresAt: now.Add(RefreshTokenTTL).Unix(),
 		},
 	}
 
@@ -59,42 +117,42 @@ func GenerateRefreshToken(in models.LoginResponse) (string, error) {
 }
 
 func ExtractClaimsRefresh(tokenString string) (*Claims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
+	return extractClaims(tokenString, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
 		return []byte(config.Load().REFRESH_TOKEN), nil
 	})
-
-	if err != nil {
-		return nil, err
-	}
-
-	if !token.Valid {
-		return nil, err
-	}
-
-	claims, ok := token.Claims.(*Claims)
-	if !ok {
-		return nil, err
-	}
-
-	return claims, nil
 }
 
+// ExtractClaimsAccess verifies an access token against the published key
+// named by its kid header.
 func ExtractClaimsAccess(tokenString string) (*Claims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(config.Load().ACCES_TOKEN), nil
+	return extractClaims(tokenString, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		if keys == nil {
+			return nil, ErrNoSigningKey
+		}
+		kid, _ := token.Header["kid"].(string)
+		key, ok := keys.PublicKey(kid)
+		if !ok {
+			return nil, fmt.Errorf("unknown signing key %q", kid)
+		}
+		return key, nil
 	})
+}
 
+func extractClaims(tokenString string, keyFunc jwt.Keyfunc) (*Claims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, keyFunc)
 	if err != nil {
 		return nil, err
 	}
 
-	if !token.Valid {
-		return nil, err
-	}
-
 	claims, ok := token.Claims.(*Claims)
-	if !ok {
-		return nil, err
+	if !ok || !token.Valid {
+		return nil, errors.New("invalid token")
 	}
 
 	return claims, nil
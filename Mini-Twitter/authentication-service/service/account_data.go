@@ -0,0 +1,112 @@
+package service
+
+import (
+	pb "auth-service/genproto/user"
+	"auth-service/storage"
+	"context"
+	"errors"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// deletionInterval is how often due account deletions are looked for.
+	deletionInterval = 10 * time.Minute
+	deletionBatch    = 100
+)
+
+// RequestDataExport returns everything auth-service keeps about the user
+// that they can see themselves. The api-gateway bundles it with their tweets.
+func (us *UserService) RequestDataExport(ctx context.Context, in *pb.Id) (*pb.UserDataExport, error) {
+	profile, err := us.st.GetProfile(in)
+	if err != nil {
+		us.log.Error("failed to get profile for export", "error", err)
+		return nil, err
+	}
+
+	followers, err := us.st.ListOfFollowers(in)
+	if err != nil {
+		us.log.Error("failed to list followers for export", "error", err)
+		return nil, err
+	}
+
+	following, err := us.st.ListOfFollowing(in)
+	if err != nil {
+		us.log.Error("failed to list following for export", "error", err)
+		return nil, err
+	}
+
+	return &pb.UserDataExport{
+		Profile:    profile,
+		Followers:  followers.Followers,
+		Following:  following.Following,
+		ExportedAt: time.Now().UTC().Format(time.RFC3339),
+	}, nil
+}
+
+// RequestDeletion schedules the account to be deleted once the grace period
+// is over. The user stays signed in meanwhile, so they can change their mind.
+func (us *UserService) RequestDeletion(ctx context.Context, in *pb.Id) (*pb.DeletionStatus, error) {
+	at, err := us.st.ScheduleDeletion(ctx, in.UserId, time.Now().Add(us.deletionGrace))
+	if err != nil {
+		us.log.Error("failed to schedule account deletion", "error", err)
+		return nil, err
+	}
+
+	us.log.Info("Account deletion scheduled", "user_id", in.UserId, "delete_after", at)
+	return &pb.DeletionStatus{UserId: in.UserId, DeleteAfter: at.UTC().Format(time.RFC3339)}, nil
+}
+
+func (us *UserService) CancelDeletion(ctx context.Context, in *pb.Id) (*pb.DeletionStatus, error) {
+	err := us.st.CancelDeletion(ctx, in.UserId)
+	if errors.Is(err, storage.ErrDeletionNotScheduled) {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	if err != nil {
+		us.log.Error("failed to cancel account deletion", "error", err)
+		return nil, err
+	}
+
+	us.log.Info("Account deletion cancelled", "user_id", in.UserId)
+	return &pb.DeletionStatus{UserId: in.UserId}, nil
+}
+
+// RunDeletions purges accounts whose grace period is over until ctx is done.
+func (us *UserService) RunDeletions(ctx context.Context) {
+	ticker := time.NewTicker(deletionInterval)
+	defer ticker.Stop()
+
+	for {
+		us.purgeDue(ctx)
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (us *UserService) purgeDue(ctx context.Context) {
+	ids, err := us.st.DueDeletions(ctx, deletionBatch)
+	if err != nil {
+		us.log.Error("failed to list due account deletions", "error", err)
+		return
+	}
+
+	for _, id := range ids {
+		purged, err := us.st.PurgeUser(ctx, id)
+		if err != nil {
+			us.log.Error("failed to purge account", "user_id", id, "error", err)
+			continue
+		}
+		if !purged {
+			continue
+		}
+
+		revokeUser(ctx, us.revocations, us.log, id)
+		us.log.Info("Account purged", "user_id", id)
+	}
+}
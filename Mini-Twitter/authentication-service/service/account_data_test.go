@@ -0,0 +1,70 @@
+package service
+
+import (
+	pb "auth-service/genproto/user"
+	"context"
+	"testing"
+	"time"
+)
+
+func TestPurgeDue(t *testing.T) {
+	tu := newTestUsers(t)
+	tu.users.due = []string{"u1", "u2", "u3"}
+	tu.users.failing["u2"] = true
+	tu.users.cancelled["u3"] = true
+
+	tu.purgeDue(context.Background())
+
+	if len(tu.users.purged) != 1 || tu.users.purged[0] != "u1" {
+		t.Fatalf("purged %v, want [u1]", tu.users.purged)
+	}
+	if !tu.revoked(t, "u1") {
+		t.Error("tokens of the purged user were not revoked")
+	}
+	for _, id := range []string{"u2", "u3"} {
+		if tu.revoked(t, id) {
+			t.Errorf("tokens of %s were revoked, though it was not purged", id)
+		}
+	}
+	// A failed purge is tried again on the next pass.
+	if len(tu.users.due) != 1 || tu.users.due[0] != "u2" {
+		t.Errorf("still due %v, want [u2]", tu.users.due)
+	}
+}
+
+func TestRunDeletionsStopsWithContext(t *testing.T) {
+	tu := newTestUsers(t)
+	tu.users.due = []string{"u1"}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	done := make(chan struct{})
+	go func() {
+		tu.RunDeletions(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("RunDeletions did not stop when the context was done")
+	}
+	// It purges once at start, not only after the first interval.
+	if len(tu.users.purged) != 1 {
+		t.Errorf("purged %v, want [u1]", tu.users.purged)
+	}
+}
+
+func TestDeleteUserRevokes(t *testing.T) {
+	tu := newTestUsers(t)
+
+	if _, err := tu.DeleteUser(context.Background(), &pb.Id{UserId: "u1"}); err != nil {
+		t.Fatalf("DeleteUser: %v", err)
+	}
+	if !tu.users.deleted["u1"] {
+		t.Error("user was not deleted")
+	}
+	if !tu.revoked(t, "u1") {
+		t.Error("tokens of the deleted user were not revoked")
+	}
+}
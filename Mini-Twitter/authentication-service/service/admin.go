@@ -0,0 +1,132 @@
+package service
+
+import (
+	pb "auth-service/genproto/user"
+	"auth-service/pkg/token"
+	"context"
+	"database/sql"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Roles of the role enum. c-admin is above admin and is the only role that
+// can grant or take c-admin.
+const (
+	RoleUser   = "user"
+	RoleAdmin  = "admin"
+	RoleCAdmin = "c-admin"
+)
+
+var roleRank = map[string]int{RoleUser: 0, RoleAdmin: 1, RoleCAdmin: 2}
+
+const (
+	auditPageSize    = 50
+	maxAuditPageSize = 100
+)
+
+// SetRole promotes or demotes a user. The actor must be an admin and may only
+// manage roles up to their own. The user's tokens carry the old role, so they
+// are revoked and the user signs in again.
+func (us *UserService) SetRole(ctx context.Context, in *pb.SetRoleReq) (*pb.UserResponse, error) {
+	target, ok := roleRank[in.Role]
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown role %q", in.Role)
+	}
+	if in.ActorId == in.UserId {
+		return nil, status.Error(codes.PermissionDenied, "cannot change your own role")
+	}
+
+	actor, err := us.adminRank(ctx, in.ActorId)
+	if err != nil {
+		return nil, err
+	}
+	current, err := us.st.UserRole(ctx, in.UserId)
+	if err != nil {
+		us.log.Error("failed to get user role", "error", err)
+		return nil, err
+	}
+	if target > actor || roleRank[current] > actor {
+		return nil, status.Error(codes.PermissionDenied, "cannot manage a role above your own")
+	}
+
+	res, err := us.st.SetRole(ctx, in)
+	if err != nil {
+		us.log.Error("failed to set role", "error", err)
+		return nil, err
+	}
+
+	// The role is changed either way; revokeUser retries and logs.
+	revokeUser(ctx, us.revocations, us.log, in.UserId)
+	us.log.Info("Role changed", "actor_id", in.ActorId, "user_id", in.UserId, "from", current, "to", in.Role)
+	return res, nil
+}
+
+// SetVerified grants or takes the verified badge of a user.
+func (us *UserService) SetVerified(ctx context.Context, in *pb.SetVerifiedReq) (*pb.UserResponse, error) {
+	if _, err := us.adminRank(ctx, in.ActorId); err != nil {
+		return nil, err
+	}
+
+	res, err := us.st.SetVerified(ctx, in)
+	if err != nil {
+		us.log.Error("failed to set verified", "error", err)
+		return nil, err
+	}
+	return res, nil
+}
+
+func (us *UserService) ListAuditLog(ctx context.Context, in *pb.AuditFilter) (*pb.AuditEntries, error) {
+	if in.Limit <= 0 {
+		in.Limit = auditPageSize
+	}
+	if in.Limit > maxAuditPageSize {
+		in.Limit = maxAuditPageSize
+	}
+	if in.Page <= 0 {
+		in.Page = 1
+	}
+
+	res, err := us.st.ListAuditLog(ctx, in)
+	if err != nil {
+		us.log.Error("failed to list audit log", "error", err)
+		return nil, err
+	}
+	return res, nil
+}
+
+// adminRank returns the rank of the actor's role, which must be an admin one.
+// The gateway checks this too, but the role in a token may be stale.
+func (us *UserService) adminRank(ctx context.Context, actorID string) (int, error) {
+	role, err := us.st.UserRole(ctx, actorID)
+	if err != nil {
+		us.log.Error("failed to get actor role", "error", err)
+		return 0, err
+	}
+
+	rank := roleRank[role]
+	if rank < roleRank[RoleAdmin] {
+		return 0, status.Error(codes.PermissionDenied, "admin role required")
+	}
+	return rank, nil
+}
+
+// adminRank returns the rank of the caller's current role, which must be an
+// admin one. The role in the token may be stale.
+func (a *authService) adminRank(claims *token.Claims) (int, error) {
+	actor, err := a.st.UserByID(claims.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, ErrForbidden
+	}
+	if err != nil {
+		a.log.Error("Failed to get caller role", "error", err)
+		return 0, err
+	}
+
+	rank := roleRank[actor.Role]
+	if rank < roleRank[RoleAdmin] {
+		return 0, ErrForbidden
+	}
+	return rank, nil
+}
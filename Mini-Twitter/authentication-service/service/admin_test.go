@@ -0,0 +1,134 @@
+package service
+
+import (
+	pb "auth-service/genproto/user"
+	"common/interceptor"
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestSetRole(t *testing.T) {
+	tests := []struct {
+		name   string
+		actor  string
+		target string
+		role   string
+		want   codes.Code
+	}{
+		{name: "admin promotes user", actor: RoleAdmin, target: RoleUser, role: RoleAdmin, want: codes.OK},
+		{name: "admin demotes admin", actor: RoleAdmin, target: RoleAdmin, role: RoleUser, want: codes.OK},
+		{name: "admin cannot grant c-admin", actor: RoleAdmin, target: RoleUser, role: RoleCAdmin, want: codes.PermissionDenied},
+		{name: "admin cannot demote c-admin", actor: RoleAdmin, target: RoleCAdmin, role: RoleUser, want: codes.PermissionDenied},
+		{name: "c-admin grants c-admin", actor: RoleCAdmin, target: RoleAdmin, role: RoleCAdmin, want: codes.OK},
+		{name: "c-admin demotes c-admin", actor: RoleCAdmin, target: RoleCAdmin, role: RoleAdmin, want: codes.OK},
+		{name: "user cannot manage roles", actor: RoleUser, target: RoleUser, role: RoleUser, want: codes.PermissionDenied},
+		{name: "unknown role", actor: RoleCAdmin, target: RoleUser, role: "root", want: codes.InvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tu := newTestUsers(t)
+			tu.users.roles["actor"] = tt.actor
+			tu.users.roles["target"] = tt.target
+
+			_, err := tu.SetRole(context.Background(), &pb.SetRoleReq{ActorId: "actor", UserId: "target", Role: tt.role})
+			if got := status.Code(err); got != tt.want {
+				t.Fatalf("SetRole: got %v (%v), want %v", got, err, tt.want)
+			}
+
+			want := tt.target
+			if tt.want == codes.OK {
+				want = tt.role
+			}
+			if tu.users.roles["target"] != want {
+				t.Errorf("target role is %s, want %s", tu.users.roles["target"], want)
+			}
+			if revoked := tu.revoked(t, "target"); revoked != (tt.want == codes.OK) {
+				t.Errorf("tokens revoked: %v, want %v", revoked, tt.want == codes.OK)
+			}
+		})
+	}
+}
+
+func TestSetRoleSelf(t *testing.T) {
+	tu := newTestUsers(t)
+	tu.users.roles["actor"] = RoleCAdmin
+
+	_, err := tu.SetRole(context.Background(), &pb.SetRoleReq{ActorId: "actor", UserId: "actor", Role: RoleUser})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("SetRole on self: got %v, want PermissionDenied", err)
+	}
+	if tu.users.roles["actor"] != RoleCAdmin {
+		t.Errorf("own role changed to %s", tu.users.roles["actor"])
+	}
+}
+
+func TestSetRoleRevocationFails(t *testing.T) {
+	tu := newTestUsers(t)
+	tu.users.roles["actor"] = RoleAdmin
+	tu.users.roles["target"] = RoleUser
+	tu.mr.Close()
+
+	// The role is committed already, so the call succeeds.
+	if _, err := tu.SetRole(context.Background(), &pb.SetRoleReq{ActorId: "actor", UserId: "target", Role: RoleAdmin}); err != nil {
+		t.Fatalf("SetRole: %v", err)
+	}
+	if tu.users.roles["target"] != RoleAdmin {
+		t.Errorf("target role is %s, want admin", tu.users.roles["target"])
+	}
+}
+
+func TestListAuditLogPageSize(t *testing.T) {
+	tests := []struct {
+		limit, want int32
+	}{
+		{0, auditPageSize},
+		{10, 10},
+		{10000, maxAuditPageSize},
+	}
+
+	for _, tt := range tests {
+		tu := newTestUsers(t)
+		if _, err := tu.ListAuditLog(context.Background(), &pb.AuditFilter{Limit: tt.limit}); err != nil {
+			t.Fatalf("ListAuditLog: %v", err)
+		}
+		if tu.users.audit.Limit != tt.want {
+			t.Errorf("limit %d: listed %d, want %d", tt.limit, tu.users.audit.Limit, tt.want)
+		}
+	}
+}
+
+func TestFetchUsersHidesEmails(t *testing.T) {
+	tu := newTestUsers(t)
+	tu.users.roles["ann"] = RoleUser
+	tu.users.roles["bob"] = RoleAdmin
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		emails bool
+	}{
+		{"user", interceptor.WithCaller(context.Background(), interceptor.Caller{UserID: "ann", Role: RoleUser}), false},
+		{"admin", interceptor.WithCaller(context.Background(), interceptor.Caller{UserID: "bob", Role: RoleAdmin}), true},
+		{"no caller", context.Background(), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := tu.FetchUsers(tt.ctx, &pb.Filter{})
+			if err != nil {
+				t.Fatalf("FetchUsers: %v", err)
+			}
+			if len(res.Users) != 2 {
+				t.Fatalf("%d users, want 2", len(res.Users))
+			}
+			for _, user := range res.Users {
+				if (user.Email != "") != tt.emails {
+					t.Errorf("user %s has email %q, want shown: %v", user.Id, user.Email, tt.emails)
+				}
+			}
+		})
+	}
+}
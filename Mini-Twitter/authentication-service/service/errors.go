@@ -0,0 +1,32 @@
+package service
+
+import (
+	"auth-service/storage"
+	"common/interceptor"
+
+	"google.golang.org/grpc/codes"
+)
+
+// errorCodes are the status codes of the errors UserService methods return
+// for the caller to act on.
+var errorCodes = map[error]codes.Code{
+	storage.ErrUserNotFound:         codes.NotFound,
+	storage.ErrNotFollowing:         codes.NotFound,
+	storage.ErrNotBlocked:           codes.NotFound,
+	storage.ErrSessionNotFound:      codes.NotFound,
+	ErrSessionNotFound:              codes.NotFound,
+	storage.ErrUsernameTaken:        codes.AlreadyExists,
+	storage.ErrTwoFactorEnabled:     codes.AlreadyExists,
+	storage.ErrWrongPassword:        codes.PermissionDenied,
+	storage.ErrCannotFollow:         codes.PermissionDenied,
+	ErrForbidden:                    codes.PermissionDenied,
+	storage.ErrDeletionNotScheduled: codes.FailedPrecondition,
+	ErrTwoFactorNotEnrolled:         codes.FailedPrecondition,
+	ErrInvalidPassword:              codes.InvalidArgument,
+}
+
+// Status converts the error a UserService method returns into a gRPC status
+// error, so callers can tell a missing user from a broken database.
+func Status(err error) error {
+	return interceptor.Status(err, errorCodes)
+}
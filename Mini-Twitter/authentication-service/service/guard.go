@@ -0,0 +1,182 @@
+package service
+
+import (
+	"auth-service/pkg/config"
+	"auth-service/pkg/hashing"
+	"auth-service/pkg/models"
+	"auth-service/storage"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Notifier sends the mails the auth flows need.
+type Notifier interface {
+	SendAccountLocked(to, link string) error
+}
+
+var (
+	// ErrInvalidCredentials is returned for both unknown accounts and wrong
+	// passwords, so a login does not tell which accounts exist.
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	ErrAccountLocked      = errors.New("account is temporarily locked after too many failed logins; check your email to unlock it")
+	ErrInvalidUnlockToken = errors.New("invalid or expired unlock token")
+)
+
+// TooManyAttemptsError is returned when a login comes sooner than the guard
+// allows after earlier failures.
+type TooManyAttemptsError struct {
+	RetryAfter time.Duration
+}
+
+func (e *TooManyAttemptsError) Error() string {
+	return fmt.Sprintf("too many failed attempts, retry in %s", e.RetryAfter.Round(time.Second))
+}
+
+// unlockTokenTTL matches the longest lockout, after which the link is useless.
+const unlockTokenTTL = 15 * time.Minute
+
+// Security events recorded for admins.
+const (
+	EventAccountLocked          = "account_locked"
+	EventAccountUnlocked        = "account_unlocked"
+	EventRegistrationCodeLocked = "registration_code_locked"
+)
+
+// dummyHash is compared against when the account does not exist, so unknown
+// accounts take as long to reject as wrong passwords.
+var dummyHash, _ = hashing.HashPassword("mini-twitter-dummy-password")
+
+// checkCredentials verifies a password under the brute-force guard. user and
+// lookupErr are the result of looking up the account named by identifier.
+func (a *authService) checkCredentials(ctx context.Context, ip, identifier string, user models.LoginResponse, lookupErr error, password string) error {
+	if lookupErr != nil && !errors.Is(lookupErr, sql.ErrNoRows) {
+		a.log.Error("Failed to login", "error", lookupErr)
+		return lookupErr
+	}
+	found := lookupErr == nil
+
+	// Failures against unknown accounts are counted too, so they are slowed
+	// down exactly like real ones.
+	account := "unknown:" + strings.ToLower(identifier)
+	if found {
+		account = user.Id
+	}
+
+	status, err := a.guard.LoginStatus(ctx, account, ip)
+	if err != nil {
+		a.log.Error("Failed to get login status", "error", err)
+		return err
+	}
+	if status.Locked {
+		return ErrAccountLocked
+	}
+	if status.RetryAfter > 0 {
+		return &TooManyAttemptsError{RetryAfter: status.RetryAfter}
+	}
+
+	if found && hashing.CheckPasswordHash(user.Password, password) {
+		if err := a.guard.ResetLoginFailures(ctx, account); err != nil {
+			a.log.Error("Failed to reset login failures", "error", err)
+		}
+		return nil
+	}
+	if !found {
+		hashing.CheckPasswordHash(dummyHash, password)
+	}
+
+	a.log.Warn("Failed login", "security_event", "login_failed", "account", account, "ip", ip)
+
+	locked, err := a.guard.RecordLoginFailure(ctx, account, ip)
+	if err != nil {
+		a.log.Error("Failed to record login failure", "error", err)
+		return err
+	}
+	if locked {
+		a.securityEvent(ctx, models.SecurityEvent{Type: EventAccountLocked, UserID: user.Id, Account: account, IP: ip})
+		if found {
+			a.sendUnlockLink(ctx, user, account)
+		}
+	}
+
+	return ErrInvalidCredentials
+}
+
+func (a *authService) sendUnlockLink(ctx context.Context, user models.LoginResponse, account string) {
+	unlockToken, err := newOpaqueToken()
+	if err != nil {
+		a.log.Error("Failed to generate unlock token", "error", err)
+		return
+	}
+
+	if err := a.guard.SetUnlockToken(ctx, hashOpaqueToken(unlockToken), account, unlockTokenTTL); err != nil {
+		a.log.Error("Failed to store unlock token", "error", err)
+		return
+	}
+
+	link, err := url.Parse(config.Load().UNLOCK_ACCOUNT_URL)
+	if err != nil {
+		a.log.Error("Invalid unlock account url", "error", err)
+		return
+	}
+	query := link.Query()
+	query.Set("token", unlockToken)
+	link.RawQuery = query.Encode()
+
+	if err := a.notifier.SendAccountLocked(user.Email, link.String()); err != nil {
+		a.log.Error("Failed to send unlock email", "user_id", user.Id, "error", err)
+	}
+}
+
+// Unlock lifts a lockout with the token from the emailed link.
+func (a *authService) Unlock(ctx context.Context, unlockToken string) error {
+	account, err := a.guard.Unlock(ctx, hashOpaqueToken(unlockToken))
+	if errors.Is(err, storage.ErrUnlockTokenNotFound) {
+		return ErrInvalidUnlockToken
+	}
+	if err != nil {
+		a.log.Error("Failed to unlock account", "error", err)
+		return err
+	}
+
+	a.securityEvent(ctx, models.SecurityEvent{Type: EventAccountUnlocked, UserID: account, Account: account})
+	return nil
+}
+
+// SecurityEvents lists the latest security events, newest first. Admin only.
+func (a *authService) SecurityEvents(ctx context.Context, accessToken string, limit int64) ([]models.SecurityEvent, error) {
+	claims, err := a.authenticate(ctx, accessToken)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := a.adminRank(claims); err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 || limit > 100 {
+		limit = 100
+	}
+
+	events, err := a.guard.SecurityEvents(ctx, limit)
+	if err != nil {
+		a.log.Error("Failed to get security events", "error", err)
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// securityEvent logs the event and keeps it for admins. Failing to keep it is
+// logged but does not fail the request that caused it.
+func (a *authService) securityEvent(ctx context.Context, event models.SecurityEvent) {
+	event.Occurred = time.Now().UTC().Format(time.RFC3339)
+	a.log.Warn("Security event", "security_event", event.Type, "user_id", event.UserID, "account", event.Account, "ip", event.IP, "detail", event.Detail)
+
+	if err := a.guard.RecordSecurityEvent(ctx, event); err != nil {
+		a.log.Error("Failed to record security event", "error", err)
+	}
+}
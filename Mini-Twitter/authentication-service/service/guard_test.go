@@ -0,0 +1,167 @@
+package service
+
+import (
+	"auth-service/pkg/models"
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestCheckCredentials(t *testing.T) {
+	ctx := context.Background()
+	ta := newTestAuth(t)
+	user := models.LoginResponse{Id: "u1", Email: "alice@example.com", Username: "alice", Password: testHash("right password")}
+	ta.users.add(user)
+
+	login := func(password string) error {
+		res, err := ta.st.LoginUsername(models.LoginUsernameRequest{Username: "alice"})
+		return ta.checkCredentials(ctx, "203.0.113.1", "alice", res, err, password)
+	}
+
+	if err := login("right password"); err != nil {
+		t.Fatalf("right password: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := login("wrong"); !errors.Is(err, ErrInvalidCredentials) {
+			t.Fatalf("wrong password: got %v, want ErrInvalidCredentials", err)
+		}
+	}
+
+	// The third failure makes the account wait, even for the right password.
+	if err := login("wrong"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("wrong password: got %v, want ErrInvalidCredentials", err)
+	}
+	var tooMany *TooManyAttemptsError
+	if err := login("right password"); !errors.As(err, &tooMany) {
+		t.Fatalf("login while delayed: got %v, want TooManyAttemptsError", err)
+	}
+
+	ta.mr.FastForward(time.Minute)
+	if err := login("right password"); err != nil {
+		t.Fatalf("right password after the delay: %v", err)
+	}
+	// Success forgets the failures.
+	if err := login("wrong"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("wrong password: got %v, want ErrInvalidCredentials", err)
+	}
+	if err := login("right password"); err != nil {
+		t.Fatalf("right password after one failure: %v", err)
+	}
+}
+
+func TestCheckCredentialsLockout(t *testing.T) {
+	ctx := context.Background()
+	ta := newTestAuth(t)
+	user := models.LoginResponse{Id: "u1", Email: "alice@example.com", Username: "alice", Password: testHash("right password")}
+	ta.users.add(user)
+
+	login := func(password string) error {
+		res, err := ta.st.LoginUsername(models.LoginUsernameRequest{Username: "alice"})
+		return ta.checkCredentials(ctx, "203.0.113.1", "alice", res, err, password)
+	}
+
+	for i := 0; i < 10; i++ {
+		ta.mr.FastForward(time.Minute)
+		if err := login("wrong"); !errors.Is(err, ErrInvalidCredentials) {
+			t.Fatalf("failure %d: got %v, want ErrInvalidCredentials", i+1, err)
+		}
+	}
+	ta.mr.FastForward(time.Minute)
+	if err := login("right password"); !errors.Is(err, ErrAccountLocked) {
+		t.Fatalf("login while locked: got %v, want ErrAccountLocked", err)
+	}
+
+	if len(ta.notifier.links) != 1 {
+		t.Fatalf("sent %d unlock links, want 1", len(ta.notifier.links))
+	}
+	link, err := url.Parse(ta.notifier.links[0])
+	if err != nil {
+		t.Fatalf("parse unlock link: %v", err)
+	}
+	if err := ta.Unlock(ctx, link.Query().Get("token")); err != nil {
+		t.Fatalf("Unlock: %v", err)
+	}
+	if err := login("right password"); err != nil {
+		t.Fatalf("login after unlock: %v", err)
+	}
+	if err := ta.Unlock(ctx, link.Query().Get("token")); !errors.Is(err, ErrInvalidUnlockToken) {
+		t.Fatalf("second Unlock: got %v, want ErrInvalidUnlockToken", err)
+	}
+}
+
+func TestCheckCredentialsUnknownAccount(t *testing.T) {
+	ctx := context.Background()
+	ta := newTestAuth(t)
+
+	login := func() error {
+		res, err := ta.st.LoginUsername(models.LoginUsernameRequest{Username: "nobody"})
+		return ta.checkCredentials(ctx, "203.0.113.1", "Nobody", res, err, "guess")
+	}
+
+	// Unknown accounts are slowed down like real ones.
+	for i := 0; i < 3; i++ {
+		if err := login(); !errors.Is(err, ErrInvalidCredentials) {
+			t.Fatalf("got %v, want ErrInvalidCredentials", err)
+		}
+	}
+	var tooMany *TooManyAttemptsError
+	if err := login(); !errors.As(err, &tooMany) {
+		t.Fatalf("got %v, want TooManyAttemptsError", err)
+	}
+}
+
+func TestConfirmRegistration(t *testing.T) {
+	ctx := context.Background()
+	ta := newTestAuth(t)
+	pending := models.RegisterRequest1{Email: "alice@example.com", Username: "alice", Password: "correct horse", Code: "123456"}
+	if err := ta.StartRegistration(ctx, pending); err != nil {
+		t.Fatalf("StartRegistration: %v", err)
+	}
+
+	if _, err := ta.ConfirmRegistration(ctx, pending.Email, "654321", "203.0.113.1"); !errors.Is(err, ErrInvalidRegistrationCode) {
+		t.Fatalf("wrong code: got %v, want ErrInvalidRegistrationCode", err)
+	}
+	if _, err := ta.ConfirmRegistration(ctx, "bob@example.com", "123456", "203.0.113.1"); !errors.Is(err, ErrInvalidRegistrationCode) {
+		t.Fatalf("no sign-up: got %v, want ErrInvalidRegistrationCode", err)
+	}
+
+	res, err := ta.ConfirmRegistration(ctx, pending.Email, pending.Code, "203.0.113.1")
+	if err != nil {
+		t.Fatalf("ConfirmRegistration: %v", err)
+	}
+	if res.Username != "alice" {
+		t.Fatalf("registered %q, want alice", res.Username)
+	}
+	if _, err := ta.ConfirmRegistration(ctx, pending.Email, pending.Code, "203.0.113.1"); !errors.Is(err, ErrInvalidRegistrationCode) {
+		t.Fatalf("used code: got %v, want ErrInvalidRegistrationCode", err)
+	}
+}
+
+func TestConfirmRegistrationLockout(t *testing.T) {
+	ctx := context.Background()
+	ta := newTestAuth(t)
+	pending := models.RegisterRequest1{Email: "alice@example.com", Username: "alice", Code: "123456"}
+	if err := ta.StartRegistration(ctx, pending); err != nil {
+		t.Fatalf("StartRegistration: %v", err)
+	}
+
+	for i := 0; i < 10; i++ {
+		ta.mr.FastForward(time.Minute)
+		if _, err := ta.ConfirmRegistration(ctx, pending.Email, "000000", "203.0.113.1"); !errors.Is(err, ErrInvalidRegistrationCode) {
+			t.Fatalf("failure %d: got %v, want ErrInvalidRegistrationCode", i+1, err)
+		}
+	}
+
+	// Locking discards the sign-up, so even the right code is refused
+	// once the lock is over.
+	var tooMany *TooManyAttemptsError
+	if _, err := ta.ConfirmRegistration(ctx, pending.Email, pending.Code, "203.0.113.1"); !errors.As(err, &tooMany) {
+		t.Fatalf("while locked: got %v, want TooManyAttemptsError", err)
+	}
+	ta.mr.FastForward(time.Hour)
+	if _, err := ta.ConfirmRegistration(ctx, pending.Email, pending.Code, "203.0.113.1"); !errors.Is(err, ErrInvalidRegistrationCode) {
+		t.Fatalf("after lock: got %v, want ErrInvalidRegistrationCode", err)
+	}
+}
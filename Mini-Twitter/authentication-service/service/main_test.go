@@ -0,0 +1,450 @@
+package service
+
+import (
+	pb "auth-service/genproto/user"
+	"auth-service/pkg/models"
+	"auth-service/pkg/token"
+	"auth-service/storage"
+	redisstore "auth-service/storage/redis"
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// TestMain runs the tests in a directory with an empty .env, which
+// config.Load insists on, and signs access tokens with an in-memory key.
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "auth-service")
+	if err != nil {
+		panic(err)
+	}
+	if err := os.WriteFile(dir+"/.env", nil, 0o600); err != nil {
+		panic(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		panic(err)
+	}
+
+	// Passwords in tests are hashed at the lowest cost; so is the hash that
+	// unknown accounts are checked against.
+	dummyHash = testHash("mini-twitter-dummy-password")
+
+	ks := token.NewKeySet(&memoryKeys{}, 24*time.Hour, discardLogger())
+	if err := ks.Sync(context.Background()); err != nil {
+		panic(err)
+	}
+	token.UseKeySet(ks)
+
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+func testHash(password string) string {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	if err != nil {
+		panic(err)
+	}
+	return string(hash)
+}
+
+func discardLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func newTestRedis(t *testing.T) (*redisstore.RedisStorage, *miniredis.Miniredis) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+	return redisstore.NewRedisStorage(rdb, discardLogger()), mr
+}
+
+// testAuth is an authService on miniredis and in-memory stores.
+type testAuth struct {
+	*authService
+	users     *memoryAuth
+	twoFactor *memoryTwoFactor
+	notifier  *recordingNotifier
+	redis     *redisstore.RedisStorage
+	mr        *miniredis.Miniredis
+}
+
+func newTestAuth(t *testing.T) *testAuth {
+	t.Helper()
+	rs, mr := newTestRedis(t)
+	ta := &testAuth{
+		users:     &memoryAuth{users: map[string]models.LoginResponse{}},
+		twoFactor: &memoryTwoFactor{settings: map[string]*twoFactorState{}},
+		notifier:  &recordingNotifier{},
+		redis:     rs,
+		mr:        mr,
+	}
+	ta.authService = NewAuthService(ta.users, rs, rs, rs, rs, ta.twoFactor, rs, rs, nil, rs, nil, ta.notifier, discardLogger()).(*authService)
+	return ta
+}
+
+type memoryKeys struct {
+	mu   sync.Mutex
+	keys []models.SigningKey
+}
+
+func (m *memoryKeys) ListSigningKeys(ctx context.Context) ([]models.SigningKey, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]models.SigningKey(nil), m.keys...), nil
+}
+
+func (m *memoryKeys) AddSigningKey(ctx context.Context, key models.SigningKey, latest time.Time) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.keys = append(m.keys, key)
+	return true, nil
+}
+
+func (m *memoryKeys) DeleteSigningKey(ctx context.Context, id string) error {
+	return nil
+}
+
+// memoryAuth keeps users by id. Password holds the hash, as in Postgres.
+type memoryAuth struct {
+	mu    sync.Mutex
+	users map[string]models.LoginResponse
+}
+
+func (m *memoryAuth) add(user models.LoginResponse) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.users[user.Id] = user
+}
+
+func (m *memoryAuth) Register(in models.RegisterRequest) (models.RegisterResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	id := uuid.NewString()
+	m.users[id] = models.LoginResponse{Id: id, Email: in.Email, Username: in.Username, Password: in.Password, Role: RoleUser}
+	return models.RegisterResponse{Id: id, Email: in.Email, Username: in.Username}, nil
+}
+
+func (m *memoryAuth) RegisterExternal(in models.RegisterRequest) (models.RegisterResponse, error) {
+	return m.Register(in)
+}
+
+func (m *memoryAuth) LoginEmail(in models.LoginEmailRequest) (models.LoginResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, user := range m.users {
+		if user.Email == in.Email {
+			return user, nil
+		}
+	}
+	return models.LoginResponse{}, sql.ErrNoRows
+}
+
+func (m *memoryAuth) LoginUsername(in models.LoginUsernameRequest) (models.LoginResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, user := range m.users {
+		if user.Username == in.Username {
+			return user, nil
+		}
+	}
+	return models.LoginResponse{}, sql.ErrNoRows
+}
+
+func (m *memoryAuth) UserIDByEmail(email string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, user := range m.users {
+		if strings.ToLower(user.Email) == email {
+			return user.Id, nil
+		}
+	}
+	return "", sql.ErrNoRows
+}
+
+func (m *memoryAuth) UserByID(id string) (models.LoginResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	user, ok := m.users[id]
+	if !ok {
+		return models.LoginResponse{}, sql.ErrNoRows
+	}
+	user.Password = ""
+	return user, nil
+}
+
+func (m *memoryAuth) ResetPassword(userID, password string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	user, ok := m.users[userID]
+	if !ok {
+		return sql.ErrNoRows
+	}
+	user.Password = password
+	m.users[userID] = user
+	return nil
+}
+
+type twoFactorState struct {
+	models.TwoFactor
+	codes map[string]bool
+}
+
+type memoryTwoFactor struct {
+	mu       sync.Mutex
+	settings map[string]*twoFactorState
+}
+
+func (m *memoryTwoFactor) SaveSecret(ctx context.Context, userID, secret string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if s, ok := m.settings[userID]; ok && s.Enabled {
+		return storage.ErrTwoFactorEnabled
+	}
+	m.settings[userID] = &twoFactorState{TwoFactor: models.TwoFactor{UserID: userID, Secret: secret}}
+	return nil
+}
+
+func (m *memoryTwoFactor) GetTwoFactor(ctx context.Context, userID string) (models.TwoFactor, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.settings[userID]
+	if !ok {
+		return models.TwoFactor{}, sql.ErrNoRows
+	}
+	return s.TwoFactor, nil
+}
+
+func (m *memoryTwoFactor) Enable(ctx context.Context, userID string, codeHashes []string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.settings[userID]
+	if !ok || s.Enabled {
+		return storage.ErrTwoFactorEnabled
+	}
+	s.Enabled = true
+	s.codes = map[string]bool{}
+	for _, hash := range codeHashes {
+		s.codes[hash] = true
+	}
+	return nil
+}
+
+func (m *memoryTwoFactor) UseRecoveryCode(ctx context.Context, userID, codeHash string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.settings[userID]
+	if !ok || !s.codes[codeHash] {
+		return false, nil
+	}
+	delete(s.codes, codeHash)
+	return true, nil
+}
+
+func (m *memoryTwoFactor) Disable(ctx context.Context, userID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.settings, userID)
+	return nil
+}
+
+type recordingNotifier struct {
+	mu    sync.Mutex
+	links []string
+}
+
+func (n *recordingNotifier) SendAccountLocked(to, link string) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.links = append(n.links, link)
+	return nil
+}
+
+// memoryUsers is a UserStorage for the UserService tests. Methods no test
+// needs are left to the nil embedded interface.
+type memoryUsers struct {
+	storage.UserStorage
+
+	mu    sync.Mutex
+	roles map[string]string
+	// usernames are the current ones by user id, former are the user ids
+	// by former username.
+	usernames map[string]string
+	changedAt map[string]time.Time
+	former    map[string]string
+	audit     *pb.AuditFilter
+	deleted   map[string]bool
+	// due are the users whose deletion is due; cancelled ones are not
+	// purged and failing ones fail to.
+	due       []string
+	cancelled map[string]bool
+	failing   map[string]bool
+	purged    []string
+}
+
+func newMemoryUsers() *memoryUsers {
+	return &memoryUsers{
+		roles:     map[string]string{},
+		usernames: map[string]string{},
+		changedAt: map[string]time.Time{},
+		former:    map[string]string{},
+		deleted:   map[string]bool{},
+		cancelled: map[string]bool{},
+		failing:   map[string]bool{},
+	}
+}
+
+func (m *memoryUsers) FetchUsers(in *pb.Filter) (*pb.UserResponses, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	res := &pb.UserResponses{}
+	for id, role := range m.roles {
+		res.Users = append(res.Users, &pb.UserResponse{Id: id, Email: id + "@example.com", Role: role})
+	}
+	return res, nil
+}
+
+func (m *memoryUsers) UsernameState(ctx context.Context, userID string) (string, time.Time, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	username, ok := m.usernames[userID]
+	if !ok {
+		return "", time.Time{}, storage.ErrUserNotFound
+	}
+	return username, m.changedAt[userID], nil
+}
+
+func (m *memoryUsers) UsernameOwner(ctx context.Context, username string) (string, string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.owner(username)
+}
+
+func (m *memoryUsers) owner(username string) (string, string, error) {
+	for id, current := range m.usernames {
+		if strings.EqualFold(current, username) {
+			return id, current, nil
+		}
+	}
+	for former, id := range m.former {
+		if strings.EqualFold(former, username) {
+			return id, m.usernames[id], nil
+		}
+	}
+	return "", "", sql.ErrNoRows
+}
+
+func (m *memoryUsers) ChangeUsername(ctx context.Context, userID, username string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if owner, _, err := m.owner(username); err == nil && owner != userID {
+		return storage.ErrUsernameTaken
+	}
+	delete(m.former, username)
+	m.former[m.usernames[userID]] = userID
+	m.usernames[userID] = username
+	m.changedAt[userID] = time.Now()
+	return nil
+}
+
+func (m *memoryUsers) UserRole(ctx context.Context, userID string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	role, ok := m.roles[userID]
+	if !ok {
+		return "", storage.ErrUserNotFound
+	}
+	return role, nil
+}
+
+func (m *memoryUsers) SetRole(ctx context.Context, in *pb.SetRoleReq) (*pb.UserResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.roles[in.UserId] = in.Role
+	return &pb.UserResponse{Id: in.UserId, Role: in.Role}, nil
+}
+
+func (m *memoryUsers) ListAuditLog(ctx context.Context, in *pb.AuditFilter) (*pb.AuditEntries, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.audit = in
+	return &pb.AuditEntries{}, nil
+}
+
+func (m *memoryUsers) DeleteUser(in *pb.Id) (*pb.Void, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.deleted[in.UserId] = true
+	return &pb.Void{}, nil
+}
+
+func (m *memoryUsers) DueDeletions(ctx context.Context, limit int) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.due) > limit {
+		return append([]string(nil), m.due[:limit]...), nil
+	}
+	return append([]string(nil), m.due...), nil
+}
+
+func (m *memoryUsers) PurgeUser(ctx context.Context, userID string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.failing[userID] {
+		return false, errors.New("database is down")
+	}
+	for i, id := range m.due {
+		if id == userID {
+			m.due = append(m.due[:i], m.due[i+1:]...)
+			break
+		}
+	}
+	if m.cancelled[userID] {
+		return false, nil
+	}
+	m.purged = append(m.purged, userID)
+	return true, nil
+}
+
+// testUsers is a UserService on memoryUsers, revoking tokens in miniredis.
+type testUsers struct {
+	*UserService
+	users *memoryUsers
+	redis *redisstore.RedisStorage
+	mr    *miniredis.Miniredis
+}
+
+func newTestUsers(t *testing.T) *testUsers {
+	t.Helper()
+	rs, mr := newTestRedis(t)
+	users := newMemoryUsers()
+	return &testUsers{
+		UserService: NewUserService(users, rs, 30*24*time.Hour, 7*24*time.Hour, 30*24*time.Hour, discardLogger()),
+		users:       users,
+		redis:       rs,
+		mr:          mr,
+	}
+}
+
+// revoked reports whether all tokens of the user were revoked.
+func (tu *testUsers) revoked(t *testing.T, userID string) bool {
+	t.Helper()
+	at, err := tu.redis.UserRevokedAt(context.Background(), userID)
+	if err != nil {
+		t.Fatalf("UserRevokedAt: %v", err)
+	}
+	return !at.IsZero()
+}
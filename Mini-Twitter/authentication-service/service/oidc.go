@@ -0,0 +1,206 @@
+package service
+
+import (
+	"auth-service/pkg/hashing"
+	"auth-service/pkg/models"
+	"auth-service/pkg/oidc"
+	"auth-service/storage"
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	ErrUnknownProvider  = errors.New("unknown identity provider")
+	ErrInvalidOIDCState = errors.New("invalid or expired login state, start the login again")
+	ErrOIDCLoginFailed  = errors.New("login with the identity provider failed")
+	// ErrEmailNotVerified is returned when the provider does not vouch for
+	// the email, which is then not trusted to find or create an account.
+	ErrEmailNotVerified = errors.New("the identity provider has not verified this email")
+)
+
+// OIDCStateTTL is how long a user has to sign in at the provider.
+const OIDCStateTTL = 10 * time.Minute
+
+// EventIdentityLinked is recorded when an external identity is linked to an
+// account by its email.
+const EventIdentityLinked = "identity_linked"
+
+// OIDCLoginURL starts a login at the provider and returns where to send the
+// user. The state, nonce and PKCE verifier are kept until the callback.
+//
+// The binding is to be kept in the browser, in a cookie, and passed back to
+// OIDCCallback, so a login is only finished in the browser that started it.
+func (a *authService) OIDCLoginURL(ctx context.Context, provider string) (authURL, binding string, err error) {
+	p, ok := a.providers[provider]
+	if !ok {
+		return "", "", ErrUnknownProvider
+	}
+
+	state, err := newOpaqueToken()
+	if err != nil {
+		a.log.Error("Failed to generate oidc state", "error", err)
+		return "", "", err
+	}
+	nonce, err := newOpaqueToken()
+	if err != nil {
+		a.log.Error("Failed to generate oidc nonce", "error", err)
+		return "", "", err
+	}
+	verifier := oidc.GenerateVerifier()
+
+	authURL, err = p.AuthCodeURL(ctx, state, nonce, verifier)
+	if err != nil {
+		a.log.Error("Failed to build authorization url", "provider", provider, "error", err)
+		return "", "", err
+	}
+
+	err = a.oidcStates.SaveOIDCState(ctx, hashOpaqueToken(state), models.OIDCState{
+		Provider: provider,
+		Verifier: verifier,
+		Nonce:    nonce,
+	}, OIDCStateTTL)
+	if err != nil {
+		a.log.Error("Failed to save oidc state", "error", err)
+		return "", "", err
+	}
+
+	return authURL, hashOpaqueToken(state), nil
+}
+
+// OIDCCallback finishes a login at the provider. The user is found by the
+// linked identity, or else by the verified email, which links the identity
+// to that account. Without either a new account is created.
+//
+// binding is the one OIDCLoginURL returned to this browser. Without it a
+// callback link made by someone else would sign the user in to their
+// account.
+func (a *authService) OIDCCallback(ctx context.Context, provider, state, binding, code string, client models.Client) (models.Tokens, error) {
+	p, ok := a.providers[provider]
+	if !ok {
+		return models.Tokens{}, ErrUnknownProvider
+	}
+
+	if subtle.ConstantTimeCompare([]byte(binding), []byte(hashOpaqueToken(state))) != 1 {
+		a.log.Warn("OIDC callback from another browser", "provider", provider, "ip", client.IP)
+		return models.Tokens{}, ErrInvalidOIDCState
+	}
+
+	saved, err := a.oidcStates.TakeOIDCState(ctx, hashOpaqueToken(state))
+	if errors.Is(err, storage.ErrOIDCStateNotFound) {
+		return models.Tokens{}, ErrInvalidOIDCState
+	}
+	if err != nil {
+		a.log.Error("Failed to get oidc state", "error", err)
+		return models.Tokens{}, err
+	}
+	if saved.Provider != provider {
+		return models.Tokens{}, ErrInvalidOIDCState
+	}
+
+	identity, err := p.Exchange(ctx, code, saved.Verifier)
+	if err != nil {
+		a.log.Error("Failed to exchange authorization code", "provider", provider, "error", err)
+		return models.Tokens{}, ErrOIDCLoginFailed
+	}
+	if identity.Nonce != saved.Nonce {
+		a.log.Error("ID token nonce mismatch", "provider", provider)
+		return models.Tokens{}, ErrOIDCLoginFailed
+	}
+
+	user, err := a.oidcUser(ctx, provider, identity, client)
+	if err != nil {
+		return models.Tokens{}, err
+	}
+
+	return a.login(ctx, user, client)
+}
+
+func (a *authService) oidcUser(ctx context.Context, provider string, identity oidc.Identity, client models.Client) (models.LoginResponse, error) {
+	user, err := a.identities.UserByIdentity(ctx, provider, identity.Subject)
+	if err == nil {
+		return user, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		a.log.Error("Failed to get user by identity", "error", err)
+		return models.LoginResponse{}, err
+	}
+
+	if identity.Email == "" || !identity.EmailVerified {
+		return models.LoginResponse{}, ErrEmailNotVerified
+	}
+
+	user, err = a.st.LoginEmail(models.LoginEmailRequest{Email: identity.Email})
+	if errors.Is(err, sql.ErrNoRows) {
+		user, err = a.registerExternal(identity)
+	}
+	if err != nil {
+		a.log.Error("Failed to get user for identity", "error", err)
+		return models.LoginResponse{}, err
+	}
+	user.Password = ""
+
+	err = a.identities.LinkIdentity(ctx, user.Id, models.ExternalIdentity{
+		Provider: provider,
+		Subject:  identity.Subject,
+		Email:    identity.Email,
+	})
+	if err != nil {
+		a.log.Error("Failed to link identity", "error", err)
+		return models.LoginResponse{}, err
+	}
+	a.securityEvent(ctx, models.SecurityEvent{Type: EventIdentityLinked, UserID: user.Id, IP: client.IP, Detail: provider})
+
+	return user, nil
+}
+
+// registerExternal creates an account for someone who signed in at a provider
+// for the first time. It has no password until one is set with a reset link.
+func (a *authService) registerExternal(identity oidc.Identity) (models.LoginResponse, error) {
+	username, err := externalUsername(identity.Email)
+	if err != nil {
+		return models.LoginResponse{}, err
+	}
+
+	first, last, _ := strings.Cut(identity.Name, " ")
+	res, err := a.st.RegisterExternal(models.RegisterRequest{
+		FirstName: first,
+		LastName:  last,
+		Email:     identity.Email,
+		Password:  hashing.NoPassword,
+		Username:  username,
+	})
+	if err != nil {
+		return models.LoginResponse{}, err
+	}
+
+	return models.LoginResponse{Id: res.Id, Email: res.Email, Username: res.Username, Role: "user"}, nil
+}
+
+var usernameChars = regexp.MustCompile(`[^a-z0-9_]+`)
+
+// externalUsername derives a username from the email, with a random suffix
+// as the plain local part is likely taken.
+func externalUsername(email string) (string, error) {
+	local, _, _ := strings.Cut(strings.ToLower(email), "@")
+	local = usernameChars.ReplaceAllString(local, "")
+	if len(local) > 20 {
+		local = local[:20]
+	}
+	if local == "" {
+		local = "user"
+	}
+
+	suffix := make([]byte, 3)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", err
+	}
+
+	return local + "_" + hex.EncodeToString(suffix), nil
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"auth-service/pkg/hashing"
+	"auth-service/pkg/models"
+	"auth-service/pkg/oidc"
+	"auth-service/pkg/oidc/oidctest"
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"testing"
+)
+
+// memoryIdentities links identities to users of a memoryAuth.
+type memoryIdentities struct {
+	mu    sync.Mutex
+	users *memoryAuth
+	links map[string]string
+}
+
+func (m *memoryIdentities) UserByIdentity(ctx context.Context, provider, subject string) (models.LoginResponse, error) {
+	m.mu.Lock()
+	userID, ok := m.links[provider+"/"+subject]
+	m.mu.Unlock()
+	if !ok {
+		return models.LoginResponse{}, sql.ErrNoRows
+	}
+	return m.users.UserByID(userID)
+}
+
+func (m *memoryIdentities) LinkIdentity(ctx context.Context, userID string, identity models.ExternalIdentity) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.links[identity.Provider+"/"+identity.Subject] = userID
+	return nil
+}
+
+// newOIDCTestAuth is a testAuth with the mock provider configured as "mock".
+func newOIDCTestAuth(t *testing.T) (*testAuth, *oidctest.Server) {
+	t.Helper()
+	ta := newTestAuth(t)
+
+	srv := oidctest.NewServer()
+	t.Cleanup(srv.Close)
+
+	ta.identities = &memoryIdentities{users: ta.users, links: map[string]string{}}
+	ta.providers = map[string]*oidc.Provider{
+		"mock": oidc.NewProvider(oidc.Config{
+			Name:         "mock",
+			Issuer:       srv.URL,
+			ClientID:     oidctest.ClientID,
+			ClientSecret: oidctest.ClientSecret,
+			RedirectURL:  "http://localhost:8081/auth/oidc/mock/callback",
+		}),
+	}
+	return ta, srv
+}
+
+// oidcSignIn runs the flow up to the callback and returns the state, the
+// browser binding and the code.
+func oidcSignIn(t *testing.T, ta *testAuth, srv *oidctest.Server) (string, string, string) {
+	t.Helper()
+
+	authURL, binding, err := ta.OIDCLoginURL(context.Background(), "mock")
+	if err != nil {
+		t.Fatalf("OIDCLoginURL: %v", err)
+	}
+	callback, err := srv.Authorize(authURL)
+	if err != nil {
+		t.Fatalf("Authorize: %v", err)
+	}
+	return callback.Query().Get("state"), binding, callback.Query().Get("code")
+}
+
+func TestOIDCCallbackCreatesAccount(t *testing.T) {
+	ctx := context.Background()
+	ta, srv := newOIDCTestAuth(t)
+	srv.SetUser(oidctest.User{Subject: "sub-1", Email: "ann@example.com", EmailVerified: true, Name: "Ann Lee"})
+
+	state, binding, code := oidcSignIn(t, ta, srv)
+	tokens, err := ta.OIDCCallback(ctx, "mock", state, binding, code, models.Client{})
+	if err != nil {
+		t.Fatalf("OIDCCallback: %v", err)
+	}
+	if tokens.AccessToken == "" {
+		t.Fatal("no access token")
+	}
+
+	user, err := ta.users.LoginEmail(models.LoginEmailRequest{Email: "ann@example.com"})
+	if err != nil {
+		t.Fatalf("account was not created: %v", err)
+	}
+	if user.Password != hashing.NoPassword {
+		t.Errorf("external account has password %q, want NoPassword", user.Password)
+	}
+
+	// Signing in again finds the account by the linked identity.
+	state, binding, code = oidcSignIn(t, ta, srv)
+	if _, err := ta.OIDCCallback(ctx, "mock", state, binding, code, models.Client{}); err != nil {
+		t.Fatalf("second OIDCCallback: %v", err)
+	}
+	if len(ta.users.users) != 1 {
+		t.Errorf("%d accounts, want 1", len(ta.users.users))
+	}
+}
+
+func TestOIDCCallbackLinksByEmail(t *testing.T) {
+	ctx := context.Background()
+	ta, srv := newOIDCTestAuth(t)
+	ta.users.add(models.LoginResponse{Id: "u1", Email: "ann@example.com", Username: "ann", Password: testHash("right password"), Role: RoleUser})
+	srv.SetUser(oidctest.User{Subject: "sub-1", Email: "ann@example.com", EmailVerified: true})
+
+	state, binding, code := oidcSignIn(t, ta, srv)
+	if _, err := ta.OIDCCallback(ctx, "mock", state, binding, code, models.Client{}); err != nil {
+		t.Fatalf("OIDCCallback: %v", err)
+	}
+
+	user, err := ta.identities.UserByIdentity(ctx, "mock", "sub-1")
+	if err != nil {
+		t.Fatalf("identity was not linked: %v", err)
+	}
+	if user.Id != "u1" {
+		t.Errorf("identity linked to %s, want u1", user.Id)
+	}
+	if len(ta.users.users) != 1 {
+		t.Errorf("%d accounts, want 1", len(ta.users.users))
+	}
+}
+
+func TestOIDCCallbackUnverifiedEmail(t *testing.T) {
+	ctx := context.Background()
+	ta, srv := newOIDCTestAuth(t)
+	ta.users.add(models.LoginResponse{Id: "u1", Email: "ann@example.com", Username: "ann", Role: RoleUser})
+	srv.SetUser(oidctest.User{Subject: "sub-1", Email: "ann@example.com", EmailVerified: false})
+
+	state, binding, code := oidcSignIn(t, ta, srv)
+	if _, err := ta.OIDCCallback(ctx, "mock", state, binding, code, models.Client{}); !errors.Is(err, ErrEmailNotVerified) {
+		t.Fatalf("OIDCCallback: got %v, want ErrEmailNotVerified", err)
+	}
+	if _, err := ta.identities.UserByIdentity(ctx, "mock", "sub-1"); err == nil {
+		t.Error("identity linked by an unverified email")
+	}
+}
+
+func TestOIDCCallbackOtherBrowser(t *testing.T) {
+	ctx := context.Background()
+	ta, srv := newOIDCTestAuth(t)
+	srv.SetUser(oidctest.User{Subject: "sub-1", Email: "ann@example.com", EmailVerified: true})
+
+	// The callback link of one browser opened in another, which started its
+	// own login or none.
+	state, _, code := oidcSignIn(t, ta, srv)
+	_, otherBinding, _ := oidcSignIn(t, ta, srv)
+	for _, binding := range []string{"", otherBinding} {
+		if _, err := ta.OIDCCallback(ctx, "mock", state, binding, code, models.Client{}); !errors.Is(err, ErrInvalidOIDCState) {
+			t.Errorf("OIDCCallback with binding %q: got %v, want ErrInvalidOIDCState", binding, err)
+		}
+	}
+	if len(ta.users.users) != 0 {
+		t.Errorf("%d accounts, want 0", len(ta.users.users))
+	}
+}
+
+func TestLoginExternalAccountWithoutPassword(t *testing.T) {
+	ctx := context.Background()
+	ta := newTestAuth(t)
+	ta.users.add(models.LoginResponse{Id: "u1", Email: "ann@example.com", Username: "ann", Password: hashing.NoPassword, Role: RoleUser})
+
+	for _, password := range []string{"", hashing.NoPassword} {
+		_, err := ta.LoginEmail(ctx, models.LoginEmailRequest{Email: "ann@example.com", Password: password}, models.Client{})
+		if !errors.Is(err, ErrInvalidCredentials) {
+			t.Errorf("login with %q: got %v, want ErrInvalidCredentials", password, err)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"auth-service/pkg/models"
+	"auth-service/storage"
+	"context"
+	"crypto/subtle"
+	"errors"
+)
+
+// ErrInvalidRegistrationCode is returned for wrong codes and for sign-ups
+// that expired or were never started alike.
+var ErrInvalidRegistrationCode = errors.New("invalid or expired code")
+
+// StartRegistration keeps the sign-up until the code emailed to the user is
+// entered.
+func (a *authService) StartRegistration(ctx context.Context, in models.RegisterRequest1) error {
+	if err := a.registrations.SetRegister(ctx, in); err != nil {
+		a.log.Error("Failed to save pending registration", "error", err)
+		return err
+	}
+	return nil
+}
+
+// ConfirmRegistration creates the account once the emailed code is entered.
+// Code guesses are guarded like logins, under their own account key, and
+// locking it discards the sign-up so the guesser has to start over.
+func (a *authService) ConfirmRegistration(ctx context.Context, email, code, ip string) (models.RegisterResponse, error) {
+	account := "register:" + normalizeEmail(email)
+
+	status, err := a.guard.LoginStatus(ctx, account, ip)
+	if err != nil {
+		a.log.Error("Failed to get login status", "error", err)
+		return models.RegisterResponse{}, err
+	}
+	if status.Locked || status.RetryAfter > 0 {
+		return models.RegisterResponse{}, &TooManyAttemptsError{RetryAfter: status.RetryAfter}
+	}
+
+	pending, err := a.registrations.GetRegister(ctx, email)
+	if err != nil && !errors.Is(err, storage.ErrRegistrationNotFound) {
+		a.log.Error("Failed to get pending registration", "error", err)
+		return models.RegisterResponse{}, err
+	}
+	if err != nil || subtle.ConstantTimeCompare([]byte(pending.Code), []byte(code)) != 1 {
+		a.log.Warn("Invalid registration code", "security_event", "registration_code_failed", "email", email, "ip", ip)
+
+		locked, err := a.guard.RecordLoginFailure(ctx, account, ip)
+		if err != nil {
+			a.log.Error("Failed to record code failure", "error", err)
+		}
+		if locked {
+			if err := a.registrations.DeleteRegister(ctx, email); err != nil {
+				a.log.Error("Failed to delete pending registration", "error", err)
+			}
+			a.securityEvent(ctx, models.SecurityEvent{Type: EventRegistrationCodeLocked, Account: account, IP: ip})
+		}
+		return models.RegisterResponse{}, ErrInvalidRegistrationCode
+	}
+
+	res, err := a.Register(models.RegisterRequest{
+		FirstName:   pending.FirstName,
+		LastName:    pending.LastName,
+		Email:       pending.Email,
+		Phone:       pending.Phone,
+		Username:    pending.Username,
+		Nationality: pending.Nationality,
+		Bio:         pending.Bio,
+		Password:    pending.Password,
+	})
+	if err != nil {
+		return models.RegisterResponse{}, err
+	}
+
+	if err := a.registrations.DeleteRegister(ctx, email); err != nil {
+		a.log.Error("Failed to delete pending registration", "error", err)
+	}
+	if err := a.guard.ResetLoginFailures(ctx, account); err != nil {
+		a.log.Error("Failed to reset code failures", "error", err)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	pb "auth-service/genproto/user"
+	"context"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// maxRelationshipTargets bounds one batch, about a page of tweets.
+	maxRelationshipTargets = 100
+	defaultMutualLimit     = 3
+	maxMutualLimit         = 50
+)
+
+// relationshipTargets checks the viewer and targets of a batched lookup and
+// drops duplicate targets.
+func relationshipTargets(viewerID string, targetIDs []string) ([]string, error) {
+	if _, err := uuid.Parse(viewerID); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid viewer id")
+	}
+	if len(targetIDs) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "no target ids")
+	}
+
+	seen := make(map[string]bool, len(targetIDs))
+	targets := make([]string, 0, len(targetIDs))
+	for _, id := range targetIDs {
+		if _, err := uuid.Parse(id); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid target id %q", id)
+		}
+		if !seen[id] {
+			seen[id] = true
+			targets = append(targets, id)
+		}
+	}
+	if len(targets) > maxRelationshipTargets {
+		return nil, status.Errorf(codes.InvalidArgument, "at most %d target ids", maxRelationshipTargets)
+	}
+	return targets, nil
+}
+
+// GetRelationships tells how the viewer and each target are related, for
+// "Follows you" labels and follow buttons.
+func (us *UserService) GetRelationships(ctx context.Context, in *pb.RelationshipsReq) (*pb.Relationships, error) {
+	targets, err := relationshipTargets(in.ViewerId, in.TargetIds)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := us.st.Relationships(ctx, in.ViewerId, targets)
+	if err != nil {
+		us.log.Error("failed to get relationships", "error", err)
+		return nil, err
+	}
+	return &pb.Relationships{Relationships: res}, nil
+}
+
+// GetMutualFollowers returns, for each target, a page of the people the
+// viewer follows who follow them, for "Followed by A, B and 3 others".
+func (us *UserService) GetMutualFollowers(ctx context.Context, in *pb.MutualFollowersReq) (*pb.MutualFollowers, error) {
+	targets, err := relationshipTargets(in.ViewerId, in.TargetIds)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := int(in.Limit)
+	if limit <= 0 {
+		limit = defaultMutualLimit
+	}
+	limit = min(limit, maxMutualLimit)
+	page := max(int(in.Page), 1)
+
+	res, err := us.st.MutualFollowers(ctx, in.ViewerId, targets, limit, (page-1)*limit)
+	if err != nil {
+		us.log.Error("failed to get mutual followers", "error", err)
+		return nil, err
+	}
+	return &pb.MutualFollowers{Targets: res}, nil
+}
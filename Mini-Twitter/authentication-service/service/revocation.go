@@ -0,0 +1,34 @@
+package service
+
+import (
+	"auth-service/storage"
+	"context"
+	"log/slog"
+	"time"
+)
+
+// revokeAttempts and revokeBackoff bound how hard revokeUser tries before
+// giving up.
+const (
+	revokeAttempts = 3
+	revokeBackoff  = 100 * time.Millisecond
+)
+
+// revokeUser denies every token of the user issued so far. It is called once
+// a change is committed, so failing the request would only make the caller
+// retry a change that already happened; instead the revocation is retried a
+// few times, even if the request is cancelled, and a failure is logged.
+func revokeUser(ctx context.Context, revocations storage.RevocationStorage, log *slog.Logger, userID string) {
+	at := time.Now()
+	ctx = context.WithoutCancel(ctx)
+
+	var err error
+	for attempt := 1; attempt <= revokeAttempts; attempt++ {
+		if err = revocations.RevokeUser(ctx, userID, at); err == nil {
+			return
+		}
+		log.Warn("Failed to revoke user tokens, retrying", "user_id", userID, "attempt", attempt, "error", err)
+		time.Sleep(time.Duration(attempt) * revokeBackoff)
+	}
+	log.Error("Failed to revoke user tokens", "user_id", userID, "error", err)
+}
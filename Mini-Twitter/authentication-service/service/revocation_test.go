@@ -0,0 +1,46 @@
+package service
+
+import (
+	"auth-service/storage"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// flakyRevocations fails the first failures calls to RevokeUser.
+type flakyRevocations struct {
+	storage.RevocationStorage
+	failures int
+	calls    int
+	revoked  time.Time
+}
+
+func (f *flakyRevocations) RevokeUser(ctx context.Context, userID string, at time.Time) error {
+	f.calls++
+	if f.calls <= f.failures {
+		return errors.New("redis is down")
+	}
+	f.revoked = at
+	return nil
+}
+
+func TestRevokeUserRetries(t *testing.T) {
+	revocations := &flakyRevocations{failures: revokeAttempts - 1}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	revokeUser(ctx, revocations, discardLogger(), "u1")
+	if revocations.calls != revokeAttempts || revocations.revoked.IsZero() {
+		t.Fatalf("revoked after %d calls at %v, want %d calls", revocations.calls, revocations.revoked, revokeAttempts)
+	}
+}
+
+func TestRevokeUserGivesUp(t *testing.T) {
+	revocations := &flakyRevocations{failures: revokeAttempts + 1}
+
+	revokeUser(context.Background(), revocations, discardLogger(), "u1")
+	if revocations.calls != revokeAttempts {
+		t.Fatalf("RevokeUser called %d times, want %d", revocations.calls, revokeAttempts)
+	}
+}
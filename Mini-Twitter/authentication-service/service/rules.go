@@ -0,0 +1,50 @@
+package service
+
+import (
+	"common/interceptor"
+
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// UserRules are what the UserService methods ask of their caller. Methods
+// acting as a user name them in a request field, which has to be the caller.
+// Methods missing here are refused, so a new method needs a rule to be
+// callable at all.
+var UserRules = map[string]interceptor.Rule{
+	"/user.UserService/Create":             {AdminOnly: true},
+	"/user.UserService/UpdateProfile":      {Field: "user_id", Admins: true},
+	"/user.UserService/ChangePassword":     {Field: "userId"},
+	"/user.UserService/ChangeProfileImage": {Field: "user_id", Admins: true},
+	"/user.UserService/ChangeUsername":     {Field: "user_id"},
+	"/user.UserService/DeleteUser":         {AdminOnly: true},
+	"/user.UserService/Follow":             {Field: "followerId"},
+	"/user.UserService/Unfollow":           {Field: "followerId"},
+	"/user.UserService/SuggestUsers":       {Field: "user_id"},
+	"/user.UserService/SearchUsers":        {Field: "user_id"},
+	"/user.UserService/Block":              {Field: "blocker_id"},
+	"/user.UserService/Unblock":            {Field: "blocker_id"},
+	"/user.UserService/GetRelationships":   {Field: "viewer_id"},
+	"/user.UserService/GetMutualFollowers": {Field: "viewer_id"},
+	"/user.UserService/RequestDataExport":  {Field: "user_id"},
+	"/user.UserService/RequestDeletion":    {Field: "user_id"},
+	"/user.UserService/CancelDeletion":     {Field: "user_id"},
+	// The actor is recorded in the audit log, so admins cannot name another.
+	"/user.UserService/SetRole":      {Field: "actor_id", AdminOnly: true},
+	"/user.UserService/SetVerified":  {Field: "actor_id", AdminOnly: true},
+	"/user.UserService/ListAuditLog": {AdminOnly: true},
+	// Lists every account; only admins see the emails.
+	"/user.UserService/FetchUsers": {},
+
+	"/user.UserService/GetProfile":                {Public: true},
+	"/user.UserService/ListOfFollowing":           {Public: true},
+	"/user.UserService/ListOfFollowers":           {Public: true},
+	"/user.UserService/ListOfFollowingByUsername": {Public: true},
+	"/user.UserService/ListOfFollowersByUsername": {Public: true},
+	"/user.UserService/CheckUsername":             {Public: true},
+	"/user.UserService/ResolveUsername":           {Public: true},
+	"/user.UserService/GetUserFollowers":          {Public: true},
+	"/user.UserService/GetUserFollows":            {Public: true},
+	"/user.UserService/MostPopularUser":           {Public: true},
+
+	grpc_health_v1.Health_Check_FullMethodName: {Public: true},
+}
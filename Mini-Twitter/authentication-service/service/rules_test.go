@@ -0,0 +1,15 @@
+package service
+
+import (
+	pb "auth-service/genproto/user"
+	"testing"
+)
+
+func TestEveryMethodHasRule(t *testing.T) {
+	for _, m := range pb.UserService_ServiceDesc.Methods {
+		method := "/" + pb.UserService_ServiceDesc.ServiceName + "/" + m.MethodName
+		if _, ok := UserRules[method]; !ok {
+			t.Errorf("%s has no rule and cannot be called", method)
+		}
+	}
+}
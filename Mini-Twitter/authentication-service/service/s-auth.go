@@ -1,28 +1,94 @@
 package service
 
 import (
+	"auth-service/pkg/config"
 	"auth-service/pkg/hashing"
 	"auth-service/pkg/models"
+	"auth-service/pkg/oidc"
 	"auth-service/pkg/token"
 	"auth-service/storage"
+	"context"
+	"database/sql"
 	"errors"
-	"log"
+	"fmt"
 	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidRefreshToken is returned for refresh tokens that cannot be used,
+// whether malformed, expired, revoked or replayed.
+var ErrInvalidRefreshToken = errors.New("invalid refresh token")
+
+// ErrInvalidAccessToken is returned when logging out with an access token
+// that cannot be parsed or has expired.
+var ErrInvalidAccessToken = errors.New("invalid access token")
+
+var (
+	ErrTooManyResetRequests = errors.New("too many password reset requests, try again later")
+	ErrInvalidResetToken    = errors.New("invalid or expired password reset token")
+	ErrInvalidPassword      = fmt.Errorf("password must be %d to %d characters long", minPasswordLength, maxPasswordLength)
+)
+
+// bcrypt ignores everything past 72 bytes, so longer passwords are refused
+// rather than silently cut.
+const (
+	minPasswordLength = 8
+	maxPasswordLength = 72
 )
 
+// resetTokenTTL is how long an emailed reset link works.
+const resetTokenTTL = 15 * time.Minute
+
 type AuthService interface {
 	Register(in models.RegisterRequest) (models.RegisterResponse, error)
-	LoginEmail(in models.LoginEmailRequest) (models.Tokens, error)
-	LoginUsername(in models.LoginUsernameRequest) (models.Tokens, error)
+	StartRegistration(ctx context.Context, in models.RegisterRequest1) error
+	ConfirmRegistration(ctx context.Context, email, code, ip string) (models.RegisterResponse, error)
+	LoginEmail(ctx context.Context, in models.LoginEmailRequest, client models.Client) (models.Tokens, error)
+	LoginUsername(ctx context.Context, in models.LoginUsernameRequest, client models.Client) (models.Tokens, error)
+	Refresh(ctx context.Context, refreshToken string, client models.Client) (models.Tokens, error)
+	Logout(ctx context.Context, accessToken, refreshToken string) error
+	LogoutAll(ctx context.Context, accessToken string) error
+	ForgotPassword(ctx context.Context, email string) (string, error)
+	ResetPassword(ctx context.Context, resetToken, newPassword string) error
+
+	EnrollTwoFactor(ctx context.Context, accessToken string) (models.TwoFactorEnrollment, error)
+	ConfirmTwoFactor(ctx context.Context, accessToken, code string) (models.RecoveryCodes, error)
+	LoginTwoFactor(ctx context.Context, in models.TwoFactorLoginRequest, client models.Client) (models.Tokens, error)
+	ResetTwoFactor(ctx context.Context, accessToken, userID string) error
+
+	Unlock(ctx context.Context, unlockToken string) error
+	SecurityEvents(ctx context.Context, accessToken string, limit int64) ([]models.SecurityEvent, error)
+
+	Sessions(ctx context.Context, accessToken string) ([]models.Session, error)
+	RevokeSession(ctx context.Context, accessToken, sessionID string) error
+
+	OIDCLoginURL(ctx context.Context, provider string) (authURL, binding string, err error)
+	OIDCCallback(ctx context.Context, provider, state, binding, code string, client models.Client) (models.Tokens, error)
 }
 
-func NewAuthService(st storage.AuthStorage, logger *slog.Logger) AuthService {
-	return &authService{st, logger}
+func NewAuthService(st storage.AuthStorage, registrations storage.RegistrationStorage, tokens storage.TokenStorage, revocations storage.RevocationStorage, resets storage.ResetStorage, twoFactor storage.TwoFactorStorage, challenges storage.ChallengeStorage, guard storage.LoginGuardStorage, identities storage.IdentityStorage, oidcStates storage.OIDCStateStorage, providers map[string]*oidc.Provider, notifier Notifier, logger *slog.Logger) AuthService {
+	return &authService{st, registrations, tokens, revocations, resets, twoFactor, challenges, guard, identities, oidcStates, providers, notifier, logger}
 }
 
 type authService struct {
-	st  storage.AuthStorage
-	log *slog.Logger
+	st            storage.AuthStorage
+	registrations storage.RegistrationStorage
+	tokens        storage.TokenStorage
+	revocations   storage.RevocationStorage
+	resets        storage.ResetStorage
+	twoFactor     storage.TwoFactorStorage
+	challenges    storage.ChallengeStorage
+	guard         storage.LoginGuardStorage
+	identities    storage.IdentityStorage
+	oidcStates    storage.OIDCStateStorage
+	// providers are the external identity providers by name.
+	providers map[string]*oidc.Provider
+	notifier  Notifier
+	log       *slog.Logger
 }
 
 func (a *authService) Register(in models.RegisterRequest) (models.RegisterResponse, error) {
@@ -43,61 +109,124 @@ func (a *authService) Register(in models.RegisterRequest) (models.RegisterRespon
 	return res, nil
 }
 
-func (a *authService) LoginEmail(in models.LoginEmailRequest) (models.Tokens, error) {
+func (a *authService) LoginEmail(ctx context.Context, in models.LoginEmailRequest, client models.Client) (models.Tokens, error) {
 	res, err := a.st.LoginEmail(in)
-	if err != nil {
-		a.log.Error("Failed to login", "error", err)
+	if err := a.checkCredentials(ctx, client.IP, in.Email, res, err, in.Password); err != nil {
 		return models.Tokens{}, err
 	}
 
-	check := hashing.CheckPasswordHash(res.Password, in.Password)
-	if !check {
-		a.log.Error("Invalid password")
-		return models.Tokens{}, errors.New("Invalid password")
+	return a.login(ctx, res, client)
+}
+
+func (a *authService) LoginUsername(ctx context.Context, in models.LoginUsernameRequest, client models.Client) (models.Tokens, error) {
+	res, err := a.st.LoginUsername(in)
+	if err := a.checkCredentials(ctx, client.IP, in.Username, res, err, in.Password); err != nil {
+		return models.Tokens{}, err
 	}
 
-	refreshToken, err := token.GenerateRefreshToken(res)
-	if err != nil {
-		a.log.Error("Failed to generate refresh token", "error", err)
+	return a.login(ctx, res, client)
+}
+
+// login finishes a login whose password has been checked. Users with
+// two-factor authentication get an MFA challenge instead of tokens.
+func (a *authService) login(ctx context.Context, user models.LoginResponse, client models.Client) (models.Tokens, error) {
+	tf, err := a.twoFactor.GetTwoFactor(ctx, user.Id)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		a.log.Error("Failed to get two-factor settings", "error", err)
 		return models.Tokens{}, err
 	}
+	if err == nil && tf.Enabled {
+		return a.challenge(ctx, user)
+	}
 
-	accessToken, err := token.GenerateAccessToken(res)
+	return a.newSession(ctx, user, client)
+}
+
+// newSession starts a new refresh token family for the user, which is listed
+// as a session of the client.
+func (a *authService) newSession(ctx context.Context, user models.LoginResponse, client models.Client) (models.Tokens, error) {
+	familyID, tokenID := uuid.NewString(), uuid.NewString()
+
+	err := a.tokens.CreateFamily(ctx, familyID, user.Id, tokenID, client, token.RefreshTokenTTL)
 	if err != nil {
-		a.log.Error("Failed to generate access token", "error", err)
+		a.log.Error("Failed to create refresh token family", "error", err)
 		return models.Tokens{}, err
 	}
 
-	response := models.Tokens{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
+	return a.issueTokens(user, familyID, tokenID)
+}
+
+// Refresh exchanges a refresh token for a new access/refresh pair of the same
+// family. Each refresh token can be used once; presenting one that was
+// already rotated revokes the whole family and its access tokens, logging
+// out both the legitimate holder and whoever replayed it.
+func (a *authService) Refresh(ctx context.Context, refreshToken string, client models.Client) (models.Tokens, error) {
+	claims, err := token.ExtractClaimsRefresh(refreshToken)
+	if err != nil || claims == nil {
+		a.log.Error("Failed to parse refresh token", "error", err)
+		return models.Tokens{}, ErrInvalidRefreshToken
+	}
+	if claims.FamilyID == "" || claims.StandardClaims.Id == "" {
+		// Issued before rotation existed.
+		a.log.Error("Refresh token has no family")
+		return models.Tokens{}, ErrInvalidRefreshToken
 	}
 
-	return response, nil
-}
+	revokedAt, err := a.revocations.UserRevokedAt(ctx, claims.ID)
+	if err != nil {
+		a.log.Error("Failed to check user revocation", "error", err)
+		return models.Tokens{}, err
+	}
+	if claims.IssuedBefore(revokedAt) {
+		a.log.Error("Refresh token issued before logout everywhere", "user_id", claims.ID)
+		return models.Tokens{}, ErrInvalidRefreshToken
+	}
 
-func (a *authService) LoginUsername(in models.LoginUsernameRequest) (models.Tokens, error) {
-	res, err := a.st.LoginUsername(in)
+	// The role, email and username are read again, so a refresh never
+	// carries claims the user no longer has.
+	user, err := a.st.UserByID(claims.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		a.log.Error("Refresh token of a deleted user", "user_id", claims.ID)
+		if err := a.tokens.RevokeFamily(ctx, claims.FamilyID); err != nil {
+			a.log.Error("Failed to revoke refresh token family", "error", err)
+		}
+		return models.Tokens{}, ErrInvalidRefreshToken
+	}
 	if err != nil {
-		a.log.Error("Failed to login", "error", err)
+		a.log.Error("Failed to get user", "error", err)
 		return models.Tokens{}, err
 	}
 
-	check := hashing.CheckPasswordHash(res.Password, in.Password)
-	log.Println(check)
-	if !check {
-		a.log.Error("Invalid password")
-		log.Println("\n----------", in.Password, res.Password, "\n---------")
-		return models.Tokens{}, errors.New("Invalid password")
+	tokenID := uuid.NewString()
+	err = a.tokens.RotateToken(ctx, claims.FamilyID, claims.ID, claims.StandardClaims.Id, tokenID, client.IP, token.RefreshTokenTTL)
+	if errors.Is(err, storage.ErrTokenReused) {
+		a.log.Error("Refused refresh token", "user_id", claims.ID, "family_id", claims.FamilyID, "error", err)
+		// The access tokens of the family may be in the wrong hands too.
+		if err := a.revocations.RevokeSession(context.WithoutCancel(ctx), claims.FamilyID); err != nil {
+			a.log.Error("Failed to revoke session", "family_id", claims.FamilyID, "error", err)
+		}
+		return models.Tokens{}, ErrInvalidRefreshToken
 	}
+	if errors.Is(err, storage.ErrTokenFamilyNotFound) {
+		a.log.Error("Refused refresh token", "user_id", claims.ID, "family_id", claims.FamilyID, "error", err)
+		return models.Tokens{}, ErrInvalidRefreshToken
+	}
+	if err != nil {
+		a.log.Error("Failed to rotate refresh token", "error", err)
+		return models.Tokens{}, err
+	}
+
+	return a.issueTokens(user, claims.FamilyID, tokenID)
+}
 
-	refreshToken, err := token.GenerateRefreshToken(res)
+func (a *authService) issueTokens(user models.LoginResponse, familyID, tokenID string) (models.Tokens, error) {
+	refreshToken, err := token.GenerateRefreshToken(user, familyID, tokenID)
 	if err != nil {
 		a.log.Error("Failed to generate refresh token", "error", err)
 		return models.Tokens{}, err
 	}
 
-	accessToken, err := token.GenerateAccessToken(res)
+	accessToken, err := token.GenerateAccessToken(user, familyID)
 	if err != nil {
 		a.log.Error("Failed to generate access token", "error", err)
 		return models.Tokens{}, err
@@ -110,3 +239,213 @@ func (a *authService) LoginUsername(in models.LoginUsernameRequest) (models.Toke
 
 	return response, nil
 }
+
+// Logout denies the access token for the rest of its lifetime and revokes the
+// refresh token family it was issued with. The refresh token is optional; a
+// client that lost it can still end the session by letting it expire.
+func (a *authService) Logout(ctx context.Context, accessToken, refreshToken string) error {
+	claims, err := token.ExtractClaimsAccess(accessToken)
+	if err != nil || claims == nil {
+		a.log.Error("Failed to parse access token", "error", err)
+		return ErrInvalidAccessToken
+	}
+
+	if claims.StandardClaims.Id != "" {
+		ttl := time.Until(time.Unix(claims.ExpiresAt, 0))
+		if err := a.revocations.RevokeToken(ctx, claims.StandardClaims.Id, ttl); err != nil {
+			a.log.Error("Failed to revoke access token", "error", err)
+			return err
+		}
+	} else {
+		// Issued before tokens carried a jti; only a user-wide cutoff can
+		// deny it.
+		if err := a.revocations.RevokeUser(ctx, claims.ID, time.Now()); err != nil {
+			a.log.Error("Failed to revoke user tokens", "error", err)
+			return err
+		}
+	}
+
+	if claims.FamilyID != "" {
+		// The access token names its session, so the refresh token is not
+		// needed to end it.
+		return a.endSession(ctx, claims.ID, claims.FamilyID)
+	}
+
+	if refreshToken == "" {
+		return nil
+	}
+
+	refresh, err := token.ExtractClaimsRefresh(refreshToken)
+	if err != nil || refresh == nil || refresh.ID != claims.ID || refresh.FamilyID == "" {
+		// The access token is already denied; a bad refresh token is not
+		// worth failing the logout for.
+		a.log.Warn("Ignoring invalid refresh token on logout", "user_id", claims.ID)
+		return nil
+	}
+
+	if err := a.tokens.RevokeFamily(ctx, refresh.FamilyID); err != nil {
+		a.log.Error("Failed to revoke refresh token family", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// authenticate parses an access token and checks it against the denylists.
+func (a *authService) authenticate(ctx context.Context, accessToken string) (*token.Claims, error) {
+	claims, err := token.ExtractClaimsAccess(accessToken)
+	if err != nil || claims == nil {
+		a.log.Error("Failed to parse access token", "error", err)
+		return nil, ErrInvalidAccessToken
+	}
+
+	if claims.StandardClaims.Id != "" {
+		revoked, err := a.revocations.IsTokenRevoked(ctx, claims.StandardClaims.Id)
+		if err != nil {
+			a.log.Error("Failed to check token revocation", "error", err)
+			return nil, err
+		}
+		if revoked {
+			return nil, ErrInvalidAccessToken
+		}
+	}
+
+	if claims.FamilyID != "" {
+		revoked, err := a.revocations.IsSessionRevoked(ctx, claims.FamilyID)
+		if err != nil {
+			a.log.Error("Failed to check session revocation", "error", err)
+			return nil, err
+		}
+		if revoked {
+			return nil, ErrInvalidAccessToken
+		}
+	}
+
+	revokedAt, err := a.revocations.UserRevokedAt(ctx, claims.ID)
+	if err != nil {
+		a.log.Error("Failed to check user revocation", "error", err)
+		return nil, err
+	}
+	if claims.IssuedBefore(revokedAt) {
+		return nil, ErrInvalidAccessToken
+	}
+
+	return claims, nil
+}
+
+// LogoutAll revokes every access and refresh token of the user issued so far.
+func (a *authService) LogoutAll(ctx context.Context, accessToken string) error {
+	claims, err := token.ExtractClaimsAccess(accessToken)
+	if err != nil || claims == nil {
+		a.log.Error("Failed to parse access token", "error", err)
+		return ErrInvalidAccessToken
+	}
+
+	if err := a.revocations.RevokeUser(ctx, claims.ID, time.Now()); err != nil {
+		a.log.Error("Failed to revoke user tokens", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// ForgotPassword issues a single-use reset token for the user with the given
+// email and returns the link to send them. It returns an empty link, and no
+// error, when no user has the email, so callers can answer both cases alike.
+func (a *authService) ForgotPassword(ctx context.Context, email string) (string, error) {
+	// Addresses differ only in case for the rate limit and the lookup alike.
+	email = normalizeEmail(email)
+
+	allowed, err := a.resets.AllowResetRequest(ctx, email)
+	if err != nil {
+		a.log.Error("Failed to check reset rate limit", "error", err)
+		return "", err
+	}
+	if !allowed {
+		return "", ErrTooManyResetRequests
+	}
+
+	userID, err := a.st.UserIDByEmail(email)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		a.log.Error("Failed to look up user by email", "error", err)
+		return "", err
+	}
+
+	resetToken, err := newOpaqueToken()
+	if err != nil {
+		a.log.Error("Failed to generate reset token", "error", err)
+		return "", err
+	}
+
+	err = a.resets.SetResetToken(ctx, userID, hashOpaqueToken(resetToken), resetTokenTTL)
+	if err != nil {
+		a.log.Error("Failed to store reset token", "error", err)
+		return "", err
+	}
+
+	link, err := url.Parse(config.Load().RESET_PASSWORD_URL)
+	if err != nil {
+		a.log.Error("Invalid reset password url", "error", err)
+		return "", err
+	}
+	query := link.Query()
+	query.Set("token", resetToken)
+	link.RawQuery = query.Encode()
+
+	return link.String(), nil
+}
+
+// ResetPassword sets a new password with a token from ForgotPassword. The
+// token is consumed even if the update fails, and every session of the user
+// is revoked afterwards.
+func (a *authService) ResetPassword(ctx context.Context, resetToken, newPassword string) error {
+	// Checked before the token is taken, so a rejected password does not
+	// cost the user their link.
+	if err := validatePassword(newPassword); err != nil {
+		return err
+	}
+
+	userID, err := a.resets.TakeResetToken(ctx, hashOpaqueToken(resetToken))
+	if errors.Is(err, storage.ErrResetTokenNotFound) {
+		return ErrInvalidResetToken
+	}
+	if err != nil {
+		a.log.Error("Failed to take reset token", "error", err)
+		return err
+	}
+
+	hash, err := hashing.HashPassword(newPassword)
+	if err != nil {
+		a.log.Error("Failed to hash password", "error", err)
+		return err
+	}
+
+	err = a.st.ResetPassword(userID, hash)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Deleted since the token was issued.
+		return ErrInvalidResetToken
+	}
+	if err != nil {
+		a.log.Error("Failed to reset password", "error", err)
+		return err
+	}
+
+	revokeUser(ctx, a.revocations, a.log, userID)
+	return nil
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// validatePassword returns ErrInvalidPassword for passwords that are too
+// short, or too long for bcrypt.
+func validatePassword(password string) error {
+	if len([]rune(password)) < minPasswordLength || len(password) > maxPasswordLength {
+		return ErrInvalidPassword
+	}
+	return nil
+}
@@ -0,0 +1,227 @@
+package service
+
+import (
+	"auth-service/pkg/hashing"
+	"auth-service/pkg/models"
+	"auth-service/pkg/token"
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRefreshRotatesAndDetectsReuse(t *testing.T) {
+	ctx := context.Background()
+	ta := newTestAuth(t)
+	user := models.LoginResponse{Id: "u1", Email: "a@example.com", Username: "alice", Role: "user"}
+	ta.users.add(user)
+	client := models.Client{IP: "203.0.113.1", UserAgent: "test"}
+
+	first, err := ta.newSession(ctx, user, client)
+	if err != nil {
+		t.Fatalf("newSession: %v", err)
+	}
+
+	second, err := ta.Refresh(ctx, first.RefreshToken, client)
+	if err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	if second.RefreshToken == first.RefreshToken {
+		t.Fatal("refresh token was not rotated")
+	}
+
+	// Replaying the rotated token revokes the family, so the token the
+	// legitimate holder got is refused too.
+	if _, err := ta.Refresh(ctx, first.RefreshToken, client); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Fatalf("replayed Refresh: got %v, want ErrInvalidRefreshToken", err)
+	}
+	if _, err := ta.Refresh(ctx, second.RefreshToken, client); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Fatalf("Refresh after reuse: got %v, want ErrInvalidRefreshToken", err)
+	}
+
+	// The access tokens of the family are revoked with it.
+	for _, access := range []string{first.AccessToken, second.AccessToken} {
+		if _, err := ta.authenticate(ctx, access); !errors.Is(err, ErrInvalidAccessToken) {
+			t.Errorf("authenticate after reuse: got %v, want ErrInvalidAccessToken", err)
+		}
+	}
+}
+
+func TestRefreshReuseKeepsOtherSessions(t *testing.T) {
+	ctx := context.Background()
+	ta := newTestAuth(t)
+	user := models.LoginResponse{Id: "u1", Email: "a@example.com", Username: "alice", Role: "user"}
+	ta.users.add(user)
+
+	stolen, err := ta.newSession(ctx, user, models.Client{})
+	if err != nil {
+		t.Fatalf("newSession: %v", err)
+	}
+	other, err := ta.newSession(ctx, user, models.Client{})
+	if err != nil {
+		t.Fatalf("newSession: %v", err)
+	}
+
+	if _, err := ta.Refresh(ctx, stolen.RefreshToken, models.Client{}); err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	if _, err := ta.Refresh(ctx, stolen.RefreshToken, models.Client{}); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Fatalf("replayed Refresh: got %v, want ErrInvalidRefreshToken", err)
+	}
+
+	if _, err := ta.authenticate(ctx, other.AccessToken); err != nil {
+		t.Errorf("authenticate on another session: %v", err)
+	}
+	if _, err := ta.Refresh(ctx, other.RefreshToken, models.Client{}); err != nil {
+		t.Errorf("Refresh on another session: %v", err)
+	}
+}
+
+func TestRefreshReloadsUser(t *testing.T) {
+	ctx := context.Background()
+	ta := newTestAuth(t)
+	user := models.LoginResponse{Id: "u1", Email: "a@example.com", Username: "alice", Role: "admin"}
+	ta.users.add(user)
+
+	tokens, err := ta.newSession(ctx, user, models.Client{})
+	if err != nil {
+		t.Fatalf("newSession: %v", err)
+	}
+
+	demoted := user
+	demoted.Role = "user"
+	demoted.Email = "new@example.com"
+	ta.users.add(demoted)
+
+	tokens, err = ta.Refresh(ctx, tokens.RefreshToken, models.Client{})
+	if err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	claims, err := token.ExtractClaimsAccess(tokens.AccessToken)
+	if err != nil {
+		t.Fatalf("ExtractClaimsAccess: %v", err)
+	}
+	if claims.Role != "user" || claims.Email != "new@example.com" {
+		t.Fatalf("claims carry role %q and email %q, want the current ones", claims.Role, claims.Email)
+	}
+}
+
+func TestRefreshDeletedUser(t *testing.T) {
+	ctx := context.Background()
+	ta := newTestAuth(t)
+	user := models.LoginResponse{Id: "u1", Username: "alice", Role: "user"}
+
+	tokens, err := ta.newSession(ctx, user, models.Client{})
+	if err != nil {
+		t.Fatalf("newSession: %v", err)
+	}
+
+	if _, err := ta.Refresh(ctx, tokens.RefreshToken, models.Client{}); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Fatalf("Refresh: got %v, want ErrInvalidRefreshToken", err)
+	}
+	sessions, err := ta.tokens.ListSessions(ctx, user.Id)
+	if err != nil {
+		t.Fatalf("ListSessions: %v", err)
+	}
+	if len(sessions) != 0 {
+		t.Fatalf("deleted user still has %d sessions", len(sessions))
+	}
+}
+
+func TestLogoutAllCutoff(t *testing.T) {
+	ctx := context.Background()
+	ta := newTestAuth(t)
+	user := models.LoginResponse{Id: "u1", Username: "alice", Role: "user"}
+	ta.users.add(user)
+
+	before, err := ta.newSession(ctx, user, models.Client{})
+	if err != nil {
+		t.Fatalf("newSession: %v", err)
+	}
+	time.Sleep(2 * time.Millisecond)
+	if err := ta.LogoutAll(ctx, before.AccessToken); err != nil {
+		t.Fatalf("LogoutAll: %v", err)
+	}
+	time.Sleep(2 * time.Millisecond)
+
+	// Logging in again at once, most likely within the same second, gives
+	// tokens that work.
+	after, err := ta.newSession(ctx, user, models.Client{})
+	if err != nil {
+		t.Fatalf("newSession: %v", err)
+	}
+	if _, err := ta.authenticate(ctx, after.AccessToken); err != nil {
+		t.Fatalf("authenticate after cutoff: %v", err)
+	}
+	if _, err := ta.Refresh(ctx, after.RefreshToken, models.Client{}); err != nil {
+		t.Fatalf("Refresh after cutoff: %v", err)
+	}
+
+	if _, err := ta.authenticate(ctx, before.AccessToken); !errors.Is(err, ErrInvalidAccessToken) {
+		t.Fatalf("authenticate before cutoff: got %v, want ErrInvalidAccessToken", err)
+	}
+	if _, err := ta.Refresh(ctx, before.RefreshToken, models.Client{}); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Fatalf("Refresh before cutoff: got %v, want ErrInvalidRefreshToken", err)
+	}
+}
+
+func TestForgotPasswordNormalizesEmail(t *testing.T) {
+	ctx := context.Background()
+	ta := newTestAuth(t)
+	ta.users.add(models.LoginResponse{Id: "u1", Email: "Alice@Example.com", Username: "alice"})
+
+	for _, email := range []string{"alice@example.com", " ALICE@example.com", "Alice@Example.com "} {
+		link, err := ta.ForgotPassword(ctx, email)
+		if err != nil {
+			t.Fatalf("ForgotPassword(%q): %v", email, err)
+		}
+		if link == "" {
+			t.Fatalf("ForgotPassword(%q) found no account", email)
+		}
+	}
+
+	// The spellings share one rate limit.
+	if _, err := ta.ForgotPassword(ctx, "aLiCe@example.com"); !errors.Is(err, ErrTooManyResetRequests) {
+		t.Fatalf("fourth ForgotPassword: got %v, want ErrTooManyResetRequests", err)
+	}
+}
+
+func TestResetPassword(t *testing.T) {
+	ctx := context.Background()
+	ta := newTestAuth(t)
+	ta.users.add(models.LoginResponse{Id: "u1", Email: "alice@example.com", Username: "alice"})
+
+	link, err := ta.ForgotPassword(ctx, "alice@example.com")
+	if err != nil {
+		t.Fatalf("ForgotPassword: %v", err)
+	}
+	parsed, err := url.Parse(link)
+	if err != nil {
+		t.Fatalf("parse link: %v", err)
+	}
+	resetToken := parsed.Query().Get("token")
+
+	// A rejected password leaves the link usable.
+	for _, password := range []string{"short", strings.Repeat("x", maxPasswordLength+1)} {
+		if err := ta.ResetPassword(ctx, resetToken, password); !errors.Is(err, ErrInvalidPassword) {
+			t.Fatalf("ResetPassword with %d characters: got %v, want ErrInvalidPassword", len(password), err)
+		}
+	}
+
+	if err := ta.ResetPassword(ctx, resetToken, "correct horse"); err != nil {
+		t.Fatalf("ResetPassword: %v", err)
+	}
+	if !hashing.CheckPasswordHash(ta.users.users["u1"].Password, "correct horse") {
+		t.Fatal("password was not changed")
+	}
+	revokedAt, err := ta.revocations.UserRevokedAt(ctx, "u1")
+	if err != nil || revokedAt.IsZero() {
+		t.Fatalf("tokens were not revoked: %v, %v", revokedAt, err)
+	}
+
+	if err := ta.ResetPassword(ctx, resetToken, "correct horse"); !errors.Is(err, ErrInvalidResetToken) {
+		t.Fatalf("second ResetPassword: got %v, want ErrInvalidResetToken", err)
+	}
+}
@@ -4,8 +4,14 @@ import (
 	pb "auth-service/genproto/user"
 	"auth-service/pkg/hashing"
 	"auth-service/storage"
+	"common/interceptor"
 	"context"
 	"log/slog"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type UserServices interface {
@@ -28,18 +34,38 @@ type UserServices interface {
 	GetUserFollowers(ctx context.Context, in *pb.Id) (*pb.Count, error)
 	GetUserFollows(ctx context.Context, in *pb.Id) (*pb.Count, error)
 	MostPopularUser(ctx context.Context, in *pb.Void) (*pb.UserResponse, error)
+	SuggestUsers(ctx context.Context, in *pb.Id) (*pb.Suggestions, error)
+	SearchUsers(ctx context.Context, in *pb.SearchReq) (*pb.SearchResults, error)
+	Block(ctx context.Context, in *pb.BlockReq) (*pb.Void, error)
+	Unblock(ctx context.Context, in *pb.BlockReq) (*pb.Void, error)
+	GetRelationships(ctx context.Context, in *pb.RelationshipsReq) (*pb.Relationships, error)
+	GetMutualFollowers(ctx context.Context, in *pb.MutualFollowersReq) (*pb.MutualFollowers, error)
 }
 
 type UserService struct {
 	pb.UnimplementedUserServiceServer
-	st  storage.UserStorage
-	log *slog.Logger
+	st          storage.UserStorage
+	revocations storage.RevocationStorage
+	// deletionGrace is how long a requested account deletion can still be
+	// cancelled.
+	deletionGrace time.Duration
+	// popularWindow is how far back MostPopularUser and SuggestUsers count
+	// new followers.
+	popularWindow time.Duration
+	// usernameCooldown is how long after changing their username a user
+	// has to wait to change it again.
+	usernameCooldown time.Duration
+	log              *slog.Logger
 }
 
-func NewUserService(st storage.UserStorage, logger *slog.Logger) *UserService {
+func NewUserService(st storage.UserStorage, revocations storage.RevocationStorage, deletionGrace, popularWindow, usernameCooldown time.Duration, logger *slog.Logger) *UserService {
 	return &UserService{
-		st:  st,
-		log: logger,
+		st:               st,
+		revocations:      revocations,
+		deletionGrace:    deletionGrace,
+		popularWindow:    popularWindow,
+		usernameCooldown: usernameCooldown,
+		log:              logger,
 	}
 }
 
@@ -71,6 +97,17 @@ func (us *UserService) GetProfile(ctx context.Context, in *pb.Id) (*pb.GetProfil
 }
 
 func (us *UserService) UpdateProfile(ctx context.Context, in *pb.UpdateProfileRequest) (*pb.UserResponse, error) {
+	if in.Username != "" {
+		current, _, err := us.st.UsernameState(ctx, in.UserId)
+		if err != nil {
+			us.log.Error("failed to get username", "error", err)
+			return nil, err
+		}
+		if !strings.EqualFold(current, normalizeUsername(in.Username)) {
+			return nil, status.Error(codes.FailedPrecondition, "username is changed with ChangeUsername")
+		}
+	}
+
 	res, err := us.st.UpdateProfile(in)
 	if err != nil {
 		us.log.Error("failed to update user", "error", err)
@@ -80,6 +117,10 @@ func (us *UserService) UpdateProfile(ctx context.Context, in *pb.UpdateProfileRe
 }
 
 func (us *UserService) ChangePassword(ctx context.Context, in *pb.ChangePasswordRequest) (*pb.ChangePasswordResponse, error) {
+	if err := validatePassword(in.NewPassword); err != nil {
+		return nil, err
+	}
+
 	hash, err := hashing.HashPassword(in.NewPassword)
 	if err != nil {
 		us.log.Error("Failed to hash password", "error", err)
@@ -93,6 +134,9 @@ func (us *UserService) ChangePassword(ctx context.Context, in *pb.ChangePassword
 		us.log.Error("failed to change password", "error", err)
 		return nil, err
 	}
+
+	// Whoever held the old password may also hold a token.
+	revokeUser(ctx, us.revocations, us.log, in.UserId)
 	return res, nil
 }
 
@@ -105,12 +149,18 @@ func (us *UserService) ChangeProfileImage(ctx context.Context, in *pb.URL) (*pb.
 	return res, nil
 }
 
+// FetchUsers lists accounts. Emails are left out unless an admin asks.
 func (us *UserService) FetchUsers(ctx context.Context, in *pb.Filter) (*pb.UserResponses, error) {
 	res, err := us.st.FetchUsers(in)
 	if err != nil {
 		us.log.Error("failed to fetch users", "error", err)
 		return nil, err
 	}
+	if caller, ok := interceptor.CallerFrom(ctx); !ok || !caller.IsAdmin() {
+		for _, user := range res.Users {
+			user.Email = ""
+		}
+	}
 	return res, nil
 }
 
@@ -156,6 +206,8 @@ func (us *UserService) DeleteUser(ctx context.Context, in *pb.Id) (*pb.Void, err
 		us.log.Error("failed to delete user", "error", err)
 		return nil, err
 	}
+
+	revokeUser(ctx, us.revocations, us.log, in.UserId)
 	return res, nil
 }
 
@@ -195,10 +247,46 @@ func (us *UserService) GetUserFollows(ctx context.Context, in *pb.Id) (*pb.Count
 	return res, nil
 }
 func (us *UserService) MostPopularUser(ctx context.Context, in *pb.Void) (*pb.UserResponse, error) {
-	res, err := us.st.MostPopularUser(in)
+	res, err := us.st.MostPopularUser(ctx, time.Now().Add(-us.popularWindow))
 	if err != nil {
 		us.log.Error("failed to most popular user", "error", err)
 		return nil, err
 	}
 	return res, nil
 }
+
+// suggestionLimit is how many users SuggestUsers returns.
+const suggestionLimit = 20
+
+// SuggestUsers returns who the user may want to follow: people followed by
+// those they follow first, then their followers and popular users.
+func (us *UserService) SuggestUsers(ctx context.Context, in *pb.Id) (*pb.Suggestions, error) {
+	res, err := us.st.SuggestUsers(ctx, in.UserId, time.Now().Add(-us.popularWindow), suggestionLimit)
+	if err != nil {
+		us.log.Error("failed to suggest users", "error", err)
+		return nil, err
+	}
+	return res, nil
+}
+
+func (us *UserService) Block(ctx context.Context, in *pb.BlockReq) (*pb.Void, error) {
+	if in.BlockerId == in.BlockedId {
+		return nil, status.Error(codes.InvalidArgument, "cannot block yourself")
+	}
+
+	res, err := us.st.Block(in)
+	if err != nil {
+		us.log.Error("failed to block user", "error", err)
+		return nil, err
+	}
+	return res, nil
+}
+
+func (us *UserService) Unblock(ctx context.Context, in *pb.BlockReq) (*pb.Void, error) {
+	res, err := us.st.Unblock(in)
+	if err != nil {
+		us.log.Error("failed to unblock user", "error", err)
+		return nil, err
+	}
+	return res, nil
+}
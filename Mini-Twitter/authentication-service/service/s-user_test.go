@@ -0,0 +1,34 @@
+package service
+
+import (
+	pb "auth-service/genproto/user"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestChangePasswordValidatesLength(t *testing.T) {
+	tu := newTestUsers(t)
+
+	for _, password := range []string{"short", strings.Repeat("x", maxPasswordLength+1)} {
+		_, err := tu.ChangePassword(context.Background(), &pb.ChangePasswordRequest{
+			UserId:          "u1",
+			CurrentPassword: "correct horse",
+			NewPassword:     password,
+		})
+		if !errors.Is(err, ErrInvalidPassword) {
+			t.Fatalf("ChangePassword with %d characters: got %v, want ErrInvalidPassword", len(password), err)
+		}
+		if code := status.Code(Status(err)); code != codes.InvalidArgument {
+			t.Errorf("ChangePassword with %d characters: got code %v, want InvalidArgument", len(password), code)
+		}
+	}
+
+	if tu.revoked(t, "u1") {
+		t.Error("tokens revoked although the password was not changed")
+	}
+}
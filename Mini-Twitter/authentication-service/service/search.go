@@ -0,0 +1,50 @@
+package service
+
+import (
+	pb "auth-service/genproto/user"
+	"context"
+	"strings"
+	"unicode/utf8"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 50
+	// maxSearchQuery bounds the query length, as similarity is computed
+	// against every candidate.
+	maxSearchQuery = 64
+)
+
+// SearchUsers finds users by username, first or last name. A prefix search
+// is for typeahead; otherwise similar names match as well.
+func (us *UserService) SearchUsers(ctx context.Context, in *pb.SearchReq) (*pb.SearchResults, error) {
+	query := strings.TrimPrefix(strings.TrimSpace(in.Query), "@")
+	if query == "" {
+		return nil, status.Error(codes.InvalidArgument, "query is required")
+	}
+	if utf8.RuneCountInString(query) > maxSearchQuery {
+		return nil, status.Errorf(codes.InvalidArgument, "query is longer than %d characters", maxSearchQuery)
+	}
+
+	req := &pb.SearchReq{
+		Query:  query,
+		Prefix: in.Prefix,
+		Page:   max(in.Page, 1),
+		Limit:  in.Limit,
+		UserId: in.UserId,
+	}
+	if req.Limit <= 0 {
+		req.Limit = defaultSearchLimit
+	}
+	req.Limit = min(req.Limit, maxSearchLimit)
+
+	res, err := us.st.SearchUsers(ctx, req)
+	if err != nil {
+		us.log.Error("failed to search users", "error", err)
+		return nil, err
+	}
+	return res, nil
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"auth-service/pkg/models"
+	"auth-service/storage"
+	"context"
+	"errors"
+)
+
+// ErrSessionNotFound is returned when revoking a session the caller does not
+// have.
+var ErrSessionNotFound = errors.New("session not found")
+
+// Sessions lists where the caller is logged in, most recently used first.
+func (a *authService) Sessions(ctx context.Context, accessToken string) ([]models.Session, error) {
+	claims, err := a.authenticate(ctx, accessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, err := a.tokens.ListSessions(ctx, claims.ID)
+	if err != nil {
+		a.log.Error("Failed to list sessions", "error", err)
+		return nil, err
+	}
+
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID == claims.FamilyID
+	}
+
+	return sessions, nil
+}
+
+// RevokeSession logs the caller out of one of their sessions. Its refresh
+// token stops working at once, and so do the access tokens issued for it.
+func (a *authService) RevokeSession(ctx context.Context, accessToken, sessionID string) error {
+	claims, err := a.authenticate(ctx, accessToken)
+	if err != nil {
+		return err
+	}
+
+	err = a.tokens.DeleteSession(ctx, claims.ID, sessionID)
+	if errors.Is(err, storage.ErrSessionNotFound) {
+		return ErrSessionNotFound
+	}
+	if err != nil {
+		a.log.Error("Failed to delete session", "error", err)
+		return err
+	}
+
+	if err := a.revocations.RevokeSession(ctx, sessionID); err != nil {
+		a.log.Error("Failed to revoke session", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// endSession ends a session of the user that may already have expired.
+func (a *authService) endSession(ctx context.Context, userID, sessionID string) error {
+	err := a.tokens.DeleteSession(ctx, userID, sessionID)
+	if err != nil && !errors.Is(err, storage.ErrSessionNotFound) {
+		a.log.Error("Failed to delete session", "error", err)
+		return err
+	}
+
+	if err := a.revocations.RevokeSession(ctx, sessionID); err != nil {
+		a.log.Error("Failed to revoke session", "error", err)
+		return err
+	}
+
+	return nil
+}
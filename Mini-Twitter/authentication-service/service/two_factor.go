@@ -0,0 +1,279 @@
+package service
+
+import (
+	"auth-service/pkg/models"
+	"auth-service/storage"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+)
+
+const (
+	totpIssuer = "Mini-Twitter"
+
+	// challengeTTL is how long the second login step can take.
+	challengeTTL = 5 * time.Minute
+	// usedCodeTTL covers every step a code is accepted in, so it cannot be
+	// replayed while still valid.
+	usedCodeTTL = 90 * time.Second
+
+	recoveryCodeCount = 10
+)
+
+var (
+	ErrTwoFactorEnabled     = storage.ErrTwoFactorEnabled
+	ErrTwoFactorNotEnrolled = errors.New("two-factor authentication is not enrolled")
+	ErrInvalidTwoFactorCode = errors.New("invalid two-factor code")
+	ErrInvalidMFAChallenge  = errors.New("invalid or expired mfa token")
+	ErrForbidden            = errors.New("forbidden")
+)
+
+var totpOpts = totp.ValidateOpts{
+	Period:    30,
+	Skew:      1,
+	Digits:    otp.DigitsSix,
+	Algorithm: otp.AlgorithmSHA1,
+}
+
+// EnrollTwoFactor generates a new TOTP secret for the caller. It is not used
+// for logins until ConfirmTwoFactor proves the authenticator app has it.
+func (a *authService) EnrollTwoFactor(ctx context.Context, accessToken string) (models.TwoFactorEnrollment, error) {
+	claims, err := a.authenticate(ctx, accessToken)
+	if err != nil {
+		return models.TwoFactorEnrollment{}, err
+	}
+
+	accountName := claims.Email
+	if accountName == "" {
+		accountName = claims.Username
+	}
+
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      totpIssuer,
+		AccountName: accountName,
+		Period:      totpOpts.Period,
+		Digits:      totpOpts.Digits,
+		Algorithm:   totpOpts.Algorithm,
+	})
+	if err != nil {
+		a.log.Error("Failed to generate totp secret", "error", err)
+		return models.TwoFactorEnrollment{}, err
+	}
+
+	err = a.twoFactor.SaveSecret(ctx, claims.ID, key.Secret())
+	if err != nil {
+		a.log.Error("Failed to save totp secret", "error", err)
+		return models.TwoFactorEnrollment{}, err
+	}
+
+	return models.TwoFactorEnrollment{
+		Secret:     key.Secret(),
+		OtpauthURI: key.URL(),
+	}, nil
+}
+
+// ConfirmTwoFactor enables two-factor authentication once the caller enters a
+// valid code, and returns recovery codes. They are only shown this once.
+func (a *authService) ConfirmTwoFactor(ctx context.Context, accessToken, code string) (models.RecoveryCodes, error) {
+	claims, err := a.authenticate(ctx, accessToken)
+	if err != nil {
+		return models.RecoveryCodes{}, err
+	}
+
+	tf, err := a.twoFactor.GetTwoFactor(ctx, claims.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.RecoveryCodes{}, ErrTwoFactorNotEnrolled
+	}
+	if err != nil {
+		a.log.Error("Failed to get two-factor settings", "error", err)
+		return models.RecoveryCodes{}, err
+	}
+	if tf.Enabled {
+		return models.RecoveryCodes{}, ErrTwoFactorEnabled
+	}
+
+	if err := a.checkCode(ctx, claims.ID, tf.Secret, code); err != nil {
+		return models.RecoveryCodes{}, err
+	}
+
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		codes[i], err = newRecoveryCode()
+		if err != nil {
+			a.log.Error("Failed to generate recovery code", "error", err)
+			return models.RecoveryCodes{}, err
+		}
+		hashes[i] = hashRecoveryCode(codes[i])
+	}
+
+	err = a.twoFactor.Enable(ctx, claims.ID, hashes)
+	if errors.Is(err, ErrTwoFactorEnabled) {
+		// Confirmed by a concurrent request, whose recovery codes stand.
+		return models.RecoveryCodes{}, err
+	}
+	if err != nil {
+		a.log.Error("Failed to enable two-factor authentication", "error", err)
+		return models.RecoveryCodes{}, err
+	}
+
+	return models.RecoveryCodes{RecoveryCodes: codes}, nil
+}
+
+// LoginTwoFactor completes a login that was answered with an MFA challenge.
+func (a *authService) LoginTwoFactor(ctx context.Context, in models.TwoFactorLoginRequest, client models.Client) (models.Tokens, error) {
+	if in.Code == "" && in.RecoveryCode == "" {
+		return models.Tokens{}, ErrInvalidTwoFactorCode
+	}
+
+	challengeHash := hashOpaqueToken(in.MFAToken)
+	user, err := a.challenges.AttemptChallenge(ctx, challengeHash)
+	if errors.Is(err, storage.ErrChallengeNotFound) {
+		return models.Tokens{}, ErrInvalidMFAChallenge
+	}
+	if err != nil {
+		a.log.Error("Failed to get mfa challenge", "error", err)
+		return models.Tokens{}, err
+	}
+
+	if in.RecoveryCode != "" {
+		ok, err := a.twoFactor.UseRecoveryCode(ctx, user.Id, hashRecoveryCode(in.RecoveryCode))
+		if err != nil {
+			a.log.Error("Failed to use recovery code", "error", err)
+			return models.Tokens{}, err
+		}
+		if !ok {
+			return models.Tokens{}, ErrInvalidTwoFactorCode
+		}
+	} else {
+		tf, err := a.twoFactor.GetTwoFactor(ctx, user.Id)
+		if errors.Is(err, sql.ErrNoRows) {
+			// Reset by an admin since the password step.
+			return models.Tokens{}, ErrInvalidMFAChallenge
+		}
+		if err != nil {
+			a.log.Error("Failed to get two-factor settings", "error", err)
+			return models.Tokens{}, err
+		}
+		if err := a.checkCode(ctx, user.Id, tf.Secret, in.Code); err != nil {
+			return models.Tokens{}, err
+		}
+	}
+
+	if err := a.challenges.DeleteChallenge(ctx, challengeHash); err != nil {
+		a.log.Error("Failed to delete mfa challenge", "error", err)
+		return models.Tokens{}, err
+	}
+
+	return a.newSession(ctx, user, client)
+}
+
+// ResetTwoFactor lets an admin turn off two-factor authentication for a user
+// who lost both their authenticator and their recovery codes, up to their own
+// role. A lost authenticator may be in someone else's hands along with the
+// password, so every session of the user is revoked too.
+func (a *authService) ResetTwoFactor(ctx context.Context, accessToken, userID string) error {
+	claims, err := a.authenticate(ctx, accessToken)
+	if err != nil {
+		return err
+	}
+	actor, err := a.adminRank(claims)
+	if err != nil {
+		return err
+	}
+
+	target, err := a.st.UserByID(userID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		a.log.Error("Failed to get user", "error", err)
+		return err
+	}
+	if roleRank[target.Role] > actor {
+		return ErrForbidden
+	}
+
+	if err := a.twoFactor.Disable(ctx, userID); err != nil {
+		a.log.Error("Failed to reset two-factor authentication", "error", err)
+		return err
+	}
+	revokeUser(ctx, a.revocations, a.log, userID)
+
+	a.log.Info("Two-factor authentication reset", "user_id", userID, "admin_id", claims.ID)
+	return nil
+}
+
+// challenge hands out the opaque token that stands for a half-finished login.
+// It is not a JWT, so it can never pass as an access token.
+func (a *authService) challenge(ctx context.Context, user models.LoginResponse) (models.Tokens, error) {
+	mfaToken, err := newOpaqueToken()
+	if err != nil {
+		a.log.Error("Failed to generate mfa token", "error", err)
+		return models.Tokens{}, err
+	}
+
+	err = a.challenges.CreateChallenge(ctx, hashOpaqueToken(mfaToken), user, challengeTTL)
+	if err != nil {
+		a.log.Error("Failed to create mfa challenge", "error", err)
+		return models.Tokens{}, err
+	}
+
+	return models.Tokens{MFAToken: mfaToken}, nil
+}
+
+// checkCode validates a TOTP code and makes sure it has not been used yet.
+func (a *authService) checkCode(ctx context.Context, userID, secret, code string) error {
+	ok, err := totp.ValidateCustom(code, secret, time.Now().UTC(), totpOpts)
+	if err != nil || !ok {
+		return ErrInvalidTwoFactorCode
+	}
+
+	fresh, err := a.challenges.MarkCodeUsed(ctx, userID, code, usedCodeTTL)
+	if err != nil {
+		a.log.Error("Failed to mark totp code used", "error", err)
+		return err
+	}
+	if !fresh {
+		return ErrInvalidTwoFactorCode
+	}
+
+	return nil
+}
+
+func newOpaqueToken() (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+func hashOpaqueToken(t string) string {
+	sum := sha256.Sum256([]byte(t))
+	return hex.EncodeToString(sum[:])
+}
+
+// newRecoveryCode returns 80 random bits as xxxx-xxxx-xxxx-xxxx.
+func newRecoveryCode() (string, error) {
+	raw := make([]byte, 10)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	code := strings.ToLower(base32.StdEncoding.EncodeToString(raw))
+	return code[0:4] + "-" + code[4:8] + "-" + code[8:12] + "-" + code[12:16], nil
+}
+
+// hashRecoveryCode ignores case, dashes and spaces, so codes can be typed the
+// way they are written down.
+func hashRecoveryCode(code string) string {
+	code = strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	return hashOpaqueToken(code)
+}
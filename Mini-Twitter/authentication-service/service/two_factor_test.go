@@ -0,0 +1,193 @@
+package service
+
+import (
+	"auth-service/pkg/models"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pquerna/otp/totp"
+)
+
+func totpCode(t *testing.T, secret string, at time.Time) string {
+	t.Helper()
+	code, err := totp.GenerateCodeCustom(secret, at, totp.ValidateOpts{
+		Period:    totpOpts.Period,
+		Digits:    totpOpts.Digits,
+		Algorithm: totpOpts.Algorithm,
+	})
+	if err != nil {
+		t.Fatalf("GenerateCodeCustom: %v", err)
+	}
+	return code
+}
+
+// enrolled returns a user with two-factor authentication enabled, its secret
+// and its recovery codes.
+func enrolled(t *testing.T, ta *testAuth, user models.LoginResponse) (string, []string) {
+	t.Helper()
+	ctx := context.Background()
+	ta.users.add(user)
+
+	tokens, err := ta.newSession(ctx, user, models.Client{})
+	if err != nil {
+		t.Fatalf("newSession: %v", err)
+	}
+	enrollment, err := ta.EnrollTwoFactor(ctx, tokens.AccessToken)
+	if err != nil {
+		t.Fatalf("EnrollTwoFactor: %v", err)
+	}
+	codes, err := ta.ConfirmTwoFactor(ctx, tokens.AccessToken, totpCode(t, enrollment.Secret, time.Now()))
+	if err != nil {
+		t.Fatalf("ConfirmTwoFactor: %v", err)
+	}
+	if len(codes.RecoveryCodes) != recoveryCodeCount {
+		t.Fatalf("got %d recovery codes, want %d", len(codes.RecoveryCodes), recoveryCodeCount)
+	}
+
+	if _, err := ta.ConfirmTwoFactor(ctx, tokens.AccessToken, totpCode(t, enrollment.Secret, time.Now())); !errors.Is(err, ErrTwoFactorEnabled) {
+		t.Fatalf("second ConfirmTwoFactor: got %v, want ErrTwoFactorEnabled", err)
+	}
+	return enrollment.Secret, codes.RecoveryCodes
+}
+
+func mfaToken(t *testing.T, ta *testAuth, user models.LoginResponse) string {
+	t.Helper()
+	tokens, err := ta.login(context.Background(), user, models.Client{})
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	if tokens.AccessToken != "" || tokens.MFAToken == "" {
+		t.Fatal("login with two-factor authentication did not answer with a challenge")
+	}
+	return tokens.MFAToken
+}
+
+func TestLoginTwoFactorCode(t *testing.T) {
+	ctx := context.Background()
+	ta := newTestAuth(t)
+	user := models.LoginResponse{Id: "u1", Username: "alice", Role: RoleUser}
+	secret, _ := enrolled(t, ta, user)
+
+	// The code used to confirm cannot be replayed.
+	challenge := mfaToken(t, ta, user)
+	used := models.TwoFactorLoginRequest{MFAToken: challenge, Code: totpCode(t, secret, time.Now())}
+	if _, err := ta.LoginTwoFactor(ctx, used, models.Client{}); !errors.Is(err, ErrInvalidTwoFactorCode) {
+		t.Fatalf("replayed code: got %v, want ErrInvalidTwoFactorCode", err)
+	}
+
+	// The next code is within the allowed skew.
+	next := models.TwoFactorLoginRequest{MFAToken: challenge, Code: totpCode(t, secret, time.Now().Add(30*time.Second))}
+	tokens, err := ta.LoginTwoFactor(ctx, next, models.Client{})
+	if err != nil {
+		t.Fatalf("LoginTwoFactor: %v", err)
+	}
+	if tokens.AccessToken == "" || tokens.RefreshToken == "" {
+		t.Fatal("LoginTwoFactor gave no tokens")
+	}
+
+	// The challenge is gone once answered.
+	if _, err := ta.LoginTwoFactor(ctx, next, models.Client{}); !errors.Is(err, ErrInvalidMFAChallenge) {
+		t.Fatalf("answered challenge: got %v, want ErrInvalidMFAChallenge", err)
+	}
+}
+
+func TestLoginTwoFactorChallengeAttempts(t *testing.T) {
+	ctx := context.Background()
+	ta := newTestAuth(t)
+	user := models.LoginResponse{Id: "u1", Username: "alice", Role: RoleUser}
+	secret, _ := enrolled(t, ta, user)
+
+	challenge := mfaToken(t, ta, user)
+	wrong := models.TwoFactorLoginRequest{MFAToken: challenge, Code: "000000"}
+	var err error
+	for i := 0; i < 10 && !errors.Is(err, ErrInvalidMFAChallenge); i++ {
+		_, err = ta.LoginTwoFactor(ctx, wrong, models.Client{})
+	}
+	if !errors.Is(err, ErrInvalidMFAChallenge) {
+		t.Fatalf("guessing never used up the challenge: %v", err)
+	}
+
+	right := models.TwoFactorLoginRequest{MFAToken: challenge, Code: totpCode(t, secret, time.Now().Add(30*time.Second))}
+	if _, err := ta.LoginTwoFactor(ctx, right, models.Client{}); !errors.Is(err, ErrInvalidMFAChallenge) {
+		t.Fatalf("used up challenge: got %v, want ErrInvalidMFAChallenge", err)
+	}
+}
+
+func TestLoginTwoFactorRecoveryCode(t *testing.T) {
+	ctx := context.Background()
+	ta := newTestAuth(t)
+	user := models.LoginResponse{Id: "u1", Username: "alice", Role: RoleUser}
+	_, codes := enrolled(t, ta, user)
+
+	// Codes can be typed in upper case and with spaces for dashes.
+	typed := models.TwoFactorLoginRequest{MFAToken: mfaToken(t, ta, user), RecoveryCode: strings.ToUpper(strings.ReplaceAll(codes[0], "-", " "))}
+	if _, err := ta.LoginTwoFactor(ctx, typed, models.Client{}); err != nil {
+		t.Fatalf("LoginTwoFactor with recovery code: %v", err)
+	}
+
+	again := models.TwoFactorLoginRequest{MFAToken: mfaToken(t, ta, user), RecoveryCode: codes[0]}
+	if _, err := ta.LoginTwoFactor(ctx, again, models.Client{}); !errors.Is(err, ErrInvalidTwoFactorCode) {
+		t.Fatalf("reused recovery code: got %v, want ErrInvalidTwoFactorCode", err)
+	}
+}
+
+func TestResetTwoFactor(t *testing.T) {
+	ctx := context.Background()
+	ta := newTestAuth(t)
+	target := models.LoginResponse{Id: "target", Username: "target", Role: RoleAdmin}
+	enrolled(t, ta, target)
+
+	session := func(user models.LoginResponse) string {
+		ta.users.add(user)
+		tokens, err := ta.newSession(ctx, user, models.Client{})
+		if err != nil {
+			t.Fatalf("newSession: %v", err)
+		}
+		return tokens.AccessToken
+	}
+	user := session(models.LoginResponse{Id: "user", Username: "user", Role: RoleUser})
+	admin := session(models.LoginResponse{Id: "admin", Username: "admin", Role: RoleAdmin})
+	cadmin := session(models.LoginResponse{Id: "cadmin", Username: "cadmin", Role: RoleCAdmin})
+
+	if err := ta.ResetTwoFactor(ctx, user, target.Id); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("ResetTwoFactor by a user: got %v, want ErrForbidden", err)
+	}
+
+	// An admin whose role was taken is refused even with the old token.
+	ta.users.add(models.LoginResponse{Id: "admin", Username: "admin", Role: RoleUser})
+	if err := ta.ResetTwoFactor(ctx, admin, target.Id); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("ResetTwoFactor by a demoted admin: got %v, want ErrForbidden", err)
+	}
+
+	if err := ta.ResetTwoFactor(ctx, cadmin, target.Id); err != nil {
+		t.Fatalf("ResetTwoFactor by a c-admin: %v", err)
+	}
+	if _, err := ta.twoFactor.GetTwoFactor(ctx, target.Id); err == nil {
+		t.Fatal("two-factor authentication is still set up")
+	}
+	revokedAt, err := ta.revocations.UserRevokedAt(ctx, target.Id)
+	if err != nil || revokedAt.IsZero() {
+		t.Fatalf("sessions were not revoked: %v, %v", revokedAt, err)
+	}
+}
+
+func TestResetTwoFactorAboveOwnRole(t *testing.T) {
+	ctx := context.Background()
+	ta := newTestAuth(t)
+	target := models.LoginResponse{Id: "target", Username: "target", Role: RoleCAdmin}
+	enrolled(t, ta, target)
+
+	admin := models.LoginResponse{Id: "admin", Username: "admin", Role: RoleAdmin}
+	ta.users.add(admin)
+	tokens, err := ta.newSession(ctx, admin, models.Client{})
+	if err != nil {
+		t.Fatalf("newSession: %v", err)
+	}
+
+	if err := ta.ResetTwoFactor(ctx, tokens.AccessToken, target.Id); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("ResetTwoFactor of a c-admin by an admin: got %v, want ErrForbidden", err)
+	}
+}
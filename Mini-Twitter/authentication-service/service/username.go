@@ -0,0 +1,121 @@
+package service
+
+import (
+	pb "auth-service/genproto/user"
+	"auth-service/storage"
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var validUsername = regexp.MustCompile(`^[a-z0-9_]{3,30}$`)
+
+// reservedUsernames cannot be taken, as they would read as part of the site
+// or impersonate it.
+var reservedUsernames = map[string]bool{
+	"about": true, "admin": true, "administrator": true, "api": true, "auth": true,
+	"c_admin": true, "help": true, "login": true, "logout": true, "me": true,
+	"minitwitter": true, "mini_twitter": true, "moderator": true, "null": true,
+	"root": true, "security": true, "settings": true, "signup": true, "support": true,
+	"system": true, "twitter": true, "undefined": true, "user": true, "users": true,
+}
+
+// usernameProblem returns why username cannot be taken by userID, or "" if it
+// can.
+func (us *UserService) usernameProblem(ctx context.Context, userID, username string) (string, error) {
+	if !validUsername.MatchString(username) {
+		return "must be 3 to 30 lowercase letters, digits or underscores", nil
+	}
+	if reservedUsernames[username] {
+		return "is reserved", nil
+	}
+
+	owner, _, err := us.st.UsernameOwner(ctx, username)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if owner != userID {
+		return "is taken", nil
+	}
+	return "", nil
+}
+
+// CheckUsername tells whether a username can be taken.
+func (us *UserService) CheckUsername(ctx context.Context, in *pb.Username) (*pb.UsernameAvailability, error) {
+	problem, err := us.usernameProblem(ctx, "", normalizeUsername(in.Username))
+	if err != nil {
+		us.log.Error("failed to check username", "error", err)
+		return nil, err
+	}
+	return &pb.UsernameAvailability{Available: problem == "", Reason: problem}, nil
+}
+
+// ChangeUsername renames the user, at most once per cooldown. The old
+// username stays theirs and keeps resolving to them.
+func (us *UserService) ChangeUsername(ctx context.Context, in *pb.ChangeUsernameReq) (*pb.UserResponse, error) {
+	username := normalizeUsername(in.Username)
+
+	current, changedAt, err := us.st.UsernameState(ctx, in.UserId)
+	if err != nil {
+		us.log.Error("failed to get username", "error", err)
+		return nil, err
+	}
+	if strings.EqualFold(current, username) {
+		return nil, status.Error(codes.InvalidArgument, "this is already your username")
+	}
+	if next := changedAt.Add(us.usernameCooldown); !changedAt.IsZero() && time.Now().Before(next) {
+		return nil, status.Errorf(codes.FailedPrecondition, "username can be changed again after %s", next.UTC().Format(time.RFC3339))
+	}
+
+	problem, err := us.usernameProblem(ctx, in.UserId, username)
+	if err != nil {
+		us.log.Error("failed to check username", "error", err)
+		return nil, err
+	}
+	if problem != "" {
+		return nil, status.Error(codes.InvalidArgument, "username "+problem)
+	}
+
+	err = us.st.ChangeUsername(ctx, in.UserId, username)
+	if errors.Is(err, storage.ErrUsernameTaken) {
+		return nil, status.Error(codes.AlreadyExists, "username is taken")
+	}
+	if err != nil {
+		us.log.Error("failed to change username", "error", err)
+		return nil, err
+	}
+
+	us.log.Info("Username changed", "user_id", in.UserId, "from", current, "to", username)
+	return &pb.UserResponse{Id: in.UserId, Username: username}, nil
+}
+
+// ResolveUsername finds the account of a current or former username.
+func (us *UserService) ResolveUsername(ctx context.Context, in *pb.Username) (*pb.ResolvedUsername, error) {
+	userID, current, err := us.st.UsernameOwner(ctx, in.Username)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, status.Error(codes.NotFound, "user not found")
+	}
+	if err != nil {
+		us.log.Error("failed to resolve username", "error", err)
+		return nil, err
+	}
+
+	return &pb.ResolvedUsername{
+		UserId:   userID,
+		Username: current,
+		Renamed:  !strings.EqualFold(current, in.Username),
+	}, nil
+}
+
+func normalizeUsername(username string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(username), "@"))
+}
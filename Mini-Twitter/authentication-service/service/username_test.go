@@ -0,0 +1,104 @@
+package service
+
+import (
+	pb "auth-service/genproto/user"
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCheckUsername(t *testing.T) {
+	tu := newTestUsers(t)
+	tu.users.usernames["u1"] = "alice"
+	tu.users.former["old_bob"] = "u2"
+	tu.users.usernames["u2"] = "bob"
+
+	tests := []struct {
+		username  string
+		available bool
+	}{
+		{"carol", true},
+		{"@Carol ", true},
+		{"alice", false},
+		{"ALICE", false},
+		{"old_bob", false},
+		{"admin", false},
+		{"Support", false},
+		{"ab", false},
+		{"has space", false},
+		{"dash-es", false},
+	}
+
+	for _, tt := range tests {
+		res, err := tu.CheckUsername(context.Background(), &pb.Username{Username: tt.username})
+		if err != nil {
+			t.Fatalf("CheckUsername(%q): %v", tt.username, err)
+		}
+		if res.Available != tt.available {
+			t.Errorf("CheckUsername(%q) = %v (%s), want %v", tt.username, res.Available, res.Reason, tt.available)
+		}
+	}
+}
+
+func TestChangeUsernameCooldown(t *testing.T) {
+	ctx := context.Background()
+	tu := newTestUsers(t)
+	tu.users.usernames["u1"] = "alice"
+
+	if _, err := tu.ChangeUsername(ctx, &pb.ChangeUsernameReq{UserId: "u1", Username: "alice2"}); err != nil {
+		t.Fatalf("first ChangeUsername: %v", err)
+	}
+	_, err := tu.ChangeUsername(ctx, &pb.ChangeUsernameReq{UserId: "u1", Username: "alice3"})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("ChangeUsername within the cooldown: got %v, want FailedPrecondition", err)
+	}
+
+	tu.users.changedAt["u1"] = time.Now().Add(-tu.usernameCooldown - time.Minute)
+	if _, err := tu.ChangeUsername(ctx, &pb.ChangeUsernameReq{UserId: "u1", Username: "alice3"}); err != nil {
+		t.Fatalf("ChangeUsername after the cooldown: %v", err)
+	}
+}
+
+func TestChangeUsername(t *testing.T) {
+	tu := newTestUsers(t)
+	tu.users.usernames["u1"] = "alice"
+	tu.users.usernames["u2"] = "bob"
+	tu.users.former["old_bob"] = "u2"
+
+	tests := []struct {
+		name     string
+		username string
+		want     codes.Code
+	}{
+		{name: "same", username: "Alice", want: codes.InvalidArgument},
+		{name: "reserved", username: "admin", want: codes.InvalidArgument},
+		{name: "invalid", username: "a!", want: codes.InvalidArgument},
+		{name: "taken", username: "bob", want: codes.InvalidArgument},
+		{name: "former of another", username: "old_bob", want: codes.InvalidArgument},
+	}
+
+	for _, tt := range tests {
+		_, err := tu.ChangeUsername(context.Background(), &pb.ChangeUsernameReq{UserId: "u1", Username: tt.username})
+		if status.Code(err) != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, err, tt.want)
+		}
+	}
+	if tu.users.usernames["u1"] != "alice" {
+		t.Errorf("username changed to %s", tu.users.usernames["u1"])
+	}
+
+	// The old username keeps resolving to the account.
+	if _, err := tu.ChangeUsername(context.Background(), &pb.ChangeUsernameReq{UserId: "u1", Username: "@Alice_New"}); err != nil {
+		t.Fatalf("ChangeUsername: %v", err)
+	}
+	res, err := tu.ResolveUsername(context.Background(), &pb.Username{Username: "alice"})
+	if err != nil {
+		t.Fatalf("ResolveUsername: %v", err)
+	}
+	if res.UserId != "u1" || res.Username != "alice_new" || !res.Renamed {
+		t.Errorf("ResolveUsername(alice) = %v, want u1 renamed to alice_new", res)
+	}
+}
@@ -0,0 +1,136 @@
+package postgres
+
+import (
+	pb "auth-service/genproto/user"
+	"auth-service/storage"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+func (p *UserRepo) UserRole(ctx context.Context, userID string) (string, error) {
+	query := `SELECT p.role FROM user_profile p JOIN users u ON u.id = p.user_id WHERE u.id = $1 AND u.deleted_at = 0`
+
+	var role string
+	err := p.db.QueryRowContext(ctx, query, userID).Scan(&role)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", storage.ErrUserNotFound
+	}
+	return role, err
+}
+
+func (p *UserRepo) SetRole(ctx context.Context, in *pb.SetRoleReq) (*pb.UserResponse, error) {
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var old string
+	err = tx.QueryRowContext(ctx, `SELECT role FROM user_profile WHERE user_id = $1 FOR UPDATE`, in.UserId).Scan(&old)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, storage.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	query := `UPDATE user_profile SET role = $2, updated_at = now() WHERE user_id = $1`
+	if _, err := tx.ExecContext(ctx, query, in.UserId, in.Role); err != nil {
+		return nil, err
+	}
+
+	if err := audit(ctx, tx, in.ActorId, in.UserId, "set_role", fmt.Sprintf("%s -> %s", old, in.Role)); err != nil {
+		return nil, err
+	}
+
+	user, err := adminView(ctx, tx, in.UserId)
+	if err != nil {
+		return nil, err
+	}
+	return user, tx.Commit()
+}
+
+func (p *UserRepo) SetVerified(ctx context.Context, in *pb.SetVerifiedReq) (*pb.UserResponse, error) {
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE user_profile SET verified = $2, updated_at = now() WHERE user_id = $1`
+	res, err := tx.ExecContext(ctx, query, in.UserId, in.Verified)
+	if err != nil {
+		return nil, err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return nil, err
+	} else if n == 0 {
+		return nil, storage.ErrUserNotFound
+	}
+
+	action := "grant_verified"
+	if !in.Verified {
+		action = "revoke_verified"
+	}
+	if err := audit(ctx, tx, in.ActorId, in.UserId, action, in.Reason); err != nil {
+		return nil, err
+	}
+
+	user, err := adminView(ctx, tx, in.UserId)
+	if err != nil {
+		return nil, err
+	}
+	return user, tx.Commit()
+}
+
+func (p *UserRepo) ListAuditLog(ctx context.Context, in *pb.AuditFilter) (*pb.AuditEntries, error) {
+	query := `SELECT id, actor_id, user_id, action, detail, created_at
+	          FROM admin_audit
+	          WHERE $1 = '' OR user_id::text = $1
+	          ORDER BY id DESC
+	          LIMIT $2 OFFSET $3`
+
+	rows, err := p.db.QueryContext(ctx, query, in.UserId, in.Limit, (in.Page-1)*in.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := &pb.AuditEntries{}
+	for rows.Next() {
+		var e pb.AuditEntry
+		if err := rows.Scan(&e.Id, &e.ActorId, &e.UserId, &e.Action, &e.Detail, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		res.Entries = append(res.Entries, &e)
+	}
+
+	return res, rows.Err()
+}
+
+// audit records an admin action in the transaction of the change.
+func audit(ctx context.Context, tx *sqlx.Tx, actorID, userID, action, detail string) error {
+	query := `INSERT INTO admin_audit (actor_id, user_id, action, detail) VALUES ($1, $2, $3, $4)`
+	_, err := tx.ExecContext(ctx, query, actorID, userID, action, detail)
+	return err
+}
+
+func adminView(ctx context.Context, tx *sqlx.Tx, userID string) (*pb.UserResponse, error) {
+	query := `SELECT u.id, u.email, COALESCE(u.phone, ''), p.first_name, p.last_name, p.username, p.nationality, p.bio,
+	                 u.created_at, p.role, p.verified
+	          FROM users u
+	          JOIN user_profile p ON p.user_id = u.id
+	          WHERE u.id = $1`
+
+	var user pb.UserResponse
+	err := tx.QueryRowContext(ctx, query, userID).Scan(&user.Id, &user.Email, &user.Phone, &user.FirstName, &user.LastName,
+		&user.Username, &user.Nationality, &user.Bio, &user.CreatedAt, &user.Role, &user.Verified)
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
@@ -0,0 +1,49 @@
+package postgres
+
+import (
+	pb "auth-service/genproto/user"
+	"context"
+	"testing"
+)
+
+func TestSetRoleAndVerified(t *testing.T) {
+	db, err := ConnectUser()
+	if err != nil {
+		t.Fatalf("Failed to connect to database: %v", err)
+	}
+	user := NewUserRepo(db)
+	ctx := context.Background()
+
+	actor, err := user.Create(&pb.CreateRequest{Email: "audit.actor@gmail.com", Password: "auditactor", Phone: "9997472001", Username: "auditactor"})
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	target, err := user.Create(&pb.CreateRequest{Email: "audit.target@gmail.com", Password: "audittarget", Phone: "9997472002", Username: "audittarget"})
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+
+	res, err := user.SetRole(ctx, &pb.SetRoleReq{ActorId: actor.Id, UserId: target.Id, Role: "admin"})
+	if err != nil {
+		t.Fatalf("Failed to set role: %v", err)
+	}
+	if res.Role != "admin" {
+		t.Fatalf("Expected role admin, got %s", res.Role)
+	}
+
+	res, err = user.SetVerified(ctx, &pb.SetVerifiedReq{ActorId: actor.Id, UserId: target.Id, Verified: true, Reason: "notable"})
+	if err != nil {
+		t.Fatalf("Failed to set verified: %v", err)
+	}
+	if !res.Verified {
+		t.Fatal("Expected user to be verified")
+	}
+
+	log, err := user.ListAuditLog(ctx, &pb.AuditFilter{UserId: target.Id, Page: 1, Limit: 10})
+	if err != nil {
+		t.Fatalf("Failed to list audit log: %v", err)
+	}
+	if len(log.Entries) != 2 || log.Entries[0].Action != "grant_verified" || log.Entries[1].Detail != "user -> admin" {
+		t.Fatalf("Unexpected audit log: %v", log.Entries)
+	}
+}
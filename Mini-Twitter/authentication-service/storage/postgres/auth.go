@@ -1,8 +1,12 @@
 package postgres
 
 import (
+	"auth-service/pkg/events"
 	"auth-service/pkg/models"
 	"auth-service/storage"
+	"common/outbox"
+	"context"
+	"database/sql"
 	"github.com/jmoiron/sqlx"
 	"log"
 )
@@ -18,20 +22,39 @@ func NewAuthRepo(db *sqlx.DB) storage.AuthStorage {
 }
 
 func (a *AuthRepo) Register(in models.RegisterRequest) (models.RegisterResponse, error) {
-	tx, err := a.db.Begin()
+	return a.register(in, in.Phone)
+}
+
+// RegisterExternal leaves the phone NULL, as it is unique.
+func (a *AuthRepo) RegisterExternal(in models.RegisterRequest) (models.RegisterResponse, error) {
+	return a.register(in, nil)
+}
+
+func (a *AuthRepo) register(in models.RegisterRequest, phone interface{}) (models.RegisterResponse, error) {
+	tx, err := a.db.Beginx()
 	if err != nil {
 		return models.RegisterResponse{}, err
 	}
+	defer tx.Rollback()
 
 	var id string
 	query := `INSERT INTO users (phone, email, password) VALUES ($1, $2, $3) RETURNING id`
-	err = a.db.QueryRow(query, in.Phone, in.Email, in.Password).Scan(&id)
+	err = tx.QueryRow(query, phone, in.Email, in.Password).Scan(&id)
 	if err != nil {
 		return models.RegisterResponse{}, err
 	}
 
 	query1 := `INSERT INTO user_profile (user_id, first_name, last_name, username, nationality, bio) VALUES ($1, $2, $3, $4, $5, $6)`
-	_, err = a.db.Query(query1, id, in.FirstName, in.LastName, in.Username, in.Nationality, in.Bio)
+	_, err = tx.Exec(query1, id, in.FirstName, in.LastName, in.Username, in.Nationality, in.Bio)
+	if err != nil {
+		return models.RegisterResponse{}, err
+	}
+
+	msg, err := events.NewUserEvent(events.UserRegistered, events.UserEvent{UserID: id, Email: in.Email, Username: in.Username})
+	if err != nil {
+		return models.RegisterResponse{}, err
+	}
+	err = outbox.Add(context.Background(), tx, msg)
 	if err != nil {
 		return models.RegisterResponse{}, err
 	}
@@ -110,3 +133,45 @@ func (a *AuthRepo) LoginUsername(in models.LoginUsernameRequest) (models.LoginRe
 		Role:     role,
 	}, nil
 }
+
+func (a *AuthRepo) UserIDByEmail(email string) (string, error) {
+	var id string
+	query := `SELECT id FROM users WHERE lower(email) = $1 and deleted_at = 0`
+	err := a.db.Get(&id, query, email)
+	if err != nil {
+		return "", err
+	}
+
+	return id, nil
+}
+
+func (a *AuthRepo) UserByID(id string) (models.LoginResponse, error) {
+	res := models.LoginResponse{}
+	query := `SELECT u.id, u.email, p.username, p.role
+	          FROM users u JOIN user_profile p ON p.user_id = u.id
+	          WHERE u.id = $1 and u.deleted_at = 0`
+	err := a.db.Get(&res, query, id)
+	if err != nil {
+		return models.LoginResponse{}, err
+	}
+
+	return res, nil
+}
+
+func (a *AuthRepo) ResetPassword(userID, password string) error {
+	query := `UPDATE users SET password = $1, updated_at = now() WHERE id = $2 and deleted_at = 0`
+	res, err := a.db.Exec(query, password, userID)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
@@ -3,7 +3,9 @@ package postgres
 import (
 	"auth-service/pkg/config"
 	"fmt"
+	"github.com/XSAM/otelsql"
 	"github.com/jmoiron/sqlx"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
 
 	_ "github.com/lib/pq"
 )
@@ -12,9 +14,11 @@ func ConnectPostgres(config config.Config) (*sqlx.DB, error) {
 	psqlInfo := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
 		config.DB_HOST, config.DB_PORT, config.DB_USER, config.DB_PASSWORD, config.DB_NAME)
 
-	db, err := sqlx.Open("postgres", psqlInfo)
+	// Every query gets a span; the text of the query is recorded, never its
+	// arguments.
+	db, err := otelsql.Open("postgres", psqlInfo, otelsql.WithAttributes(semconv.DBSystemPostgreSQL))
 	if err != nil {
 		return nil, err
 	}
-	return db, nil
+	return sqlx.NewDb(db, "postgres"), nil
 }
@@ -0,0 +1,42 @@
+package postgres
+
+import (
+	"auth-service/pkg/models"
+	"auth-service/storage"
+	"context"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type IdentityRepo struct {
+	db *sqlx.DB
+}
+
+func NewIdentityRepo(db *sqlx.DB) storage.IdentityStorage {
+	return &IdentityRepo{
+		db: db,
+	}
+}
+
+func (i *IdentityRepo) UserByIdentity(ctx context.Context, provider, subject string) (models.LoginResponse, error) {
+	res := models.LoginResponse{}
+
+	query := `SELECT u.id, u.email, p.username, p.role
+	          FROM user_identities i
+	          JOIN users u ON u.id = i.user_id AND u.deleted_at = 0
+	          JOIN user_profile p ON p.user_id = u.id
+	          WHERE i.provider = $1 AND i.subject = $2`
+	err := i.db.GetContext(ctx, &res, query, provider, subject)
+	if err != nil {
+		return models.LoginResponse{}, err
+	}
+
+	return res, nil
+}
+
+func (i *IdentityRepo) LinkIdentity(ctx context.Context, userID string, identity models.ExternalIdentity) error {
+	query := `INSERT INTO user_identities (provider, subject, user_id, email) VALUES ($1, $2, $3, $4)
+	          ON CONFLICT (provider, subject) DO UPDATE SET user_id = excluded.user_id, email = excluded.email`
+	_, err := i.db.ExecContext(ctx, query, identity.Provider, identity.Subject, userID, identity.Email)
+	return err
+}
@@ -0,0 +1,58 @@
+package postgres
+
+import (
+	"auth-service/pkg/models"
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+)
+
+func TestIdentities(t *testing.T) {
+	db, err := Connect()
+	if err != nil {
+		t.Fatalf("Failed to connect to database: %v", err)
+	}
+
+	user, err := NewAuthRepo(db).Register(models.RegisterRequest{
+		FirstName: "Open",
+		LastName:  "Id",
+		Email:     "open.id@gmail.com",
+		Password:  "openid",
+		Phone:     "9997470002",
+		Username:  "openid",
+	})
+	if err != nil {
+		t.Fatalf("Failed to register user: %v", err)
+	}
+
+	ctx := context.Background()
+	repo := NewIdentityRepo(db)
+	defer db.Exec(`DELETE FROM user_identities WHERE user_id = $1`, user.Id)
+
+	_, err = repo.UserByIdentity(ctx, "google", "sub-openid")
+	if !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("Expected sql.ErrNoRows before linking, got %v", err)
+	}
+
+	identity := models.ExternalIdentity{Provider: "google", Subject: "sub-openid", Email: user.Email}
+	if err := repo.LinkIdentity(ctx, user.Id, identity); err != nil {
+		t.Fatalf("Failed to link identity: %v", err)
+	}
+	// Linking again is a no-op.
+	if err := repo.LinkIdentity(ctx, user.Id, identity); err != nil {
+		t.Fatalf("Failed to link identity again: %v", err)
+	}
+
+	res, err := repo.UserByIdentity(ctx, "google", "sub-openid")
+	if err != nil {
+		t.Fatalf("Failed to get user by identity: %v", err)
+	}
+	if res.Id != user.Id || res.Username != "openid" {
+		t.Errorf("Unexpected user: %+v", res)
+	}
+
+	if _, err := repo.UserByIdentity(ctx, "github", "sub-openid"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Identity matched another provider: %v", err)
+	}
+}
@@ -0,0 +1,71 @@
+package postgres
+
+import (
+	pb "auth-service/genproto/user"
+	"context"
+
+	"github.com/lib/pq"
+)
+
+func (p *UserRepo) Relationships(ctx context.Context, viewerID string, targetIDs []string) ([]*pb.Relationship, error) {
+	query := `SELECT t.id,
+	                 EXISTS (SELECT 1 FROM follows WHERE follower_id = $1 AND following_id = t.id),
+	                 EXISTS (SELECT 1 FROM follows WHERE follower_id = t.id AND following_id = $1),
+	                 EXISTS (SELECT 1 FROM user_blocks WHERE blocker_id = $1 AND blocked_id = t.id),
+	                 EXISTS (SELECT 1 FROM user_blocks WHERE blocker_id = t.id AND blocked_id = $1)
+	          FROM unnest($2::uuid[]) WITH ORDINALITY AS t(id, n)
+	          ORDER BY t.n`
+
+	rows, err := p.db.QueryContext(ctx, query, viewerID, pq.Array(targetIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []*pb.Relationship
+	for rows.Next() {
+		var r pb.Relationship
+		if err := rows.Scan(&r.TargetId, &r.Following, &r.FollowedBy, &r.Blocking, &r.BlockedBy); err != nil {
+			return nil, err
+		}
+		res = append(res, &r)
+	}
+
+	return res, rows.Err()
+}
+
+func (p *UserRepo) MutualFollowers(ctx context.Context, viewerID string, targetIDs []string, limit, offset int) ([]*pb.TargetMutualFollowers, error) {
+	// The people the viewer followed most recently come first.
+	query := `WITH known AS (
+	              SELECT f.following_id AS target_id, p.username,
+	                     ROW_NUMBER() OVER (PARTITION BY f.following_id ORDER BY mine.created_at DESC, p.username) AS n
+	              FROM follows f
+	              JOIN follows mine ON mine.following_id = f.follower_id AND mine.follower_id = $1
+	              JOIN users u ON u.id = f.follower_id AND u.deleted_at = 0
+	              JOIN user_profile p ON p.user_id = f.follower_id
+	              WHERE f.following_id = ANY($2::uuid[])
+	          )
+	          SELECT t.id, array_agg(k.username ORDER BY k.n) FILTER (WHERE k.n > $4 AND k.n <= $3 + $4),
+	                 COUNT(k.n)
+	          FROM unnest($2::uuid[]) WITH ORDINALITY AS t(id, n)
+	          LEFT JOIN known k ON k.target_id = t.id
+	          GROUP BY t.id, t.n
+	          ORDER BY t.n`
+
+	rows, err := p.db.QueryContext(ctx, query, viewerID, pq.Array(targetIDs), limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []*pb.TargetMutualFollowers
+	for rows.Next() {
+		var m pb.TargetMutualFollowers
+		if err := rows.Scan(&m.TargetId, pq.Array(&m.Usernames), &m.Total); err != nil {
+			return nil, err
+		}
+		res = append(res, &m)
+	}
+
+	return res, rows.Err()
+}
@@ -0,0 +1,75 @@
+package postgres
+
+import (
+	pb "auth-service/genproto/user"
+	"context"
+	"fmt"
+	"testing"
+)
+
+func TestRelationships(t *testing.T) {
+	db, err := ConnectUser()
+	if err != nil {
+		t.Fatalf("Failed to connect to database: %v", err)
+	}
+	user := NewUserRepo(db)
+	ctx := context.Background()
+
+	// viewer follows friend and target; friend follows target and viewer.
+	ids := map[string]string{}
+	for i, name := range []string{"relviewer", "relfriend", "reltarget", "relstranger"} {
+		created, err := user.Create(&pb.CreateRequest{
+			Email:    name + "@gmail.com",
+			Password: name,
+			Phone:    fmt.Sprintf("99974730%02d", i),
+			Username: name,
+		})
+		if err != nil {
+			t.Fatalf("Failed to create user: %v", err)
+		}
+		ids[name] = created.Id
+	}
+	follows := [][2]string{
+		{"relviewer", "relfriend"},
+		{"relviewer", "reltarget"},
+		{"relfriend", "reltarget"},
+		{"relfriend", "relviewer"},
+	}
+	for _, f := range follows {
+		if _, err := user.Follow(&pb.FollowReq{FollowerId: ids[f[0]], FollowingId: ids[f[1]]}); err != nil {
+			t.Fatalf("Failed to follow: %v", err)
+		}
+	}
+	if _, err := user.Block(&pb.BlockReq{BlockerId: ids["relstranger"], BlockedId: ids["relviewer"]}); err != nil {
+		t.Fatalf("Failed to block: %v", err)
+	}
+
+	targets := []string{ids["relfriend"], ids["reltarget"], ids["relstranger"]}
+	rels, err := user.Relationships(ctx, ids["relviewer"], targets)
+	if err != nil {
+		t.Fatalf("Failed to get relationships: %v", err)
+	}
+	if len(rels) != 3 {
+		t.Fatalf("Expected 3 relationships, got %v", rels)
+	}
+	if !rels[0].Following || !rels[0].FollowedBy {
+		t.Errorf("Expected viewer and friend to follow each other, got %v", rels[0])
+	}
+	if !rels[1].Following || rels[1].FollowedBy {
+		t.Errorf("Expected viewer to follow target only, got %v", rels[1])
+	}
+	if !rels[2].BlockedBy || rels[2].Blocking {
+		t.Errorf("Expected stranger to block viewer, got %v", rels[2])
+	}
+
+	mutuals, err := user.MutualFollowers(ctx, ids["relviewer"], targets, 3, 0)
+	if err != nil {
+		t.Fatalf("Failed to get mutual followers: %v", err)
+	}
+	if len(mutuals) != 3 || mutuals[1].Total != 1 || len(mutuals[1].Usernames) != 1 || mutuals[1].Usernames[0] != "relfriend" {
+		t.Fatalf("Expected relfriend to follow target, got %v", mutuals)
+	}
+	if mutuals[2].Total != 0 || len(mutuals[2].Usernames) != 0 {
+		t.Fatalf("Expected no mutual followers of stranger, got %v", mutuals[2])
+	}
+}
@@ -0,0 +1,80 @@
+package postgres
+
+import (
+	pb "auth-service/genproto/user"
+	"context"
+	"strings"
+)
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// containsPattern returns an ILIKE pattern matching s anywhere, with the
+// wildcards in s taken literally.
+func containsPattern(s string) string {
+	return "%" + likeEscaper.Replace(s) + "%"
+}
+
+// prefixPattern returns a LIKE pattern matching values starting with s.
+func prefixPattern(s string) string {
+	return likeEscaper.Replace(s) + "%"
+}
+
+// searchUsersQuery takes the lowercased query as $1 and its prefix pattern as
+// $2. A typeahead search matches names starting with the query and ranks an
+// exact username first, then by followers. A fuzzy search also matches
+// similar names and ranks by similarity, about equal matches by followers.
+//
+// Every name condition is served by a trigram index of migrations 10 and 15;
+// the full name is written exactly as indexed. Followers are counted for the
+// matches only.
+const searchUsersQuery = `WITH blocked AS (
+	    SELECT blocked_id AS id FROM user_blocks WHERE blocker_id = $3
+	    UNION
+	    SELECT blocker_id FROM user_blocks WHERE blocked_id = $3
+	), matches AS (
+	    SELECT p.user_id, p.username, p.first_name, p.last_name, p.verified,
+	           lower(p.username) = $1 AS exact,
+	           GREATEST(similarity(lower(p.username), $1),
+	                    similarity(lower(COALESCE(p.first_name, '')), $1),
+	                    similarity(lower(COALESCE(p.last_name, '')), $1),
+	                    similarity(lower(COALESCE(p.first_name, '') || ' ' || COALESCE(p.last_name, '')), $1)) AS score
+	    FROM user_profile p
+	    JOIN users u ON u.id = p.user_id
+	    WHERE u.deleted_at = 0 AND u.delete_after IS NULL AND p.is_active AND p.role = 'user'
+	      AND p.user_id NOT IN (SELECT id FROM blocked)
+	      AND (lower(p.username) LIKE $2
+	           OR lower(p.first_name) LIKE $2
+	           OR lower(p.last_name) LIKE $2
+	           OR lower(COALESCE(p.first_name, '') || ' ' || COALESCE(p.last_name, '')) LIKE $2
+	           OR (NOT $4 AND (lower(p.username) % $1
+	                           OR lower(p.first_name) % $1
+	                           OR lower(p.last_name) % $1)))
+	)
+	SELECT m.user_id, m.username, COALESCE(m.first_name, ''), COALESCE(m.last_name, ''), m.verified,
+	       fc.n AS followers_count, m.score
+	FROM matches m
+	CROSS JOIN LATERAL (SELECT COUNT(*) AS n FROM follows WHERE following_id = m.user_id) fc
+	ORDER BY m.exact DESC,
+	         CASE WHEN $4 THEN 0 ELSE round(m.score::numeric, 1) END DESC,
+	         followers_count DESC, m.score DESC, m.username
+	LIMIT $5 OFFSET $6`
+
+func (p *UserRepo) SearchUsers(ctx context.Context, in *pb.SearchReq) (*pb.SearchResults, error) {
+	q := strings.ToLower(in.Query)
+	rows, err := p.db.QueryContext(ctx, searchUsersQuery, q, prefixPattern(q), in.UserId, in.Prefix, in.Limit, (in.Page-1)*in.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := &pb.SearchResults{}
+	for rows.Next() {
+		var r pb.SearchResult
+		if err := rows.Scan(&r.Id, &r.Username, &r.FirstName, &r.LastName, &r.Verified, &r.FollowersCount, &r.Score); err != nil {
+			return nil, err
+		}
+		res.Users = append(res.Users, &r)
+	}
+
+	return res, rows.Err()
+}
@@ -0,0 +1,108 @@
+package postgres
+
+import (
+	pb "auth-service/genproto/user"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestContainsPattern(t *testing.T) {
+	tests := map[string]string{
+		"ali":    "%ali%",
+		"a_b":    `%a\_b%`,
+		"100%":   `%100\%%`,
+		`back\s`: `%back\\s%`,
+	}
+	for in, want := range tests {
+		if got := containsPattern(in); got != want {
+			t.Errorf("containsPattern(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestSearchUsers(t *testing.T) {
+	db, err := ConnectUser()
+	if err != nil {
+		t.Fatalf("Failed to connect to database: %v", err)
+	}
+	user := NewUserRepo(db)
+	ctx := context.Background()
+
+	// searchfan follows searchalex, so searchalex ranks above searchalexa.
+	ids := map[string]string{}
+	for i, name := range []string{"searchme", "searchalex", "searchalexa", "searchfan"} {
+		created, err := user.Create(&pb.CreateRequest{
+			Email:    name + "@gmail.com",
+			Password: name,
+			Phone:    fmt.Sprintf("99974720%02d", i),
+			Username: name,
+		})
+		if err != nil {
+			t.Fatalf("Failed to create user: %v", err)
+		}
+		ids[name] = created.Id
+	}
+	if _, err := user.Follow(&pb.FollowReq{FollowerId: ids["searchfan"], FollowingId: ids["searchalex"]}); err != nil {
+		t.Fatalf("Failed to follow: %v", err)
+	}
+
+	res, err := user.SearchUsers(ctx, &pb.SearchReq{Query: "searchale", Prefix: true, Page: 1, Limit: 10, UserId: ids["searchme"]})
+	if err != nil {
+		t.Fatalf("Failed to search users: %v", err)
+	}
+	if len(res.Users) != 2 || res.Users[0].Id != ids["searchalex"] || res.Users[0].FollowersCount != 1 {
+		t.Fatalf("Expected searchalex then searchalexa, got %v", res.Users)
+	}
+
+	// A typo still matches with a fuzzy search.
+	res, err = user.SearchUsers(ctx, &pb.SearchReq{Query: "serchalexa", Page: 1, Limit: 10, UserId: ids["searchme"]})
+	if err != nil {
+		t.Fatalf("Failed to search users: %v", err)
+	}
+	for _, u := range res.Users {
+		if u.Id == ids["searchalexa"] {
+			return
+		}
+	}
+	t.Fatalf("Expected searchalexa among %v", res.Users)
+}
+
+// TestSearchUsersPlan checks that the name conditions can use the trigram
+// indexes. Sequential scans are turned off, as the test tables are small
+// enough for the planner to prefer them.
+func TestSearchUsersPlan(t *testing.T) {
+	db, err := ConnectUser()
+	if err != nil {
+		t.Fatalf("Failed to connect to database: %v", err)
+	}
+	ctx := context.Background()
+
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		t.Fatalf("Failed to begin: %v", err)
+	}
+	defer tx.Rollback()
+	if _, err := tx.ExecContext(ctx, `SET LOCAL enable_seqscan = off`); err != nil {
+		t.Fatalf("Failed to turn off sequential scans: %v", err)
+	}
+
+	var plan []string
+	err = tx.SelectContext(ctx, &plan, "EXPLAIN "+searchUsersQuery, "ann lee", prefixPattern("ann lee"), "00000000-0000-0000-0000-000000000000", true, 10, 0)
+	if err != nil {
+		t.Fatalf("Failed to explain search: %v", err)
+	}
+
+	text := strings.Join(plan, "\n")
+	for _, index := range []string{
+		"user_profile_username_trgm_idx",
+		"user_profile_first_name_trgm_idx",
+		"user_profile_last_name_trgm_idx",
+		"user_profile_full_name_trgm_idx",
+	} {
+		if !strings.Contains(text, index) {
+			t.Errorf("Search does not use %s:\n%s", index, text)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package postgres
+
+import (
+	"auth-service/pkg/models"
+	"auth-service/storage"
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type SigningKeyRepo struct {
+	db *sqlx.DB
+}
+
+func NewSigningKeyRepo(db *sqlx.DB) storage.SigningKeyStorage {
+	return &SigningKeyRepo{
+		db: db,
+	}
+}
+
+func (s *SigningKeyRepo) ListSigningKeys(ctx context.Context) ([]models.SigningKey, error) {
+	var keys []models.SigningKey
+	query := `SELECT id, algorithm, private_key, active_from FROM signing_keys ORDER BY active_from`
+	err := s.db.SelectContext(ctx, &keys, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+func (s *SigningKeyRepo) AddSigningKey(ctx context.Context, key models.SigningKey, latest time.Time) (bool, error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	// Instances syncing at once queue up here, so only the first adds a key
+	// and the others see it.
+	_, err = tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext('signing_keys'))`)
+	if err != nil {
+		return false, err
+	}
+
+	var newer bool
+	err = tx.GetContext(ctx, &newer, `SELECT EXISTS (SELECT 1 FROM signing_keys WHERE active_from > $1)`, latest)
+	if err != nil {
+		return false, err
+	}
+	if newer {
+		return false, nil
+	}
+
+	query := `INSERT INTO signing_keys (id, algorithm, private_key, active_from) VALUES ($1, $2, $3, $4)`
+	_, err = tx.ExecContext(ctx, query, key.ID, key.Algorithm, key.PrivateKey, key.ActiveFrom)
+	if err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+func (s *SigningKeyRepo) DeleteSigningKey(ctx context.Context, id string) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM signing_keys WHERE id = $1`, id)
+	return err
+}
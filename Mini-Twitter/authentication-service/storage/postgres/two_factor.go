@@ -0,0 +1,123 @@
+package postgres
+
+import (
+	"auth-service/pkg/models"
+	"auth-service/storage"
+	"context"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type TwoFactorRepo struct {
+	db *sqlx.DB
+}
+
+func NewTwoFactorRepo(db *sqlx.DB) storage.TwoFactorStorage {
+	return &TwoFactorRepo{
+		db: db,
+	}
+}
+
+func (t *TwoFactorRepo) SaveSecret(ctx context.Context, userID, secret string) error {
+	query := `INSERT INTO two_factor (user_id, secret) VALUES ($1, $2)
+	          ON CONFLICT (user_id) DO UPDATE SET secret = excluded.secret, created_at = now()
+	          WHERE two_factor.enabled = false`
+	res, err := t.db.ExecContext(ctx, query, userID, secret)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return storage.ErrTwoFactorEnabled
+	}
+
+	return nil
+}
+
+func (t *TwoFactorRepo) GetTwoFactor(ctx context.Context, userID string) (models.TwoFactor, error) {
+	var res models.TwoFactor
+	query := `SELECT user_id, secret, enabled FROM two_factor WHERE user_id = $1`
+	err := t.db.GetContext(ctx, &res, query, userID)
+	if err != nil {
+		return models.TwoFactor{}, err
+	}
+
+	return res, nil
+}
+
+func (t *TwoFactorRepo) Enable(ctx context.Context, userID string, codeHashes []string) error {
+	tx, err := t.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE two_factor SET enabled = true, enabled_at = now()
+	          WHERE user_id = $1 and enabled = false`
+	res, err := tx.ExecContext(ctx, query, userID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		// Enabled by a concurrent confirmation; keep its recovery codes.
+		return storage.ErrTwoFactorEnabled
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM recovery_codes WHERE user_id = $1`, userID)
+	if err != nil {
+		return err
+	}
+
+	for _, hash := range codeHashes {
+		_, err = tx.ExecContext(ctx, `INSERT INTO recovery_codes (user_id, code_hash) VALUES ($1, $2)`, userID, hash)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (t *TwoFactorRepo) UseRecoveryCode(ctx context.Context, userID, codeHash string) (bool, error) {
+	query := `UPDATE recovery_codes SET used_at = now()
+	          WHERE user_id = $1 and code_hash = $2 and used_at IS NULL`
+	res, err := t.db.ExecContext(ctx, query, userID, codeHash)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return n == 1, nil
+}
+
+func (t *TwoFactorRepo) Disable(ctx context.Context, userID string) error {
+	tx, err := t.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM recovery_codes WHERE user_id = $1`, userID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM two_factor WHERE user_id = $1`, userID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,65 @@
+package postgres
+
+import (
+	"auth-service/pkg/models"
+	"auth-service/storage"
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestTwoFactor(t *testing.T) {
+	db, err := Connect()
+	if err != nil {
+		t.Fatalf("Failed to connect to database: %v", err)
+	}
+
+	user, err := NewAuthRepo(db).Register(models.RegisterRequest{
+		FirstName: "Two",
+		LastName:  "Factor",
+		Email:     "two.factor@gmail.com",
+		Password:  "twofactor",
+		Phone:     "9997470001",
+		Username:  "twofactor",
+	})
+	if err != nil {
+		t.Fatalf("Failed to register user: %v", err)
+	}
+
+	ctx := context.Background()
+	repo := NewTwoFactorRepo(db)
+	defer repo.Disable(ctx, user.Id)
+
+	if err := repo.SaveSecret(ctx, user.Id, "SECRET1"); err != nil {
+		t.Fatalf("Failed to save secret: %v", err)
+	}
+	// Enrolling again before confirming replaces the secret.
+	if err := repo.SaveSecret(ctx, user.Id, "SECRET2"); err != nil {
+		t.Fatalf("Failed to replace secret: %v", err)
+	}
+
+	if err := repo.Enable(ctx, user.Id, []string{"hash1", "hash2"}); err != nil {
+		t.Fatalf("Failed to enable two-factor: %v", err)
+	}
+
+	tf, err := repo.GetTwoFactor(ctx, user.Id)
+	if err != nil {
+		t.Fatalf("Failed to get two-factor: %v", err)
+	}
+	if !tf.Enabled || tf.Secret != "SECRET2" {
+		t.Errorf("Unexpected two-factor settings: %+v", tf)
+	}
+
+	if err := repo.SaveSecret(ctx, user.Id, "SECRET3"); !errors.Is(err, storage.ErrTwoFactorEnabled) {
+		t.Errorf("Expected ErrTwoFactorEnabled, got %v", err)
+	}
+
+	ok, err := repo.UseRecoveryCode(ctx, user.Id, "hash1")
+	if err != nil || !ok {
+		t.Errorf("Failed to use recovery code: %v", err)
+	}
+	ok, err = repo.UseRecoveryCode(ctx, user.Id, "hash1")
+	if err != nil || ok {
+		t.Errorf("Recovery code was accepted twice: %v", err)
+	}
+}
@@ -1,16 +1,18 @@
 package postgres
 
 import (
+	"auth-service/pkg/events"
 	"auth-service/pkg/hashing"
 	"auth-service/storage"
+	"common/outbox"
 	"context"
 	"database/sql"
 	"errors"
 	"fmt"
 	"github.com/jmoiron/sqlx"
+	"time"
 
 	"github.com/google/uuid"
-	"github.com/jackc/pgx/v4"
 
 	pb "auth-service/genproto/user"
 )
@@ -26,36 +28,54 @@ func NewUserRepo(db *sqlx.DB) storage.UserStorage {
 func (p *UserRepo) Create(req *pb.CreateRequest) (*pb.UserResponse, error) {
 	userID := uuid.New().String()
 
+	tx, err := p.db.Beginx()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
 	query := `INSERT INTO users (id, phone, email, password) VALUES ($1, $2, $3, $4) RETURNING id`
-	_, err := p.db.Exec(query, userID, req.Phone, req.Email, req.Password)
+	_, err = tx.Exec(query, userID, req.Phone, req.Email, req.Password)
 	if err != nil {
 		return nil, err
 	}
 
 	profileQuery := `INSERT INTO user_profile (user_id, first_name, last_name, username, nationality, bio) 
 	                 VALUES ($1, $2, $3, $4, $5, $6)`
-	_, err = p.db.Exec(profileQuery, userID, req.FirstName, req.LastName, req.Username, req.Nationality, req.Bio)
+	_, err = tx.Exec(profileQuery, userID, req.FirstName, req.LastName, req.Username, req.Nationality, req.Bio)
+	if err != nil {
+		return nil, err
+	}
+
+	msg, err := events.NewUserEvent(events.UserRegistered, events.UserEvent{UserID: userID, Email: req.Email, Username: req.Username})
 	if err != nil {
 		return nil, err
 	}
+	if err := outbox.Add(context.Background(), tx, msg); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
 
 	return &pb.UserResponse{Id: userID, Email: req.Email, FirstName: req.FirstName, LastName: req.LastName}, nil
 }
 
 func (p *UserRepo) GetProfile(req *pb.Id) (*pb.GetProfileResponse, error) {
-	query := `SELECT u.id, u.email, u.phone, p.first_name, p.last_name, p.username, p.nationality, p.bio, 
-	                   p.followers_count, p.following_count, p.posts_count
+	query := `SELECT u.id, u.email, COALESCE(u.phone, ''), p.first_name, p.last_name, p.username, p.nationality, p.bio, 
+	                   p.followers_count, p.following_count, p.posts_count, p.verified
 	          FROM users u
 	          JOIN user_profile p ON u.id = p.user_id
-	          WHERE u.id = $1 and p.role != 'admin' and u.deleted_at = 0`
+	          WHERE u.id = $1 and u.deleted_at = 0`
 
 	row := p.db.QueryRow(query, req.UserId)
 	var res pb.GetProfileResponse
 	err := row.Scan(&res.UserId, &res.Email, &res.PhoneNumber, &res.FirstName, &res.LastName, &res.Username, &res.Nationality,
-		&res.Bio, &res.FollowersCount, &res.FollowingCount, &res.PostsCount)
+		&res.Bio, &res.FollowersCount, &res.FollowingCount, &res.PostsCount, &res.Verified)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, errors.New("user not found")
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, storage.ErrUserNotFound
 		}
 		return nil, err
 	}
@@ -64,11 +84,12 @@ func (p *UserRepo) GetProfile(req *pb.Id) (*pb.GetProfileResponse, error) {
 }
 
 func (p *UserRepo) UpdateProfile(req *pb.UpdateProfileRequest) (*pb.UserResponse, error) {
+	// The username is changed with ChangeUsername only.
 	query := `UPDATE user_profile 
-	          SET first_name = $1, last_name = $2, username = $3, nationality = $4, bio = $5, profile_image = $6, updated_at = now()
-	          WHERE user_id = $7 RETURNING user_id`
+	          SET first_name = $1, last_name = $2, nationality = $3, bio = $4, profile_image = $5, updated_at = now()
+	          WHERE user_id = $6 RETURNING user_id`
 
-	_, err := p.db.Exec(query, req.FirstName, req.LastName, req.Username, req.Nationality, req.Bio, req.ProfileImage, req.UserId)
+	_, err := p.db.Exec(query, req.FirstName, req.LastName, req.Nationality, req.Bio, req.ProfileImage, req.UserId)
 	if err != nil {
 		return nil, err
 	}
@@ -83,14 +104,14 @@ func (p *UserRepo) ChangePassword(req *pb.ChangePasswordRequest) (*pb.ChangePass
 	var password string
 	err := row.Scan(&password)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, errors.New("user not found")
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, storage.ErrUserNotFound
 		}
 		return nil, err
 	}
 	ok := hashing.CheckPasswordHash(password, req.CurrentPassword)
 	if !ok {
-		return nil, errors.New("password is incorrect")
+		return nil, storage.ErrWrongPassword
 	}
 	query = `UPDATE users SET password = $1, updated_at = now() WHERE id = $2`
 	_, err = p.db.Exec(query, req.NewPassword, req.UserId)
@@ -112,13 +133,15 @@ func (p *UserRepo) ChangeProfileImage(req *pb.URL) (*pb.Void, error) {
 }
 
 func (p *UserRepo) FetchUsers(req *pb.Filter) (*pb.UserResponses, error) {
-	query := `SELECT u.id, u.email, p.first_name, p.last_name, p.username, u.created_at
+	query := `SELECT u.id, u.email, p.first_name, p.last_name, p.username, u.created_at, p.role, p.verified
 	          FROM users u
 	          JOIN user_profile p ON u.id = p.user_id
-	          WHERE p.username ILIKE $1 and p.role = 'user'
+	          WHERE (p.username ILIKE $1 or p.first_name ILIKE $1) and ($4 = '' or p.role::text = $4)
+	            and u.deleted_at = 0 and p.is_active
+	          ORDER BY u.created_at DESC
 	          LIMIT $2 OFFSET $3`
 
-	rows, err := p.db.Query(query, req.FirstName, req.Limit, (req.Page-1)*req.Limit)
+	rows, err := p.db.Query(query, containsPattern(req.FirstName), req.Limit, (req.Page-1)*req.Limit, req.Role)
 	if err != nil {
 		return nil, err
 	}
@@ -127,7 +150,7 @@ func (p *UserRepo) FetchUsers(req *pb.Filter) (*pb.UserResponses, error) {
 	var users []*pb.UserResponse
 	for rows.Next() {
 		var user pb.UserResponse
-		if err := rows.Scan(&user.Id, &user.Email, &user.FirstName, &user.LastName, &user.Username, &user.CreatedAt); err != nil {
+		if err := rows.Scan(&user.Id, &user.Email, &user.FirstName, &user.LastName, &user.Username, &user.CreatedAt, &user.Role, &user.Verified); err != nil {
 			return nil, err
 		}
 		users = append(users, &user)
@@ -173,9 +196,8 @@ func (p *UserRepo) ListOfFollowingByUsername(req *pb.Id) (*pb.Followings, error)
 	query := `
 		SELECT p2.username AS following_username
 		FROM follows f
-		JOIN user_profile p1 ON f.follower_id = p1.user_id
 		JOIN user_profile p2 ON f.following_id = p2.user_id
-		WHERE p1.username = $1;
+		WHERE f.follower_id = (` + usernameAccount + `);
     `
 
 	rows, err := p.db.Query(query, req.UserId)
@@ -204,9 +226,8 @@ func (p *UserRepo) ListOfFollowersByUsername(req *pb.Id) (*pb.Followers, error)
 	query := `
 		SELECT p2.username AS follower_username
 		FROM follows f
-		JOIN user_profile p1 ON f.following_id = p1.user_id
 		JOIN user_profile p2 ON f.follower_id = p2.user_id
-		WHERE p1.username = $1;
+		WHERE f.following_id = (` + usernameAccount + `);
     `
 
 	rows, err := p.db.Query(query, req.UserId)
@@ -261,21 +282,51 @@ func (p *UserRepo) ListOfFollowers(req *pb.Id) (*pb.Followers, error) {
 }
 
 func (p *UserRepo) DeleteUser(req *pb.Id) (*pb.Void, error) {
-	query := `UPDATE users SET deleted_at = EXTRACT(EPOCH FROM NOW()) WHERE id = $1 AND deleted_at = 0`
+	tx, err := p.db.Beginx()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
 
-	_, err := p.db.Exec(query, req.UserId)
+	query := `UPDATE users SET deleted_at = EXTRACT(EPOCH FROM NOW()) WHERE id = $1 AND deleted_at = 0`
+	res, err := tx.Exec(query, req.UserId)
 	if err != nil {
 		return nil, fmt.Errorf("failed to mark user as deleted: %w", err)
 	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if affected == 0 {
+		// Already deleted; the event went out then.
+		return &pb.Void{}, nil
+	}
 
+	msg, err := events.NewUserEvent(events.UserDeleted, events.UserEvent{UserID: req.UserId})
+	if err != nil {
+		return nil, err
+	}
+	if err := outbox.Add(context.Background(), tx, msg); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
 	return &pb.Void{}, nil
 }
 
 // ----------------------------------------------------------------------------------------
 
 func (p *UserRepo) Follow(in *pb.FollowReq) (*pb.FollowRes, error) {
+	// Following again keeps the original follow and returns it.
 	query := `INSERT INTO follows (follower_id, following_id, created_at)
-	          VALUES ($1, $2, NOW())
+	          SELECT $1, $2, NOW()
+	          WHERE NOT EXISTS (
+	              SELECT 1 FROM user_blocks
+	              WHERE (blocker_id = $1 AND blocked_id = $2) OR (blocker_id = $2 AND blocked_id = $1)
+	          )
+	          ON CONFLICT (follower_id, following_id) DO UPDATE SET created_at = follows.created_at
 	          RETURNING follower_id, following_id, created_at`
 
 	var res pb.FollowRes
@@ -283,6 +334,9 @@ func (p *UserRepo) Follow(in *pb.FollowReq) (*pb.FollowRes, error) {
 		&res.FollowerId, &res.FollowingId, &res.FollowedAt)
 
 	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, storage.ErrCannotFollow
+		}
 		return nil, err
 	}
 
@@ -299,7 +353,7 @@ func (p *UserRepo) Unfollow(in *pb.FollowReq) (*pb.DFollowRes, error) {
 
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("no such follow relation exists")
+			return nil, storage.ErrNotFollowing
 		}
 		return nil, err
 	}
@@ -331,33 +385,222 @@ func (p *UserRepo) GetUserFollows(in *pb.Id) (*pb.Count, error) {
 	return &count, nil
 }
 
-func (p *UserRepo) MostPopularUser(in *pb.Void) (*pb.UserResponse, error) {
-	var (
-		userID string
-	)
-
-	query := `SELECT follower_id FROM follows ORDER BY follower_id DESC LIMIT 1`
+func (p *UserRepo) MostPopularUser(ctx context.Context, since time.Time) (*pb.UserResponse, error) {
+	// Ties go to whoever has more followers overall.
+	query := `WITH gained AS (
+	              SELECT following_id AS id, COUNT(*) FILTER (WHERE created_at >= $1) AS recent, COUNT(*) AS total
+	              FROM follows
+	              GROUP BY following_id
+	          )
+	          SELECT u.id, u.email, COALESCE(u.phone, ''), p.first_name, p.last_name, p.username, p.nationality, p.bio, p.created_at,
+	                 p.verified
+	          FROM gained g
+	          JOIN users u ON u.id = g.id AND u.deleted_at = 0
+	          JOIN user_profile p ON p.user_id = u.id AND p.role != 'c-admin'
+	          WHERE g.recent > 0
+	          ORDER BY g.recent DESC, g.total DESC, u.created_at
+	          LIMIT 1`
 
 	var user pb.UserResponse
-	err := p.db.QueryRow(query).Scan(&userID)
+	err := p.db.QueryRowContext(ctx, query, since).Scan(&user.Id, &user.Email, &user.Phone, &user.FirstName, &user.LastName,
+		&user.Username, &user.Nationality, &user.Bio, &user.CreatedAt, &user.Verified)
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, storage.ErrUserNotFound
+		}
 		return nil, fmt.Errorf("failed to get most popular user: %w", err)
 	}
 
-	query1 := `SELECT u.id, u.email, u.phone, p.first_name, p.last_name, p.username, p.nationality, p.bio, p.created_at
-	          FROM users u
-	          JOIN user_profile p ON u.id = p.user_id
-	          WHERE u.id = $1 and role != 'c-admin' and u.deleted_at = 0`
+	return &user, nil
+}
 
-	row := p.db.QueryRow(query1, userID)
-	err = row.Scan(&user.Id, &user.Email, &user.Phone, &user.FirstName, &user.LastName, &user.Username, &user.Nationality,
-		&user.Bio, &user.CreatedAt)
+func (p *UserRepo) SuggestUsers(ctx context.Context, userID string, since time.Time, limit int) (*pb.Suggestions, error) {
+	// Candidates are ranked by how many of the people the user follows
+	// follow them, then by whether they follow the user, then by followers
+	// gained since since, which keeps the popularity scan to recent follows.
+	// Total followers are counted for the returned users only.
+	query := `WITH following AS (
+	              SELECT following_id AS id FROM follows WHERE follower_id = $1
+	          ), blocked AS (
+	              SELECT blocked_id AS id FROM user_blocks WHERE blocker_id = $1
+	              UNION
+	              SELECT blocker_id FROM user_blocks WHERE blocked_id = $1
+	          ), mutual AS (
+	              SELECT f.following_id AS id, COUNT(*) AS n
+	              FROM follows f
+	              JOIN following fo ON fo.id = f.follower_id
+	              GROUP BY f.following_id
+	          ), popularity AS (
+	              SELECT following_id AS id, COUNT(*) AS n FROM follows WHERE created_at >= $3 GROUP BY following_id
+	          ), ranked AS (
+	              SELECT u.id, p.username, p.first_name, p.last_name, COALESCE(p.bio, '') AS bio,
+	                     COALESCE(m.n, 0) AS mutual_count,
+	                     COALESCE(pop.n, 0) AS recent_followers,
+	                     EXISTS (SELECT 1 FROM follows WHERE follower_id = u.id AND following_id = $1) AS follows_you,
+	                     u.created_at
+	              FROM users u
+	              JOIN user_profile p ON p.user_id = u.id AND p.role = 'user'
+	              LEFT JOIN mutual m ON m.id = u.id
+	              LEFT JOIN popularity pop ON pop.id = u.id
+	              WHERE u.id != $1 AND u.deleted_at = 0 AND u.delete_after IS NULL
+	                AND u.id NOT IN (SELECT id FROM following)
+	                AND u.id NOT IN (SELECT id FROM blocked)
+	              ORDER BY mutual_count DESC, follows_you DESC, recent_followers DESC, u.created_at DESC
+	              LIMIT $2
+	          )
+	          SELECT r.id, r.username, r.first_name, r.last_name, r.bio, r.mutual_count, fc.n, r.follows_you
+	          FROM ranked r
+	          CROSS JOIN LATERAL (SELECT COUNT(*) AS n FROM follows WHERE following_id = r.id) fc
+	          ORDER BY r.mutual_count DESC, r.follows_you DESC, r.recent_followers DESC, r.created_at DESC`
+
+	rows, err := p.db.QueryContext(ctx, query, userID, limit, since)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, errors.New("user not found")
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := &pb.Suggestions{}
+	for rows.Next() {
+		var s pb.SuggestedUser
+		if err := rows.Scan(&s.Id, &s.Username, &s.FirstName, &s.LastName, &s.Bio, &s.MutualCount, &s.FollowersCount, &s.FollowsYou); err != nil {
+			return nil, err
 		}
+		res.Users = append(res.Users, &s)
+	}
+
+	return res, rows.Err()
+}
+
+func (p *UserRepo) Block(in *pb.BlockReq) (*pb.Void, error) {
+	tx, err := p.db.Beginx()
+	if err != nil {
 		return nil, err
 	}
+	defer tx.Rollback()
 
-	return &user, nil
+	query := `INSERT INTO user_blocks (blocker_id, blocked_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`
+	if _, err := tx.Exec(query, in.BlockerId, in.BlockedId); err != nil {
+		return nil, err
+	}
+
+	// Neither can keep following the other.
+	query = `DELETE FROM follows
+	         WHERE (follower_id = $1 AND following_id = $2) OR (follower_id = $2 AND following_id = $1)`
+	if _, err := tx.Exec(query, in.BlockerId, in.BlockedId); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &pb.Void{}, nil
+}
+
+func (p *UserRepo) Unblock(in *pb.BlockReq) (*pb.Void, error) {
+	query := `DELETE FROM user_blocks WHERE blocker_id = $1 AND blocked_id = $2`
+
+	res, err := p.db.Exec(query, in.BlockerId, in.BlockedId)
+	if err != nil {
+		return nil, err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return nil, storage.ErrNotBlocked
+	}
+
+	return &pb.Void{}, nil
+}
+
+func (p *UserRepo) ScheduleDeletion(ctx context.Context, userID string, at time.Time) (time.Time, error) {
+	query := `UPDATE users SET delete_after = COALESCE(delete_after, $2), updated_at = now()
+	          WHERE id = $1 AND deleted_at = 0
+	          RETURNING delete_after`
+
+	var deleteAfter time.Time
+	err := p.db.QueryRowContext(ctx, query, userID, at).Scan(&deleteAfter)
+	if errors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, storage.ErrUserNotFound
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return deleteAfter, nil
+}
+
+func (p *UserRepo) CancelDeletion(ctx context.Context, userID string) error {
+	query := `UPDATE users SET delete_after = NULL, updated_at = now()
+	          WHERE id = $1 AND delete_after IS NOT NULL AND deleted_at = 0`
+
+	res, err := p.db.ExecContext(ctx, query, userID)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return storage.ErrDeletionNotScheduled
+	}
+	return nil
+}
+
+func (p *UserRepo) DueDeletions(ctx context.Context, limit int) ([]string, error) {
+	query := `SELECT id FROM users WHERE delete_after <= now() ORDER BY delete_after LIMIT $1`
+
+	var ids []string
+	if err := p.db.SelectContext(ctx, &ids, query, limit); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (p *UserRepo) PurgeUser(ctx context.Context, userID string) (bool, error) {
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	// The row lock makes a concurrent cancel wait for the purge, or the purge
+	// see the cancel.
+	var id string
+	query := `SELECT id FROM users WHERE id = $1 AND delete_after <= now() FOR UPDATE`
+	err = tx.QueryRowContext(ctx, query, userID).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	queries := []string{
+		`DELETE FROM follows WHERE follower_id = $1 OR following_id = $1`,
+		`DELETE FROM user_blocks WHERE blocker_id = $1 OR blocked_id = $1`,
+		`DELETE FROM username_history WHERE user_id = $1`,
+		`DELETE FROM user_identities WHERE user_id = $1`,
+		`DELETE FROM recovery_codes WHERE user_id = $1`,
+		`DELETE FROM two_factor WHERE user_id = $1`,
+		`DELETE FROM user_profile WHERE user_id = $1`,
+		`DELETE FROM users WHERE id = $1`,
+	}
+	for _, q := range queries {
+		if _, err := tx.ExecContext(ctx, q, userID); err != nil {
+			return false, err
+		}
+	}
+
+	msg, err := events.NewUserEvent(events.UserDeleted, events.UserEvent{UserID: userID})
+	if err != nil {
+		return false, err
+	}
+	if err := outbox.Add(ctx, tx, msg); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+	return true, nil
 }
@@ -2,9 +2,14 @@ package postgres
 
 import (
 	pb "auth-service/genproto/user"
+	"auth-service/storage"
+	"context"
+	"errors"
 	"fmt"
 	"github.com/jmoiron/sqlx"
+	"slices"
 	"testing"
+	"time"
 )
 
 func ConnectUser() (*sqlx.DB, error) {
@@ -271,10 +276,126 @@ func TestMostPopularUser(t *testing.T) {
 	}
 	user := NewUserRepo(db)
 
-	rst := pb.Void{}
-	req, err := user.MostPopularUser(&rst)
+	req, err := user.MostPopularUser(context.Background(), time.Now().Add(-7*24*time.Hour))
 	if err != nil {
 		t.Errorf("Failed to most popular user: %v", err)
 	}
 	fmt.Println(req)
 }
+
+func TestAccountDeletion(t *testing.T) {
+	db, err := ConnectUser()
+	if err != nil {
+		t.Fatalf("Failed to connect to database: %v", err)
+	}
+	user := NewUserRepo(db)
+	ctx := context.Background()
+
+	created, err := user.Create(&pb.CreateRequest{
+		Email:    "deleted.user@gmail.com",
+		Password: "deleteduser",
+		Phone:    "9997470003",
+		Username: "deleteduser",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+
+	if _, err := user.ScheduleDeletion(ctx, created.Id, time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("Failed to schedule deletion: %v", err)
+	}
+	if err := user.CancelDeletion(ctx, created.Id); err != nil {
+		t.Fatalf("Failed to cancel deletion: %v", err)
+	}
+	if err := user.CancelDeletion(ctx, created.Id); !errors.Is(err, storage.ErrDeletionNotScheduled) {
+		t.Fatalf("Expected ErrDeletionNotScheduled, got %v", err)
+	}
+
+	if _, err := user.ScheduleDeletion(ctx, created.Id, time.Now().Add(-time.Second)); err != nil {
+		t.Fatalf("Failed to schedule deletion: %v", err)
+	}
+	due, err := user.DueDeletions(ctx, 100)
+	if err != nil {
+		t.Fatalf("Failed to list due deletions: %v", err)
+	}
+	if !slices.Contains(due, created.Id) {
+		t.Fatalf("Expected %s to be due, got %v", created.Id, due)
+	}
+
+	purged, err := user.PurgeUser(ctx, created.Id)
+	if err != nil || !purged {
+		t.Fatalf("Failed to purge user: %v, %v", purged, err)
+	}
+	if _, err := user.GetProfile(&pb.Id{UserId: created.Id}); err == nil {
+		t.Fatal("Purged user still has a profile")
+	}
+	if purged, _ := user.PurgeUser(ctx, created.Id); purged {
+		t.Fatal("User purged twice")
+	}
+}
+
+func TestSuggestUsers(t *testing.T) {
+	db, err := ConnectUser()
+	if err != nil {
+		t.Fatalf("Failed to connect to database: %v", err)
+	}
+	user := NewUserRepo(db)
+	ctx := context.Background()
+
+	// me follows friend, who follows both candidate and blocked.
+	ids := map[string]string{}
+	for i, name := range []string{"suggestme", "suggestfriend", "suggestcandidate", "suggestblocked"} {
+		created, err := user.Create(&pb.CreateRequest{
+			Email:    name + "@gmail.com",
+			Password: name,
+			Phone:    fmt.Sprintf("99974710%02d", i),
+			Username: name,
+		})
+		if err != nil {
+			t.Fatalf("Failed to create user: %v", err)
+		}
+		ids[name] = created.Id
+	}
+	follows := [][2]string{
+		{"suggestme", "suggestfriend"},
+		{"suggestfriend", "suggestcandidate"},
+		{"suggestfriend", "suggestblocked"},
+	}
+	for _, f := range follows {
+		if _, err := user.Follow(&pb.FollowReq{FollowerId: ids[f[0]], FollowingId: ids[f[1]]}); err != nil {
+			t.Fatalf("Failed to follow: %v", err)
+		}
+	}
+	if _, err := user.Block(&pb.BlockReq{BlockerId: ids["suggestblocked"], BlockedId: ids["suggestme"]}); err != nil {
+		t.Fatalf("Failed to block: %v", err)
+	}
+
+	res, err := user.SuggestUsers(ctx, ids["suggestme"], time.Now().Add(-time.Hour), 20)
+	if err != nil {
+		t.Fatalf("Failed to suggest users: %v", err)
+	}
+	if len(res.Users) == 0 || res.Users[0].Id != ids["suggestcandidate"] || res.Users[0].MutualCount != 1 {
+		t.Fatalf("Expected suggestcandidate with one mutual first, got %v", res.Users)
+	}
+	for _, s := range res.Users {
+		if s.Id == ids["suggestfriend"] || s.Id == ids["suggestblocked"] {
+			t.Fatalf("Suggested followed or blocked user %s", s.Username)
+		}
+	}
+
+	if _, err := user.Follow(&pb.FollowReq{FollowerId: ids["suggestme"], FollowingId: ids["suggestblocked"]}); err == nil {
+		t.Fatal("Followed a user who blocked them")
+	}
+
+	// Following again keeps one follow.
+	if _, err := user.Follow(&pb.FollowReq{FollowerId: ids["suggestme"], FollowingId: ids["suggestfriend"]}); err != nil {
+		t.Fatalf("Failed to follow again: %v", err)
+	}
+	count, err := user.GetUserFollowers(&pb.Id{UserId: ids["suggestfriend"]})
+	if err != nil {
+		t.Fatalf("Failed to count followers: %v", err)
+	}
+	if count.Count != 1 {
+		t.Fatalf("Expected one follower after following twice, got %d", count.Count)
+	}
+}
@@ -0,0 +1,97 @@
+package postgres
+
+import (
+	"auth-service/storage"
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// usernameAccounts selects the accounts with the current or former username
+// $1. A current username wins over a former one.
+const usernameAccounts = `SELECT user_id, 0 AS former FROM user_profile WHERE lower(username) = lower($1)
+	UNION ALL
+	SELECT user_id, 1 FROM username_history WHERE lower(username) = lower($1)`
+
+// usernameAccount selects the one account a username resolves to.
+const usernameAccount = `SELECT user_id FROM (` + usernameAccounts + `) o ORDER BY former LIMIT 1`
+
+func (p *UserRepo) UsernameState(ctx context.Context, userID string) (string, time.Time, error) {
+	query := `SELECT p.username, p.username_changed_at
+	          FROM user_profile p
+	          JOIN users u ON u.id = p.user_id AND u.deleted_at = 0
+	          WHERE p.user_id = $1`
+
+	var (
+		username  string
+		changedAt sql.NullTime
+	)
+	err := p.db.QueryRowContext(ctx, query, userID).Scan(&username, &changedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", time.Time{}, storage.ErrUserNotFound
+	}
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return username, changedAt.Time, nil
+}
+
+func (p *UserRepo) UsernameOwner(ctx context.Context, username string) (string, string, error) {
+	query := `SELECT o.user_id, p.username
+	          FROM (` + usernameAccounts + `) o
+	          JOIN user_profile p ON p.user_id = o.user_id
+	          ORDER BY o.former
+	          LIMIT 1`
+
+	var userID, current string
+	err := p.db.QueryRowContext(ctx, query, username).Scan(&userID, &current)
+	if err != nil {
+		return "", "", err
+	}
+	return userID, current, nil
+}
+
+func (p *UserRepo) ChangeUsername(ctx context.Context, userID, username string) error {
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var old string
+	err = tx.QueryRowContext(ctx, `SELECT username FROM user_profile WHERE user_id = $1 FOR UPDATE`, userID).Scan(&old)
+	if errors.Is(err, sql.ErrNoRows) {
+		return storage.ErrUserNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	// Taking back a former username removes it from the history.
+	query := `DELETE FROM username_history WHERE lower(username) = lower($1) AND user_id = $2`
+	if _, err := tx.ExecContext(ctx, query, username, userID); err != nil {
+		return err
+	}
+
+	query = `INSERT INTO username_history (username, user_id) VALUES ($1, $2)
+	         ON CONFLICT (username) DO UPDATE SET changed_at = now()
+	         WHERE username_history.user_id = excluded.user_id`
+	if _, err := tx.ExecContext(ctx, query, old, userID); err != nil {
+		return err
+	}
+
+	query = `UPDATE user_profile SET username = $2, username_changed_at = now(), updated_at = now() WHERE user_id = $1`
+	if _, err := tx.ExecContext(ctx, query, userID, username); err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return storage.ErrUsernameTaken
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,49 @@
+package postgres
+
+import (
+	pb "auth-service/genproto/user"
+	"context"
+	"testing"
+)
+
+func TestChangeUsername(t *testing.T) {
+	db, err := ConnectUser()
+	if err != nil {
+		t.Fatalf("Failed to connect to database: %v", err)
+	}
+	user := NewUserRepo(db)
+	ctx := context.Background()
+
+	created, err := user.Create(&pb.CreateRequest{Email: "renamed@gmail.com", Password: "renamed", Phone: "9997473001", Username: "oldname"})
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+
+	if err := user.ChangeUsername(ctx, created.Id, "newname"); err != nil {
+		t.Fatalf("Failed to change username: %v", err)
+	}
+
+	current, changedAt, err := user.UsernameState(ctx, created.Id)
+	if err != nil {
+		t.Fatalf("Failed to get username: %v", err)
+	}
+	if current != "newname" || changedAt.IsZero() {
+		t.Fatalf("Expected newname with a change time, got %s at %v", current, changedAt)
+	}
+
+	owner, resolved, err := user.UsernameOwner(ctx, "OldName")
+	if err != nil {
+		t.Fatalf("Failed to resolve former username: %v", err)
+	}
+	if owner != created.Id || resolved != "newname" {
+		t.Fatalf("Expected oldname to resolve to %s/newname, got %s/%s", created.Id, owner, resolved)
+	}
+
+	// Taking the old name back drops it from the history.
+	if err := user.ChangeUsername(ctx, created.Id, "oldname"); err != nil {
+		t.Fatalf("Failed to take back username: %v", err)
+	}
+	if _, resolved, _ := user.UsernameOwner(ctx, "newname"); resolved != "oldname" {
+		t.Fatalf("Expected newname to resolve to oldname, got %s", resolved)
+	}
+}
@@ -0,0 +1,195 @@
+package redis
+
+import (
+	"auth-service/pkg/models"
+	"auth-service/storage"
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// failureWindow is how long failures are remembered after the last one.
+	failureWindow = 15 * time.Minute
+
+	// After delayAfter failures an account must wait before the next try,
+	// doubling with every failure up to maxDelay.
+	delayAfter = 3
+	baseDelay  = time.Second
+	maxDelay   = time.Minute
+
+	// lockAfter failures lock the account for lockoutTTL.
+	lockAfter  = 10
+	lockoutTTL = 15 * time.Minute
+
+	// ipLimit failures from one IP within failureWindow block it, whatever
+	// accounts it tries.
+	ipLimit = 50
+
+	// maxSecurityEvents is how many security events are kept for admins.
+	maxSecurityEvents = 1000
+	securityEventsKey = "security:events"
+)
+
+func accountFailuresKey(account string) string {
+	return "login:fail:account:" + account
+}
+
+func ipFailuresKey(ip string) string {
+	return "login:fail:ip:" + ip
+}
+
+func accountDelayKey(account string) string {
+	return "login:delay:" + account
+}
+
+func accountLockKey(account string) string {
+	return "login:lock:" + account
+}
+
+func unlockTokenKey(tokenHash string) string {
+	return "login:unlock:" + tokenHash
+}
+
+func (r *RedisStorage) LoginStatus(ctx context.Context, account, ip string) (models.LoginStatus, error) {
+	var lock, delay, ipWindow *redis.DurationCmd
+	var ipFailures *redis.StringCmd
+	_, err := r.rdb.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		if account != "" {
+			lock = pipe.PTTL(ctx, accountLockKey(account))
+			delay = pipe.PTTL(ctx, accountDelayKey(account))
+		}
+		ipFailures = pipe.Get(ctx, ipFailuresKey(ip))
+		ipWindow = pipe.PTTL(ctx, ipFailuresKey(ip))
+		return nil
+	})
+	if err != nil && err != redis.Nil {
+		return models.LoginStatus{}, errors.Wrap(err, "failed to get login status from Redis")
+	}
+
+	if lock != nil && lock.Val() > 0 {
+		return models.LoginStatus{Locked: true, RetryAfter: lock.Val()}, nil
+	}
+	if n, _ := ipFailures.Int64(); n >= ipLimit {
+		return models.LoginStatus{RetryAfter: max(ipWindow.Val(), time.Second)}, nil
+	}
+	if delay != nil && delay.Val() > 0 {
+		return models.LoginStatus{RetryAfter: delay.Val()}, nil
+	}
+
+	return models.LoginStatus{}, nil
+}
+
+func (r *RedisStorage) RecordLoginFailure(ctx context.Context, account, ip string) (bool, error) {
+	var failures *redis.IntCmd
+	_, err := r.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		if account != "" {
+			failures = pipe.Incr(ctx, accountFailuresKey(account))
+			pipe.Expire(ctx, accountFailuresKey(account), failureWindow)
+		}
+		pipe.Incr(ctx, ipFailuresKey(ip))
+		pipe.Expire(ctx, ipFailuresKey(ip), failureWindow)
+		return nil
+	})
+	if err != nil {
+		return false, errors.Wrap(err, "failed to record login failure in Redis")
+	}
+	if failures == nil {
+		return false, nil
+	}
+
+	n := failures.Val()
+	if n >= lockAfter {
+		// SETNX so a locked account is only reported as newly locked once.
+		locked, err := r.rdb.SetNX(ctx, accountLockKey(account), 1, lockoutTTL).Result()
+		if err != nil {
+			return false, errors.Wrap(err, "failed to lock account in Redis")
+		}
+		return locked, nil
+	}
+
+	if n >= delayAfter {
+		delay := baseDelay << (n - delayAfter)
+		if delay > maxDelay {
+			delay = maxDelay
+		}
+		if err := r.rdb.Set(ctx, accountDelayKey(account), 1, delay).Err(); err != nil {
+			return false, errors.Wrap(err, "failed to delay account in Redis")
+		}
+	}
+
+	return false, nil
+}
+
+func (r *RedisStorage) ResetLoginFailures(ctx context.Context, account string) error {
+	err := r.rdb.Del(ctx, accountFailuresKey(account), accountDelayKey(account), accountLockKey(account)).Err()
+	if err != nil {
+		return errors.Wrap(err, "failed to reset login failures in Redis")
+	}
+
+	return nil
+}
+
+func (r *RedisStorage) SetUnlockToken(ctx context.Context, tokenHash, account string, ttl time.Duration) error {
+	if err := r.rdb.Set(ctx, unlockTokenKey(tokenHash), account, ttl).Err(); err != nil {
+		return errors.Wrap(err, "failed to set unlock token in Redis")
+	}
+
+	return nil
+}
+
+func (r *RedisStorage) Unlock(ctx context.Context, tokenHash string) (string, error) {
+	account, err := r.rdb.GetDel(ctx, unlockTokenKey(tokenHash)).Result()
+	if err == redis.Nil {
+		return "", storage.ErrUnlockTokenNotFound
+	}
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get unlock token from Redis")
+	}
+
+	if err := r.ResetLoginFailures(ctx, account); err != nil {
+		return "", err
+	}
+
+	return account, nil
+}
+
+func (r *RedisStorage) RecordSecurityEvent(ctx context.Context, event models.SecurityEvent) error {
+	info, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.LPush(ctx, securityEventsKey, info)
+		pipe.LTrim(ctx, securityEventsKey, 0, maxSecurityEvents-1)
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to record security event in Redis")
+	}
+
+	return nil
+}
+
+func (r *RedisStorage) SecurityEvents(ctx context.Context, limit int64) ([]models.SecurityEvent, error) {
+	res, err := r.rdb.LRange(ctx, securityEventsKey, 0, limit-1).Result()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get security events from Redis")
+	}
+
+	events := make([]models.SecurityEvent, 0, len(res))
+	for _, info := range res {
+		var event models.SecurityEvent
+		if err := json.Unmarshal([]byte(info), &event); err != nil {
+			r.log.Error("Skipping malformed security event", "error", err)
+			continue
+		}
+		events = append(events, event)
+	}
+
+	return events, nil
+}
@@ -0,0 +1,117 @@
+package redis
+
+import (
+	"auth-service/storage"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func newTestStorage(t *testing.T) (*RedisStorage, *miniredis.Miniredis) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+	return NewRedisStorage(rdb, slog.New(slog.NewTextHandler(io.Discard, nil))), mr
+}
+
+func TestLoginFailuresDelayThenLock(t *testing.T) {
+	ctx := context.Background()
+	r, mr := newTestStorage(t)
+
+	for n := 1; n <= lockAfter; n++ {
+		locked, err := r.RecordLoginFailure(ctx, "u1", "203.0.113.1")
+		if err != nil {
+			t.Fatalf("RecordLoginFailure: %v", err)
+		}
+		if locked != (n == lockAfter) {
+			t.Fatalf("failure %d reported locked = %v", n, locked)
+		}
+
+		status, err := r.LoginStatus(ctx, "u1", "203.0.113.1")
+		if err != nil {
+			t.Fatalf("LoginStatus: %v", err)
+		}
+		switch {
+		case n < delayAfter:
+			if status.Locked || status.RetryAfter != 0 {
+				t.Fatalf("failure %d: status %+v, want no wait", n, status)
+			}
+		case n < lockAfter:
+			want := min(baseDelay<<(n-delayAfter), maxDelay)
+			if status.Locked || status.RetryAfter != want {
+				t.Fatalf("failure %d: status %+v, want a wait of %v", n, status, want)
+			}
+		default:
+			if !status.Locked || status.RetryAfter != lockoutTTL {
+				t.Fatalf("failure %d: status %+v, want locked for %v", n, status, lockoutTTL)
+			}
+		}
+	}
+
+	// A lockout is only reported once.
+	locked, err := r.RecordLoginFailure(ctx, "u1", "203.0.113.1")
+	if err != nil || locked {
+		t.Fatalf("failure after lock reported locked = %v, %v", locked, err)
+	}
+
+	mr.FastForward(lockoutTTL)
+	status, err := r.LoginStatus(ctx, "u1", "203.0.113.1")
+	if err != nil || status.Locked {
+		t.Fatalf("status after lockout = %+v, %v; want unlocked", status, err)
+	}
+}
+
+func TestLoginFailuresPerIP(t *testing.T) {
+	ctx := context.Background()
+	r, _ := newTestStorage(t)
+
+	// Failures without an account only count against the IP.
+	for n := 0; n < ipLimit; n++ {
+		if _, err := r.RecordLoginFailure(ctx, "", "203.0.113.1"); err != nil {
+			t.Fatalf("RecordLoginFailure: %v", err)
+		}
+	}
+
+	status, err := r.LoginStatus(ctx, "u1", "203.0.113.1")
+	if err != nil || status.RetryAfter <= 0 {
+		t.Fatalf("status from blocked IP = %+v, %v; want a wait", status, err)
+	}
+	status, err = r.LoginStatus(ctx, "u1", "198.51.100.1")
+	if err != nil || status.RetryAfter != 0 {
+		t.Fatalf("status from another IP = %+v, %v; want no wait", status, err)
+	}
+}
+
+func TestUnlock(t *testing.T) {
+	ctx := context.Background()
+	r, _ := newTestStorage(t)
+
+	for n := 0; n < lockAfter; n++ {
+		if _, err := r.RecordLoginFailure(ctx, "u1", "203.0.113.1"); err != nil {
+			t.Fatalf("RecordLoginFailure: %v", err)
+		}
+	}
+	if err := r.SetUnlockToken(ctx, "hash", "u1", time.Minute); err != nil {
+		t.Fatalf("SetUnlockToken: %v", err)
+	}
+
+	account, err := r.Unlock(ctx, "hash")
+	if err != nil || account != "u1" {
+		t.Fatalf("Unlock = %q, %v; want u1", account, err)
+	}
+	status, err := r.LoginStatus(ctx, "u1", "203.0.113.1")
+	if err != nil || status.Locked || status.RetryAfter != 0 {
+		t.Fatalf("status after unlock = %+v, %v; want no wait", status, err)
+	}
+
+	if _, err := r.Unlock(ctx, "hash"); !errors.Is(err, storage.ErrUnlockTokenNotFound) {
+		t.Fatalf("second Unlock: got %v, want ErrUnlockTokenNotFound", err)
+	}
+}
@@ -0,0 +1,93 @@
+package redis
+
+import (
+	"auth-service/pkg/models"
+	"auth-service/storage"
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/redis/go-redis/v9"
+)
+
+// maxChallengeAttempts is how many codes can be tried against one challenge
+// before the user has to enter their password again.
+const maxChallengeAttempts = 5
+
+func challengeKey(tokenHash string) string {
+	return "mfa:challenge:" + tokenHash
+}
+
+func usedCodeKey(userID, code string) string {
+	return "mfa:used:" + userID + ":" + code
+}
+
+func (r *RedisStorage) CreateChallenge(ctx context.Context, tokenHash string, user models.LoginResponse, ttl time.Duration) error {
+	user.Password = ""
+	info, err := json.Marshal(user)
+	if err != nil {
+		return err
+	}
+
+	key := challengeKey(tokenHash)
+	_, err = r.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, key, "user", info, "attempts", 0)
+		pipe.Expire(ctx, key, ttl)
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to create mfa challenge in Redis")
+	}
+
+	return nil
+}
+
+func (r *RedisStorage) AttemptChallenge(ctx context.Context, tokenHash string) (models.LoginResponse, error) {
+	key := challengeKey(tokenHash)
+
+	var info *redis.StringCmd
+	var attempts *redis.IntCmd
+	_, err := r.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		info = pipe.HGet(ctx, key, "user")
+		attempts = pipe.HIncrBy(ctx, key, "attempts", 1)
+		return nil
+	})
+	if err == redis.Nil {
+		// HINCRBY recreated the key without a TTL.
+		r.rdb.Del(ctx, key)
+		return models.LoginResponse{}, storage.ErrChallengeNotFound
+	}
+	if err != nil {
+		return models.LoginResponse{}, errors.Wrap(err, "failed to get mfa challenge from Redis")
+	}
+
+	if attempts.Val() > maxChallengeAttempts {
+		r.rdb.Del(ctx, key)
+		return models.LoginResponse{}, storage.ErrChallengeNotFound
+	}
+
+	var user models.LoginResponse
+	if err := json.Unmarshal([]byte(info.Val()), &user); err != nil {
+		return models.LoginResponse{}, err
+	}
+
+	return user, nil
+}
+
+func (r *RedisStorage) DeleteChallenge(ctx context.Context, tokenHash string) error {
+	if err := r.rdb.Del(ctx, challengeKey(tokenHash)).Err(); err != nil {
+		return errors.Wrap(err, "failed to delete mfa challenge from Redis")
+	}
+
+	return nil
+}
+
+func (r *RedisStorage) MarkCodeUsed(ctx context.Context, userID, code string, ttl time.Duration) (bool, error) {
+	ok, err := r.rdb.SetNX(ctx, usedCodeKey(userID, code), 1, ttl).Result()
+	if err != nil {
+		return false, errors.Wrap(err, "failed to mark totp code used in Redis")
+	}
+
+	return ok, nil
+}
@@ -0,0 +1,46 @@
+package redis
+
+import (
+	"auth-service/pkg/models"
+	"auth-service/storage"
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/redis/go-redis/v9"
+)
+
+func oidcStateKey(stateHash string) string {
+	return "oidc:state:" + stateHash
+}
+
+func (r *RedisStorage) SaveOIDCState(ctx context.Context, stateHash string, state models.OIDCState, ttl time.Duration) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	if err := r.rdb.Set(ctx, oidcStateKey(stateHash), data, ttl).Err(); err != nil {
+		return errors.Wrap(err, "failed to save oidc state in Redis")
+	}
+
+	return nil
+}
+
+func (r *RedisStorage) TakeOIDCState(ctx context.Context, stateHash string) (models.OIDCState, error) {
+	data, err := r.rdb.GetDel(ctx, oidcStateKey(stateHash)).Bytes()
+	if err == redis.Nil {
+		return models.OIDCState{}, storage.ErrOIDCStateNotFound
+	}
+	if err != nil {
+		return models.OIDCState{}, errors.Wrap(err, "failed to get oidc state from Redis")
+	}
+
+	var state models.OIDCState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return models.OIDCState{}, err
+	}
+
+	return state, nil
+}
@@ -2,6 +2,7 @@ package redis
 
 import (
 	"auth-service/pkg/models"
+	"auth-service/storage"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -73,6 +74,9 @@ func (r *RedisStorage) SetRegister(ctx context.Context, in models.RegisterReques
 func (r *RedisStorage) GetRegister(ctx context.Context, email string) (models.RegisterRequest1, error) {
 
 	res, err := r.rdb.Get(ctx, email).Result()
+	if err == redis.Nil {
+		return models.RegisterRequest1{}, storage.ErrRegistrationNotFound
+	}
 	if err != nil {
 		r.log.Error("Error in GetRegister", "err", err)
 		return models.RegisterRequest1{}, err
@@ -88,3 +92,11 @@ func (r *RedisStorage) GetRegister(ctx context.Context, email string) (models.Re
 
 	return response, nil
 }
+
+func (r *RedisStorage) DeleteRegister(ctx context.Context, email string) error {
+	if err := r.rdb.Del(ctx, email).Err(); err != nil {
+		return errors.Wrap(err, "failed to delete register from Redis")
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package redis
+
+import (
+	"auth-service/storage"
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// resetRequestLimit is how many reset emails one address can ask for
+	// within resetRequestWindow.
+	resetRequestLimit  = 3
+	resetRequestWindow = time.Hour
+)
+
+func resetTokenKey(tokenHash string) string {
+	return "reset:token:" + tokenHash
+}
+
+func resetUserKey(userID string) string {
+	return "reset:user:" + userID
+}
+
+// resetRateKey takes the email as normalized by the service.
+func resetRateKey(email string) string {
+	return "reset:rate:" + email
+}
+
+func (r *RedisStorage) AllowResetRequest(ctx context.Context, email string) (bool, error) {
+	key := resetRateKey(email)
+
+	var count *redis.IntCmd
+	_, err := r.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		count = pipe.Incr(ctx, key)
+		pipe.ExpireNX(ctx, key, resetRequestWindow)
+		return nil
+	})
+	if err != nil {
+		return false, errors.Wrap(err, "failed to count reset requests in Redis")
+	}
+
+	return count.Val() <= resetRequestLimit, nil
+}
+
+func (r *RedisStorage) SetResetToken(ctx context.Context, userID, tokenHash string, ttl time.Duration) error {
+	previous, err := r.rdb.GetSet(ctx, resetUserKey(userID), tokenHash).Result()
+	if err != nil && err != redis.Nil {
+		return errors.Wrap(err, "failed to set reset token in Redis")
+	}
+
+	_, err = r.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		if previous != "" {
+			pipe.Del(ctx, resetTokenKey(previous))
+		}
+		pipe.Expire(ctx, resetUserKey(userID), ttl)
+		pipe.Set(ctx, resetTokenKey(tokenHash), userID, ttl)
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to set reset token in Redis")
+	}
+
+	return nil
+}
+
+func (r *RedisStorage) TakeResetToken(ctx context.Context, tokenHash string) (string, error) {
+	userID, err := r.rdb.GetDel(ctx, resetTokenKey(tokenHash)).Result()
+	if err == redis.Nil {
+		return "", storage.ErrResetTokenNotFound
+	}
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get reset token from Redis")
+	}
+
+	if err := r.rdb.Del(ctx, resetUserKey(userID)).Err(); err != nil {
+		r.log.Error("Failed to delete reset token of user", "user_id", userID, "error", err)
+	}
+
+	return userID, nil
+}
@@ -0,0 +1,177 @@
+package redis
+
+import (
+	"auth-service/pkg/models"
+	"auth-service/storage"
+	"context"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/redis/go-redis/v9"
+)
+
+func familyKey(familyID string) string {
+	return "refresh:family:" + familyID
+}
+
+// sessionsKey is the set of family ids of a user. Families expire on their
+// own, so the set may name dead ones; ListSessions prunes them.
+func sessionsKey(userID string) string {
+	return "sessions:user:" + userID
+}
+
+func (r *RedisStorage) CreateFamily(ctx context.Context, familyID, userID, tokenID string, client models.Client, ttl time.Duration) error {
+	key := familyKey(familyID)
+	now := time.Now().Unix()
+
+	_, err := r.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, key,
+			"user_id", userID,
+			"current", tokenID,
+			"user_agent", client.UserAgent,
+			"ip", client.IP,
+			"created_at", now,
+			"last_seen", now,
+		)
+		pipe.Expire(ctx, key, ttl)
+		pipe.SAdd(ctx, sessionsKey(userID), familyID)
+		pipe.Expire(ctx, sessionsKey(userID), ttl)
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to create refresh token family in Redis")
+	}
+
+	return nil
+}
+
+// rotateScript swaps the current token of a family in one step, so two
+// concurrent refreshes with the same token cannot both succeed. It returns 1
+// on success, 0 if the family does not exist and -1 on reuse, in which case
+// the family is deleted.
+var rotateScript = redis.NewScript(`
+local current = redis.call('HGET', KEYS[1], 'current')
+if not current then
+	return 0
+end
+if current ~= ARGV[1] then
+	redis.call('DEL', KEYS[1])
+	redis.call('SREM', KEYS[2], ARGV[6])
+	return -1
+end
+redis.call('HSET', KEYS[1], 'current', ARGV[2], 'ip', ARGV[4], 'last_seen', ARGV[5])
+redis.call('PEXPIRE', KEYS[1], ARGV[3])
+redis.call('PEXPIRE', KEYS[2], ARGV[3])
+return 1
+`)
+
+func (r *RedisStorage) RotateToken(ctx context.Context, familyID, userID, tokenID, newTokenID, ip string, ttl time.Duration) error {
+	keys := []string{familyKey(familyID), sessionsKey(userID)}
+	res, err := rotateScript.Run(ctx, r.rdb, keys, tokenID, newTokenID, ttl.Milliseconds(), ip, time.Now().Unix(), familyID).Int()
+	if err != nil {
+		return errors.Wrap(err, "failed to rotate refresh token in Redis")
+	}
+
+	switch res {
+	case 0:
+		return storage.ErrTokenFamilyNotFound
+	case -1:
+		r.log.Warn("Refresh token reused, family revoked", "family_id", familyID)
+		return storage.ErrTokenReused
+	}
+
+	return nil
+}
+
+func (r *RedisStorage) RevokeFamily(ctx context.Context, familyID string) error {
+	if err := r.rdb.Del(ctx, familyKey(familyID)).Err(); err != nil {
+		return errors.Wrap(err, "failed to revoke refresh token family in Redis")
+	}
+
+	return nil
+}
+
+func (r *RedisStorage) ListSessions(ctx context.Context, userID string) ([]models.Session, error) {
+	ids, err := r.rdb.SMembers(ctx, sessionsKey(userID)).Result()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list sessions in Redis")
+	}
+
+	cmds := make([]*redis.MapStringStringCmd, len(ids))
+	_, err = r.rdb.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, id := range ids {
+			cmds[i] = pipe.HGetAll(ctx, familyKey(id))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get sessions from Redis")
+	}
+
+	var (
+		sessions []models.Session
+		dead     []interface{}
+	)
+	for i, cmd := range cmds {
+		f := cmd.Val()
+		if len(f) == 0 || f["user_id"] != userID {
+			dead = append(dead, ids[i])
+			continue
+		}
+		sessions = append(sessions, models.Session{
+			ID:        ids[i],
+			UserAgent: f["user_agent"],
+			IP:        f["ip"],
+			CreatedAt: unixString(f["created_at"]),
+			LastSeen:  unixString(f["last_seen"]),
+		})
+	}
+
+	if len(dead) > 0 {
+		if err := r.rdb.SRem(ctx, sessionsKey(userID), dead...).Err(); err != nil {
+			r.log.Warn("Failed to prune expired sessions", "user_id", userID, "error", err)
+		}
+	}
+
+	// RFC 3339 strings in UTC sort by time.
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].LastSeen > sessions[j].LastSeen
+	})
+
+	return sessions, nil
+}
+
+// deleteSessionScript deletes a family only if it belongs to the user, so one
+// user cannot end the session of another by guessing its id.
+var deleteSessionScript = redis.NewScript(`
+if redis.call('HGET', KEYS[1], 'user_id') ~= ARGV[1] then
+	return 0
+end
+redis.call('DEL', KEYS[1])
+redis.call('SREM', KEYS[2], ARGV[2])
+return 1
+`)
+
+func (r *RedisStorage) DeleteSession(ctx context.Context, userID, familyID string) error {
+	keys := []string{familyKey(familyID), sessionsKey(userID)}
+	res, err := deleteSessionScript.Run(ctx, r.rdb, keys, userID, familyID).Int()
+	if err != nil {
+		return errors.Wrap(err, "failed to delete session in Redis")
+	}
+	if res == 0 {
+		return storage.ErrSessionNotFound
+	}
+
+	return nil
+}
+
+// unixString formats unix seconds stored in a hash as RFC 3339.
+func unixString(sec string) string {
+	n, err := strconv.ParseInt(sec, 10, 64)
+	if err != nil {
+		return ""
+	}
+	return time.Unix(n, 0).UTC().Format(time.RFC3339)
+}
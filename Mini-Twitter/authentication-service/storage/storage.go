@@ -3,12 +3,66 @@ package storage
 import (
 	pb "auth-service/genproto/user"
 	"auth-service/pkg/models"
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	// ErrTokenFamilyNotFound means the family has expired or was revoked.
+	ErrTokenFamilyNotFound = errors.New("refresh token family not found")
+	// ErrTokenReused means a refresh token that was already rotated has been
+	// presented again. The family is revoked when this is returned.
+	ErrTokenReused = errors.New("refresh token reuse detected")
+	// ErrOIDCStateNotFound means the login state is unknown, expired or
+	// already used.
+	ErrOIDCStateNotFound = errors.New("oidc state not found")
+	// ErrRegistrationNotFound means the sign-up has expired or was never
+	// started.
+	ErrRegistrationNotFound = errors.New("pending registration not found")
+	// ErrSessionNotFound means the user has no live session with the id.
+	ErrSessionNotFound = errors.New("session not found")
+	// ErrResetTokenNotFound means the reset token is unknown, expired or
+	// already used.
+	ErrResetTokenNotFound = errors.New("password reset token not found")
+	// ErrChallengeNotFound means the MFA challenge is unknown, expired or
+	// has run out of attempts.
+	ErrChallengeNotFound = errors.New("mfa challenge not found")
+	ErrTwoFactorEnabled  = errors.New("two-factor authentication is already enabled")
+	// ErrUnlockTokenNotFound means the unlock token is unknown, expired or
+	// already used.
+	ErrUnlockTokenNotFound = errors.New("unlock token not found")
+	// ErrDeletionNotScheduled means the user has not asked for their account
+	// to be deleted, or it already has been.
+	ErrDeletionNotScheduled = errors.New("account deletion not scheduled")
+	// ErrUsernameTaken means another account has the username, now or
+	// formerly.
+	ErrUsernameTaken = errors.New("username is taken")
+	// ErrUserNotFound means no live user has the id or username.
+	ErrUserNotFound = errors.New("user not found")
+	// ErrWrongPassword means the current password given does not match.
+	ErrWrongPassword = errors.New("password is incorrect")
+	// ErrCannotFollow means one of the users has blocked the other.
+	ErrCannotFollow = errors.New("cannot follow this user")
+	// ErrNotFollowing means there is no follow relation to remove.
+	ErrNotFollowing = errors.New("no such follow relation exists")
+	// ErrNotBlocked means there is no block to remove.
+	ErrNotBlocked = errors.New("no such block exists")
 )
 
 type AuthStorage interface {
 	Register(in models.RegisterRequest) (models.RegisterResponse, error)
+	// RegisterExternal creates an account for a login at an identity
+	// provider, which has no phone.
+	RegisterExternal(in models.RegisterRequest) (models.RegisterResponse, error)
 	LoginEmail(in models.LoginEmailRequest) (models.LoginResponse, error)
 	LoginUsername(in models.LoginUsernameRequest) (models.LoginResponse, error)
+	// UserIDByEmail matches the lower-cased email case-insensitively. It
+	// returns sql.ErrNoRows if no active user has the email.
+	UserIDByEmail(email string) (string, error)
+	// UserByID returns sql.ErrNoRows if no active user has the id.
+	UserByID(id string) (models.LoginResponse, error)
+	ResetPassword(userID, password string) error
 }
 
 type UserStorage interface {
@@ -22,11 +76,196 @@ type UserStorage interface {
 	ListOfFollowers(in *pb.Id) (*pb.Followers, error)
 	ListOfFollowingByUsername(req *pb.Id) (*pb.Followings, error)
 	ListOfFollowersByUsername(req *pb.Id) (*pb.Followers, error)
+	// DeleteUser marks the user deleted and writes the user.deleted event in
+	// the same transaction.
 	DeleteUser(in *pb.Id) (*pb.Void, error)
 
 	Follow(in *pb.FollowReq) (*pb.FollowRes, error)
 	Unfollow(in *pb.FollowReq) (*pb.DFollowRes, error)
 	GetUserFollowers(in *pb.Id) (*pb.Count, error)
 	GetUserFollows(in *pb.Id) (*pb.Count, error)
-	MostPopularUser(in *pb.Void) (*pb.UserResponse, error)
+	// MostPopularUser returns the user who gained the most followers since
+	// since.
+	MostPopularUser(ctx context.Context, since time.Time) (*pb.UserResponse, error)
+	// SuggestUsers returns up to limit users to follow, leaving out those the
+	// user follows or has a block with. Popularity is counted from the
+	// follows since since.
+	SuggestUsers(ctx context.Context, userID string, since time.Time, limit int) (*pb.Suggestions, error)
+	// SearchUsers returns a page of active users whose username or name
+	// matches the query, leaving out those the searcher has a block with.
+	SearchUsers(ctx context.Context, in *pb.SearchReq) (*pb.SearchResults, error)
+	Block(in *pb.BlockReq) (*pb.Void, error)
+	Unblock(in *pb.BlockReq) (*pb.Void, error)
+	// Relationships returns how the viewer relates to each target, in the
+	// order of targetIDs.
+	Relationships(ctx context.Context, viewerID string, targetIDs []string) ([]*pb.Relationship, error)
+	// MutualFollowers returns a page of the people the viewer follows who
+	// follow each target, in the order of targetIDs.
+	MutualFollowers(ctx context.Context, viewerID string, targetIDs []string, limit, offset int) ([]*pb.TargetMutualFollowers, error)
+
+	// UsernameState returns the user's username and when they last changed
+	// it, or the zero time if they never did.
+	UsernameState(ctx context.Context, userID string) (string, time.Time, error)
+	// UsernameOwner returns the account with the current or former username
+	// and its current username, or sql.ErrNoRows.
+	UsernameOwner(ctx context.Context, username string) (userID, current string, err error)
+	// ChangeUsername moves the user's username into the history and sets the
+	// new one, or returns ErrUsernameTaken.
+	ChangeUsername(ctx context.Context, userID, username string) error
+
+	UserRole(ctx context.Context, userID string) (string, error)
+	// SetRole and SetVerified change the user and record the change with its
+	// actor in the audit log, in one transaction.
+	SetRole(ctx context.Context, in *pb.SetRoleReq) (*pb.UserResponse, error)
+	SetVerified(ctx context.Context, in *pb.SetVerifiedReq) (*pb.UserResponse, error)
+	ListAuditLog(ctx context.Context, in *pb.AuditFilter) (*pb.AuditEntries, error)
+
+	// ScheduleDeletion marks the account for deletion at at. Asking again
+	// keeps the time already set.
+	ScheduleDeletion(ctx context.Context, userID string, at time.Time) (time.Time, error)
+	// CancelDeletion clears a scheduled deletion, or returns
+	// ErrDeletionNotScheduled.
+	CancelDeletion(ctx context.Context, userID string) error
+	// DueDeletions returns up to limit users whose deletion time has passed.
+	DueDeletions(ctx context.Context, limit int) ([]string, error)
+	// PurgeUser deletes everything kept about a user whose deletion is due
+	// and writes the user.deleted event in the same transaction. It reports
+	// false when the deletion was cancelled in the meantime.
+	PurgeUser(ctx context.Context, userID string) (bool, error)
+}
+
+// RegistrationStorage keeps sign-ups, keyed by email, until the code emailed
+// to the user is entered.
+type RegistrationStorage interface {
+	SetRegister(ctx context.Context, in models.RegisterRequest1) error
+	// GetRegister returns ErrRegistrationNotFound if no sign-up is pending
+	// for the email.
+	GetRegister(ctx context.Context, email string) (models.RegisterRequest1, error)
+	DeleteRegister(ctx context.Context, email string) error
+}
+
+// TokenStorage tracks refresh token families. A family remembers only the id
+// of its newest token, so any older token of the family is a replay.
+//
+// Each family is also a session of its user, listed with the device it was
+// created on.
+type TokenStorage interface {
+	CreateFamily(ctx context.Context, familyID, userID, tokenID string, client models.Client, ttl time.Duration) error
+	// RotateToken replaces tokenID with newTokenID as the current token of the
+	// family and records ip as its last use. If tokenID is not current, the
+	// family is revoked and ErrTokenReused is returned.
+	RotateToken(ctx context.Context, familyID, userID, tokenID, newTokenID, ip string, ttl time.Duration) error
+	RevokeFamily(ctx context.Context, familyID string) error
+	// ListSessions returns the live families of the user.
+	ListSessions(ctx context.Context, userID string) ([]models.Session, error)
+	// DeleteSession revokes the family if it belongs to the user, and returns
+	// ErrSessionNotFound otherwise.
+	DeleteSession(ctx context.Context, userID, familyID string) error
+}
+
+// RevocationStorage is the denylist of access tokens. The api-gateway reads
+// it on every request, so its keys are part of the contract between the two.
+type RevocationStorage interface {
+	// RevokeToken denies the token with the given jti for ttl, which should
+	// cover the rest of its lifetime.
+	RevokeToken(ctx context.Context, tokenID string, ttl time.Duration) error
+	IsTokenRevoked(ctx context.Context, tokenID string) (bool, error)
+	// RevokeUser denies every token of the user issued before at.
+	RevokeUser(ctx context.Context, userID string, at time.Time) error
+	// UserRevokedAt returns the last time all tokens of the user were
+	// revoked, or the zero time if they never were.
+	UserRevokedAt(ctx context.Context, userID string) (time.Time, error)
+	// RevokeSession denies every access token issued for the session.
+	RevokeSession(ctx context.Context, sessionID string) error
+	IsSessionRevoked(ctx context.Context, sessionID string) (bool, error)
+}
+
+// ResetStorage keeps password reset tokens. Only hashes of the tokens are
+// stored, so reading the store does not let anyone reset a password.
+type ResetStorage interface {
+	// AllowResetRequest counts a reset request for email and reports whether
+	// it is within the rate limit.
+	AllowResetRequest(ctx context.Context, email string) (bool, error)
+	// SetResetToken stores the token hash for the user, replacing any token
+	// issued to them before.
+	SetResetToken(ctx context.Context, userID, tokenHash string, ttl time.Duration) error
+	// TakeResetToken returns the user of the token hash and deletes it, so a
+	// token can be used once.
+	TakeResetToken(ctx context.Context, tokenHash string) (string, error)
+}
+
+// TwoFactorStorage keeps TOTP secrets and recovery codes.
+type TwoFactorStorage interface {
+	// SaveSecret starts or restarts an enrollment. It returns
+	// ErrTwoFactorEnabled if the user has already confirmed one.
+	SaveSecret(ctx context.Context, userID, secret string) error
+	// GetTwoFactor returns sql.ErrNoRows if the user never enrolled.
+	GetTwoFactor(ctx context.Context, userID string) (models.TwoFactor, error)
+	// Enable confirms the enrollment and replaces the recovery codes. It
+	// returns ErrTwoFactorEnabled if the enrollment is already confirmed.
+	Enable(ctx context.Context, userID string, codeHashes []string) error
+	// UseRecoveryCode marks the code used and reports whether it was valid.
+	UseRecoveryCode(ctx context.Context, userID, codeHash string) (bool, error)
+	// Disable removes the secret and recovery codes of the user.
+	Disable(ctx context.Context, userID string) error
+}
+
+// ChallengeStorage keeps the MFA challenges handed out between the password
+// and the code step of a login.
+type ChallengeStorage interface {
+	CreateChallenge(ctx context.Context, tokenHash string, user models.LoginResponse, ttl time.Duration) error
+	// AttemptChallenge counts an attempt at the challenge and returns its
+	// user. Once the attempts run out the challenge is deleted and
+	// ErrChallengeNotFound is returned.
+	AttemptChallenge(ctx context.Context, tokenHash string) (models.LoginResponse, error)
+	DeleteChallenge(ctx context.Context, tokenHash string) error
+	// MarkCodeUsed reports whether the TOTP code was unused for the user and
+	// remembers it for ttl, so a code cannot be replayed.
+	MarkCodeUsed(ctx context.Context, userID, code string, ttl time.Duration) (bool, error)
+}
+
+// LoginGuardStorage counts failed logins and code guesses per account and per
+// client IP, and decides when to slow down or lock out. An empty account only
+// checks or counts the IP.
+type LoginGuardStorage interface {
+	LoginStatus(ctx context.Context, account, ip string) (models.LoginStatus, error)
+	// RecordLoginFailure counts a failure and reports whether it locked the
+	// account, which happens once per lockout.
+	RecordLoginFailure(ctx context.Context, account, ip string) (bool, error)
+	ResetLoginFailures(ctx context.Context, account string) error
+
+	SetUnlockToken(ctx context.Context, tokenHash, account string, ttl time.Duration) error
+	// Unlock lifts the lockout of the account the token was issued for and
+	// returns that account.
+	Unlock(ctx context.Context, tokenHash string) (string, error)
+
+	RecordSecurityEvent(ctx context.Context, event models.SecurityEvent) error
+	SecurityEvents(ctx context.Context, limit int64) ([]models.SecurityEvent, error)
+}
+
+// SigningKeyStorage keeps the private keys access tokens are signed with.
+type SigningKeyStorage interface {
+	ListSigningKeys(ctx context.Context) ([]models.SigningKey, error)
+	// AddSigningKey adds the key unless one active after latest exists, which
+	// means another instance added it first, and reports whether it did.
+	// latest is the zero time when there were no keys.
+	AddSigningKey(ctx context.Context, key models.SigningKey, latest time.Time) (bool, error)
+	DeleteSigningKey(ctx context.Context, id string) error
+}
+
+// IdentityStorage links users to their accounts at external identity
+// providers.
+type IdentityStorage interface {
+	// UserByIdentity returns sql.ErrNoRows if the identity is not linked to
+	// an active user.
+	UserByIdentity(ctx context.Context, provider, subject string) (models.LoginResponse, error)
+	LinkIdentity(ctx context.Context, userID string, identity models.ExternalIdentity) error
+}
+
+// OIDCStateStorage keeps logins at external providers between the redirect
+// to the provider and the callback. States are keyed by hash and can be
+// taken once.
+type OIDCStateStorage interface {
+	SaveOIDCState(ctx context.Context, stateHash string, state models.OIDCState, ttl time.Duration) error
+	TakeOIDCState(ctx context.Context, stateHash string) (models.OIDCState, error)
 }